@@ -6,6 +6,8 @@ import (
 	"orders/model"
 	"os"
 	"time"
+	"utils/currency"
+	"utils/middleware"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -63,4 +65,25 @@ func SyncDB() {
 	db.AutoMigrate(&model.Order{})
 	db.AutoMigrate(&model.OrderItem{})
 	db.AutoMigrate(&model.OrderTracking{})
+	db.AutoMigrate(&model.TaxRule{})
+	db.AutoMigrate(&model.CheckoutSaga{})
+	db.AutoMigrate(&model.CheckoutSagaStep{})
+	db.AutoMigrate(&model.Refund{})
+	db.AutoMigrate(&model.ReturnRequest{})
+	db.AutoMigrate(&model.ReturnItem{})
+	db.AutoMigrate(&model.ReturnPhoto{})
+	db.AutoMigrate(&model.ReturnTracking{})
+	db.AutoMigrate(&middleware.IdempotencyKey{})
+
+	// carts and orders from before currencies existed are in the default one
+	db.Model(&model.CartItem{}).Where("currency = ''").Update("currency", currency.Default())
+	db.Model(&model.Order{}).Where("currency = ''").Update("currency", currency.Default())
+	db.Model(&model.OrderItem{}).Where("base_currency = ''").Update("base_currency", currency.Default())
+
+	// orders from before tax was charged were untaxed
+	db.Model(&model.Order{}).Where("subtotal = 0 AND tax_amount = 0").Update("subtotal", gorm.Expr("total_amount"))
+
+	// order items from before sellers were recorded take theirs from the snapshot
+	db.Model(&model.OrderItem{}).Where("seller_id = 0").
+		Update("seller_id", gorm.Expr("CAST(JSON_UNQUOTE(JSON_EXTRACT(product_snapshot, '$.seller_id')) AS UNSIGNED)"))
 }
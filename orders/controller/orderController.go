@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"orders/model"
 	"orders/service"
@@ -39,6 +40,15 @@ func GetCart(c *gin.Context) {
 			Success: false,
 			Message: err.Error(),
 		})
+		return
+	}
+
+	if err := s.CartLoadProducts(c.Request.Context(), cart, c.Query("currency")); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, &model.CartResponse{
@@ -150,6 +160,9 @@ type CheckoutInput struct {
 	PaymentMethod string `json:"payment_method"`
 	CartID        int    `json:"cart_id"`
 	CartItemIDs   []int  `json:"cart_item_ids"`
+	// Currency is what the order is charged in. It defaults to the currency
+	// the items are listed in when they share one.
+	Currency string `json:"currency"`
 }
 
 func Checkout(c *gin.Context) {
@@ -172,11 +185,12 @@ func Checkout(c *gin.Context) {
 		return
 	}
 
-	s := service.GetTransaction()
+	// CreateOrder runs its own transactions, one per checkout step
+	s := service.GetService()
 	defer func() {
 		r := recover()
 		if r != nil {
-			err := s.Rollback(r)
+			err := s.ErrorCheck(r)
 			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
 				Success: false,
 				Message: err.Error(),
@@ -185,8 +199,7 @@ func Checkout(c *gin.Context) {
 		}
 	}()
 
-	// Only create order once payment is successful
-	order, err := s.CreateOrder(c.Request.Context(), input.CartID, input.CartItemIDs, input.PaymentMethod)
+	order, err := s.CreateOrder(c.Request.Context(), input.CartID, input.CartItemIDs, input.PaymentMethod, input.Currency)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
 			Success: false,
@@ -195,8 +208,6 @@ func Checkout(c *gin.Context) {
 		return
 	}
 
-	s.Commit()
-
 	c.JSON(http.StatusOK, &model.OrderResponse{
 		Success: true,
 		Message: "Checked out successfully",
@@ -300,6 +311,7 @@ func TrackOrder(c *gin.Context) {
 
 type UpdateStatusInput struct {
 	Status string `json:"status"`
+	Note   string `json:"note"`
 }
 
 func UpdateOrderStatus(c *gin.Context) {
@@ -353,27 +365,109 @@ func UpdateOrderStatus(c *gin.Context) {
 		return
 	}
 
-	status, err := s.OrderUpdateStatus(orderID, input.Status)
+	if _, err := s.OrderTransition(c.Request.Context(), orderID, service.OrderStatus(input.Status), user, input.Note); err != nil {
+		s.Rollback(err)
+
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderTransitionForbidden) {
+			code = http.StatusForbidden
+		}
+
+		c.AbortWithStatusJSON(code, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Order status updated successfully",
+	})
+}
+
+// CancelOrder cancels an order on behalf of its buyer.
+func CancelOrder(c *gin.Context) {
+	cancelOrder(c, service.ORDER_ROLE_BUYER)
+}
+
+// SellerCancelOrder cancels an order on behalf of a seller of one of its
+// items. The reason is shown to the buyer.
+func SellerCancelOrder(c *gin.Context) {
+	cancelOrder(c, service.ORDER_ROLE_SELLER)
+}
+
+func cancelOrder(c *gin.Context, as service.OrderRole) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	var input model.CancelOrder
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	order, err := s.OrderCancel(c.Request.Context(), orderID, user, as, input)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+		s.Rollback(err)
+
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderTransitionForbidden) {
+			code = http.StatusForbidden
+		}
+
+		c.AbortWithStatusJSON(code, &model.GlobalResponse{
 			Success: false,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	if !status {
+	if err := s.Commit(); err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
 			Success: false,
-			Message: "Failed to update order status",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	s.Commit()
-
-	c.JSON(http.StatusOK, &model.GlobalResponse{
+	c.JSON(http.StatusOK, &model.OrderResponse{
 		Success: true,
-		Message: "Order status updated successfully",
+		Message: "Order cancelled successfully",
+		Data:    []*model.Order{order},
 	})
 }
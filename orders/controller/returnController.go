@@ -0,0 +1,265 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"orders/model"
+	"orders/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// returnAction moves a return along for the seller or an admin.
+type returnAction func(s *service.Service, ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error)
+
+func CreateReturn(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	var input model.NewReturn
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	returnRun(c, "Return requested successfully", func(s *service.Service, ctx context.Context, _ int, user *middleware.User) (*model.ReturnRequest, error) {
+		return s.ReturnCreate(ctx, orderID, user, input)
+	})
+}
+
+func GetOrderReturns(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	orderID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid order ID",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+	}()
+
+	returns, err := s.ReturnGetByOrderID(c.Request.Context(), orderID, user)
+	if err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderTransitionForbidden) {
+			code = http.StatusForbidden
+		}
+
+		c.AbortWithStatusJSON(code, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ReturnListResponse{
+		Success: true,
+		Message: "Returns retrieved successfully",
+		Data:    returns,
+	})
+}
+
+func GetSellerReturns(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+	}()
+
+	returns, err := s.ReturnGetBySellerID(c.Request.Context(), user.ID, c.Query("status"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ReturnListResponse{
+		Success: true,
+		Message: "Returns retrieved successfully",
+		Data:    returns,
+	})
+}
+
+func ApproveReturn(c *gin.Context) {
+	var input model.ReviewReturn
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reviewReturn(c, "Return approved successfully", func(s *service.Service, ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error) {
+		return s.ReturnApprove(ctx, id, user, input)
+	})
+}
+
+func RejectReturn(c *gin.Context) {
+	var input model.ReviewReturn
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reviewReturn(c, "Return rejected successfully", func(s *service.Service, ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error) {
+		return s.ReturnReject(ctx, id, user, input)
+	})
+}
+
+func ReceiveReturn(c *gin.Context) {
+	reviewReturn(c, "Return marked as received", func(s *service.Service, ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error) {
+		return s.ReturnReceive(ctx, id, user)
+	})
+}
+
+func RefundReturn(c *gin.Context) {
+	var input model.RefundReturn
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	reviewReturn(c, "Return refunded successfully", func(s *service.Service, ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error) {
+		return s.ReturnRefund(ctx, id, user, input)
+	})
+}
+
+func reviewReturn(c *gin.Context, message string, action returnAction) {
+	returnID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid return ID",
+		})
+		return
+	}
+
+	returnRun(c, message, func(s *service.Service, ctx context.Context, _ int, user *middleware.User) (*model.ReturnRequest, error) {
+		return action(s, ctx, returnID, user)
+	})
+}
+
+// returnRun runs action in a transaction for the logged in user and responds
+// with the return it leaves.
+func returnRun(c *gin.Context, message string, action returnAction) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	request, err := action(s, c.Request.Context(), 0, user)
+	if err != nil {
+		s.Rollback(err)
+
+		code := http.StatusBadRequest
+		if errors.Is(err, service.ErrOrderTransitionForbidden) {
+			code = http.StatusForbidden
+		}
+
+		c.AbortWithStatusJSON(code, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.Commit(); err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ReturnResponse{
+		Success: true,
+		Message: message,
+		Data:    request,
+	})
+}
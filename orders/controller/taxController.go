@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"net/http"
+	"orders/model"
+	"orders/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TaxRuleList(c *gin.Context) {
+	s := service.GetService()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+		}
+	}()
+
+	rules, err := s.TaxRuleGetAll(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.TaxRuleListResponse{
+		Success: true,
+		Message: "Tax rules retrieved successfully",
+		Data:    rules,
+	})
+}
+
+func CreateTaxRule(c *gin.Context) {
+	var input model.NewTaxRule
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	rule, err := s.TaxRuleCreate(c.Request.Context(), input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.TaxRuleResponse{
+		Success: true,
+		Message: "Tax rule successfully created",
+		Data:    rule,
+	})
+}
+
+func UpdateTaxRule(c *gin.Context) {
+	ruleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid tax rule ID",
+		})
+		return
+	}
+
+	var input model.UpdateTaxRule
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	rule, err := s.TaxRuleUpdate(c.Request.Context(), ruleID, input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.TaxRuleResponse{
+		Success: true,
+		Message: "Tax rule successfully updated",
+		Data:    rule,
+	})
+}
+
+func DeleteTaxRule(c *gin.Context) {
+	ruleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid tax rule ID",
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		r := recover()
+		if r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	if err := s.TaxRuleDelete(c.Request.Context(), ruleID); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Tax rule successfully deleted",
+	})
+}
@@ -2,7 +2,9 @@ package resolver
 
 import (
 	"context"
+	"fmt"
 	"orders/service"
+	"time"
 	"utils/orders"
 )
 
@@ -34,3 +36,108 @@ func (s *Server) CreateCart(ctx context.Context, req *orders.CreateCartRequest)
 	}, nil
 
 }
+
+func (s *Server) VerifyPurchase(ctx context.Context, req *orders.VerifyPurchaseRequest) (*orders.VerifyPurchaseResponse, error) {
+	orderID, err := service.GetService().OrderGetCompletedPurchase(ctx, int(req.UserId), int(req.ProductId))
+	if err != nil {
+		return nil, err
+	}
+
+	return &orders.VerifyPurchaseResponse{
+		Verified: orderID > 0,
+		OrderId:  int64(orderID),
+	}, nil
+}
+
+func (s *Server) GetProductSales(ctx context.Context, req *orders.ProductSalesRequest) (*orders.ProductSalesResponse, error) {
+	since, err := time.Parse(time.RFC3339, req.Since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+
+	productIDs := make([]int, 0, len(req.ProductIds))
+	for _, id := range req.ProductIds {
+		productIDs = append(productIDs, int(id))
+	}
+
+	sales, err := service.GetService().OrderItemGetDailySales(ctx, productIDs, since)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &orders.ProductSalesResponse{
+		Sales: make([]*orders.DailySales, 0, len(sales)),
+	}
+
+	for _, sale := range sales {
+		resp.Sales = append(resp.Sales, &orders.DailySales{
+			ProductId: int64(sale.ProductID),
+			Date:      sale.Day,
+			Quantity:  int64(sale.Quantity),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *Server) GetCoPurchases(ctx context.Context, req *orders.CoPurchaseRequest) (*orders.CoPurchaseResponse, error) {
+	since, err := time.Parse(time.RFC3339, req.Since)
+	if err != nil {
+		return nil, fmt.Errorf("invalid since: %w", err)
+	}
+
+	pairs, products, err := service.GetService().OrderItemGetCoPurchases(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &orders.CoPurchaseResponse{
+		Pairs:    make([]*orders.CoPurchase, 0, len(pairs)),
+		Products: make([]*orders.ProductOrders, 0, len(products)),
+	}
+
+	for _, pair := range pairs {
+		resp.Pairs = append(resp.Pairs, &orders.CoPurchase{
+			ProductId:        int64(pair.ProductID),
+			RelatedProductId: int64(pair.RelatedProductID),
+			Orders:           int64(pair.Orders),
+		})
+	}
+
+	for _, product := range products {
+		resp.Products = append(resp.Products, &orders.ProductOrders{
+			ProductId: int64(product.ProductID),
+			Orders:    int64(product.Orders),
+		})
+	}
+
+	return resp, nil
+}
+
+// ConfirmPayment is how the payment provider integration moves a card order
+// from pending to paid.
+func (s *Server) ConfirmPayment(ctx context.Context, req *orders.ConfirmPaymentRequest) (*orders.ConfirmPaymentResponse, error) {
+	tx := service.GetTransaction()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(r)
+			panic(r)
+		}
+	}()
+
+	order, err := tx.OrderConfirmPayment(ctx, int(req.OrderId), req.Amount, req.Currency, req.Reference)
+	if err != nil {
+		tx.Rollback(err)
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &orders.ConfirmPaymentResponse{
+		Success: true,
+		Status:  order.Status,
+	}, nil
+}
@@ -2,29 +2,98 @@ package grpcclient
 
 import (
 	"context"
+	"sync"
 	"utils/product"
 )
 
+var (
+	productClient     product.ProductClient
+	productClientOnce sync.Once
+)
+
+// getProductClient returns a client over a single connection that is shared
+// by every call, instead of dialing the products service each time.
+func getProductClient() product.ProductClient {
+	productClientOnce.Do(func() {
+		productClient, _ = product.Connect(product.ConnectionOption{})
+	})
+
+	return productClient
+}
+
 func GetProductDetails(ctx context.Context, req *product.GetProductDetailsRequest) (*product.GetProductDetailsResponse, error) {
-	productConn, conn := product.Connect(product.ConnectionOption{})
-	defer conn.Close()
+	productConn := getProductClient()
 
 	productDetails, err := productConn.GetProductDetails(ctx, req)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return productDetails, nil
 }
 
 func UpdateStock(ctx context.Context, req *product.UpdateStockRequest) (*product.UpdateStockResponse, error) {
-	productConn, conn := product.Connect(product.ConnectionOption{})
-	defer conn.Close()
+	productConn := getProductClient()
 
 	updateStock, err := productConn.UpdateStock(ctx, req)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return updateStock, nil
 }
+
+func ReserveStock(ctx context.Context, req *product.ReserveStockRequest) (*product.ReserveStockResponse, error) {
+	productConn := getProductClient()
+
+	reservation, err := productConn.ReserveStock(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func CommitReservation(ctx context.Context, req *product.ReservationRequest) (*product.ReservationResponse, error) {
+	productConn := getProductClient()
+
+	committed, err := productConn.CommitReservation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return committed, nil
+}
+
+func ReleaseReservation(ctx context.Context, req *product.ReservationRequest) (*product.ReservationResponse, error) {
+	productConn := getProductClient()
+
+	released, err := productConn.ReleaseReservation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return released, nil
+}
+
+func GetProductsDetails(ctx context.Context, req *product.GetProductsDetailsRequest) (*product.GetProductsDetailsResponse, error) {
+	productConn := getProductClient()
+
+	productsDetails, err := productConn.GetProductsDetails(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return productsDetails, nil
+}
+
+func IncrementStock(ctx context.Context, req *product.IncrementStockRequest) (*product.IncrementStockResponse, error) {
+	productConn := getProductClient()
+
+	incremented, err := productConn.IncrementStock(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return incremented, nil
+}
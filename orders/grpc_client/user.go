@@ -2,12 +2,25 @@ package grpcclient
 
 import (
 	"context"
+	"sync"
 	"utils/user"
 )
 
+var (
+	userClient     user.UserClient
+	userClientOnce sync.Once
+)
+
+func getUserClient() user.UserClient {
+	userClientOnce.Do(func() {
+		userClient, _ = user.Connect(user.ConnectionOption{})
+	})
+
+	return userClient
+}
+
 func GetUserDetails(ctx context.Context, id *user.GetUserDetailsRequest) (*user.GetUserDetailsResponse, error) {
-	userConn, conn := user.Connect(user.ConnectionOption{})
-	defer conn.Close()
+	userConn := getUserClient()
 
 	userDetails, err := userConn.GetUserDetails(ctx, id)
 	if err != nil {
@@ -7,8 +7,10 @@ import (
 	"orders/config"
 	"orders/grpc/resolver"
 	"orders/router"
+	"orders/service"
 	"os"
 	"sync"
+	"time"
 	"utils/middleware"
 	"utils/orders"
 
@@ -19,6 +21,9 @@ import (
 const (
 	defaultPort     = "8080"
 	defaultGRPCPort = "50051"
+
+	checkoutSagaRecoveryInterval = time.Minute
+	idempotencyPurgeInterval     = time.Hour
 )
 
 func init() {
@@ -41,6 +46,9 @@ func main() {
 	sqlDB, _ := db.DB()
 	defer sqlDB.Close()
 
+	go service.StartCheckoutSagaRecovery(checkoutSagaRecoveryInterval)
+	go middleware.PurgeIdempotencyKeys(db, idempotencyPurgeInterval)
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
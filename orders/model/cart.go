@@ -11,24 +11,44 @@ type Cart struct {
 }
 
 type CartItem struct {
-	ID        int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
-	CartID    int       `json:"cart_id" gorm:"type:int;not null"`
-	ProductID int       `json:"product_id" gorm:"type:int;not null"`
-	Quantity  int       `json:"quantity" gorm:"type:int;not null"`
-	Price     float64   `json:"price" gorm:"type:decimal(10,2);not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+	ID        int          `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	CartID    int          `json:"cart_id" gorm:"type:int;not null"`
+	ProductID int          `json:"product_id" gorm:"type:int;not null"`
+	VariantID int          `json:"variant_id" gorm:"type:int;not null;default:0"`
+	Quantity  int          `json:"quantity" gorm:"type:int;not null"`
+	Price     float64      `json:"price" gorm:"type:decimal(10,2);not null"`
+	Currency  string       `json:"currency" gorm:"type:varchar(3);not null"`
+	CreatedAt time.Time    `json:"created_at" gorm:"type:timestamp;not null"`
+	Product   *CartProduct `json:"product,omitempty" gorm:"-"`
+}
+
+// CartProduct is what the cart shows about an item's product. Available is
+// false once the product has been removed from sale.
+type CartProduct struct {
+	Name         string            `json:"name"`
+	ShopName     string            `json:"shop_name"`
+	PrimaryImage string            `json:"primary_image"`
+	Options      map[string]string `json:"options,omitempty"`
+	Price        float64           `json:"price"`
+	Currency     string            `json:"currency"`
+	OnSale       bool              `json:"on_sale"`
+	Stock        int               `json:"stock"`
+	Available    bool              `json:"available"`
 }
 
 type CartItemInput struct {
 	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id"`
 	Quantity  int `json:"quantity"`
 }
 
 type NewCartItem struct {
 	CartID    int     `json:"cart_id"`
 	ProductID int     `json:"product_id"`
+	VariantID int     `json:"variant_id"`
 	Quantity  int     `json:"quantity"`
 	Price     float64 `json:"price"`
+	Currency  string  `json:"currency"`
 }
 
 type EditCartItem struct {
@@ -8,9 +8,19 @@ type Order struct {
 	ID              int          `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
 	UserID          int          `json:"user_id" gorm:"type:int;not null"`
 	Status          string       `json:"status" gorm:"type:varchar(50);not null"`
+	Subtotal        float64      `json:"subtotal" gorm:"type:decimal(10,2);not null;default:0"`
+	TaxAmount       float64      `json:"tax_amount" gorm:"type:decimal(10,2);not null;default:0"`
 	TotalAmount     float64      `json:"total_amount" gorm:"type:decimal(10,2);not null;"`
+	Currency        string       `json:"currency" gorm:"type:varchar(3);not null"`
+	TaxRegion       string       `json:"tax_region" gorm:"type:varchar(10)"`
 	ShippingAddress string       `json:"shipping_address" gorm:"type:varchar(255);not null"`
 	PaymentMethod   string       `json:"payment_method" gorm:"type:varchar(100);not null"`
+	ReservationID   *int         `json:"-" gorm:"type:int;null"`
+	RefundedAmount  float64      `json:"refunded_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	CancelReason    string       `json:"cancel_reason,omitempty" gorm:"type:varchar(30)"`
+	CancelNote      string       `json:"cancel_note,omitempty" gorm:"type:varchar(255)"`
+	CancelledBy     string       `json:"cancelled_by,omitempty" gorm:"type:varchar(20)"`
+	CancelledAt     *time.Time   `json:"cancelled_at,omitempty" gorm:"type:timestamp;null"`
 	CreatedAt       time.Time    `json:"created_at" gorm:"type:timestamp;not null"`
 	UpdatedAt       *time.Time   `json:"updated_at" gorm:"type:timestamp;null"`
 	DeletedAt       *time.Time   `json:"deleted_at" gorm:"type:timestamp;null"`
@@ -18,33 +28,69 @@ type Order struct {
 }
 
 type OrderItem struct {
-	ID              int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
-	OrderID         int        `json:"order_id" gorm:"type:int;not null"`
-	ProductID       int        `json:"product_id" gorm:"type:int;not null"`
-	Quantity        int        `json:"quantity" gorm:"type:int;not null"`
-	PriceAtPurchase float64    `json:"price_at_purchase" gorm:"type:decimal(10,2);not null;"`
-	ProductSnapshot string     `json:"product_snapsho" gorm:"type:string;not null"`
-	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
-	UpdatedAt       *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
-	DeletedAt       *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+	ID               int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	OrderID          int        `json:"order_id" gorm:"type:int;not null"`
+	ProductID        int        `json:"product_id" gorm:"type:int;not null"`
+	VariantID        int        `json:"variant_id" gorm:"type:int;not null;default:0"`
+	SellerID         int        `json:"seller_id" gorm:"type:int;not null;default:0;index"`
+	Quantity         int        `json:"quantity" gorm:"type:int;not null"`
+	PriceAtPurchase  float64    `json:"price_at_purchase" gorm:"type:decimal(10,2);not null;"`
+	BaseCurrency     string     `json:"base_currency" gorm:"type:varchar(3);not null"`
+	ExchangeRate     float64    `json:"exchange_rate" gorm:"type:decimal(18,8);not null;default:1"`
+	TaxCategory      string     `json:"tax_category" gorm:"type:varchar(30)"`
+	TaxRate          float64    `json:"tax_rate" gorm:"type:decimal(7,4);not null;default:0"`
+	TaxAmount        float64    `json:"tax_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	PriceIncludesTax bool       `json:"price_includes_tax" gorm:"type:boolean;not null;default:false"`
+	ProductSnapshot  string     `json:"product_snapsho" gorm:"type:string;not null"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt        *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt        *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
 }
 
 type NewOrderItem struct {
-	OrderID         int         `json:"order_id"`
-	ProductID       int         `json:"product_id"`
-	Quantity        int         `json:"quantity"`
-	PriceAtPurchase float64     `json:"price_at_purchase"`
-	ProductSnapshot string      `json:"product_snapshot"`
-	ID              int         `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
-	UserID          int         `json:"user_id" gorm:"type:int;unique;not null"`
-	Status          string      `json:"status" gorm:"type:varchar(50);not null"`
-	TotalAmount     float64     `json:"total_amount" gorm:"type:decimal(10,2);not null;"`
-	ShippingAddress string      `json:"shipping_address" gorm:"type:varchar(255);not null"`
-	PaymentMethod   string      `json:"payment_method" gorm:"type:varchar(255);not null"`
-	CreatedAt       time.Time   `json:"created_at" gorm:"type:timestamp;not null"`
-	UpdatedAt       *time.Time  `json:"updated_at" gorm:"type:timestamp;null"`
-	DeletedAt       *time.Time  `json:"deleted_at" gorm:"type:timestamp;null"`
-	Items           []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	OrderID          int         `json:"order_id"`
+	ProductID        int         `json:"product_id"`
+	VariantID        int         `json:"variant_id"`
+	SellerID         int         `json:"seller_id"`
+	Quantity         int         `json:"quantity"`
+	PriceAtPurchase  float64     `json:"price_at_purchase"`
+	BaseCurrency     string      `json:"base_currency"`
+	ExchangeRate     float64     `json:"exchange_rate"`
+	TaxCategory      string      `json:"tax_category"`
+	TaxRate          float64     `json:"tax_rate"`
+	TaxAmount        float64     `json:"tax_amount"`
+	PriceIncludesTax bool        `json:"price_includes_tax"`
+	ProductSnapshot  string      `json:"product_snapshot"`
+	ID               int         `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	UserID           int         `json:"user_id" gorm:"type:int;unique;not null"`
+	Status           string      `json:"status" gorm:"type:varchar(50);not null"`
+	TotalAmount      float64     `json:"total_amount" gorm:"type:decimal(10,2);not null;"`
+	ShippingAddress  string      `json:"shipping_address" gorm:"type:varchar(255);not null"`
+	PaymentMethod    string      `json:"payment_method" gorm:"type:varchar(255);not null"`
+	CreatedAt        time.Time   `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt        *time.Time  `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt        *time.Time  `json:"deleted_at" gorm:"type:timestamp;null"`
+	Items            []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+}
+
+// DailySales is the quantity of a product sold on one day.
+type DailySales struct {
+	ProductID int    `json:"product_id"`
+	Day       string `json:"day"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CoPurchase is the number of completed orders that contain both products.
+type CoPurchase struct {
+	ProductID        int `json:"product_id"`
+	RelatedProductID int `json:"related_product_id"`
+	Orders           int `json:"orders"`
+}
+
+// ProductOrders is the number of completed orders that contain a product.
+type ProductOrders struct {
+	ProductID int `json:"product_id"`
+	Orders    int `json:"orders"`
 }
 
 type OrderResponse struct {
@@ -53,11 +99,19 @@ type OrderResponse struct {
 	Data    []*Order `json:"data"`
 }
 
+// CancelOrder is a cancellation request. Reason is one of the reason codes
+// of whoever cancels; Note is required when the reason is other.
+type CancelOrder struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
 type OrderTracking struct {
 	ID          int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
 	OrderID     int       `json:"order_id" gorm:"type:int;not null"`
 	Status      string    `json:"status" gorm:"type:varchar(100);not null"`
 	Description string    `json:"description" gorm:"type:varchar(100);not null"`
+	Actor       string    `json:"actor" gorm:"type:varchar(20)"`
 	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;not null"`
 }
 
@@ -68,14 +122,40 @@ type OrderTrackingResponse struct {
 }
 
 type ProductSnapshot struct {
-	ID              int       `json:"id"`
-	Name            string    `json:"name"`
-	Description     string    `json:"description"`
-	SellerID        int       `json:"seller_id"`
-	ShopName        string    `json:"shop_name"`
-	PriceAtPurchase float64   `json:"price_at_purchase"`
-	SKU             string    `json:"sku"`
-	PrimaryImage    *string   `json:"primary_image"`
-	TaxCategory     *string   `json:"tax_category"`
-	CapturedAt      time.Time `json:"captured_at"`
+	ID              int                  `json:"id"`
+	VariantID       *int                 `json:"variant_id"`
+	Options         map[string]string    `json:"options"`
+	Name            string               `json:"name"`
+	Description     string               `json:"description"`
+	SellerID        int                  `json:"seller_id"`
+	ShopName        string               `json:"shop_name"`
+	PriceAtPurchase float64              `json:"price_at_purchase"`
+	ListPrice       float64              `json:"list_price"`
+	Currency        string               `json:"currency"`
+	SKU             string               `json:"sku"`
+	CategoryPath    *string              `json:"category_path"`
+	PrimaryImage    *string              `json:"primary_image"`
+	TaxCategory     *string              `json:"tax_category"`
+	Attributes      []*SnapshotAttribute `json:"attributes"`
+	ProductType     string               `json:"product_type"`
+	Components      []*SnapshotComponent `json:"components,omitempty"`
+	CapturedAt      time.Time            `json:"captured_at"`
+}
+
+// SnapshotComponent is one product included in a bundle, per bundle unit.
+type SnapshotComponent struct {
+	ProductID int    `json:"product_id"`
+	VariantID *int   `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+	Name      string `json:"name"`
+	SKU       string `json:"sku"`
+}
+
+// SnapshotAttribute is a product spec as it was when the item was bought.
+type SnapshotAttribute struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Unit  string `json:"unit"`
+	Value string `json:"value"`
 }
@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+type RefundStatus string
+
+const (
+	REFUND_STATUS_PENDING   RefundStatus = "pending"
+	REFUND_STATUS_SUCCEEDED RefundStatus = "succeeded"
+	REFUND_STATUS_FAILED    RefundStatus = "failed"
+)
+
+// Refund is money owed back to the buyer of an order. Refunds are created as
+// pending and settled by the payment provider of the order's payment method.
+type Refund struct {
+	ID            int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	OrderID       int        `json:"order_id" gorm:"type:int;not null;index"`
+	ReturnID      *int       `json:"return_id" gorm:"type:int;null"`
+	Amount        float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
+	Currency      string     `json:"currency" gorm:"type:varchar(3);not null"`
+	PaymentMethod string     `json:"payment_method" gorm:"type:varchar(100);not null"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null"`
+	Reason        string     `json:"reason" gorm:"type:varchar(100)"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt     *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+}
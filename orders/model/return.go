@@ -0,0 +1,99 @@
+package model
+
+import "time"
+
+type ReturnStatus string
+
+const (
+	RETURN_STATUS_REQUESTED ReturnStatus = "requested"
+	RETURN_STATUS_APPROVED  ReturnStatus = "approved"
+	RETURN_STATUS_REJECTED  ReturnStatus = "rejected"
+	RETURN_STATUS_RECEIVED  ReturnStatus = "received"
+	RETURN_STATUS_REFUNDED  ReturnStatus = "refunded"
+)
+
+// ReturnRequest is a buyer asking to send items of a completed order back.
+// A return only holds items sold by one seller, who handles it.
+type ReturnRequest struct {
+	ID             int               `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	OrderID        int               `json:"order_id" gorm:"type:int;not null;index"`
+	UserID         int               `json:"user_id" gorm:"type:int;not null;index"`
+	SellerID       int               `json:"seller_id" gorm:"type:int;not null;index"`
+	Status         string            `json:"status" gorm:"type:varchar(20);not null"`
+	Reason         string            `json:"reason" gorm:"type:varchar(30);not null"`
+	Note           string            `json:"note" gorm:"type:varchar(255)"`
+	ResolutionNote string            `json:"resolution_note" gorm:"type:varchar(255)"`
+	RefundID       *int              `json:"refund_id" gorm:"type:int;null"`
+	RefundAmount   float64           `json:"refund_amount" gorm:"type:decimal(10,2);not null;default:0"`
+	Restocked      bool              `json:"restocked" gorm:"type:boolean;not null;default:false"`
+	CreatedAt      time.Time         `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt      *time.Time        `json:"updated_at" gorm:"type:timestamp;null"`
+	Items          []*ReturnItem     `json:"items" gorm:"-"`
+	Photos         []*ReturnPhoto    `json:"photos" gorm:"-"`
+	History        []*ReturnTracking `json:"history" gorm:"-"`
+}
+
+type ReturnItem struct {
+	ID          int `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ReturnID    int `json:"return_id" gorm:"type:int;not null;index"`
+	OrderItemID int `json:"order_item_id" gorm:"type:int;not null;index"`
+	ProductID   int `json:"product_id" gorm:"type:int;not null"`
+	VariantID   int `json:"variant_id" gorm:"type:int;not null;default:0"`
+	Quantity    int `json:"quantity" gorm:"type:int;not null"`
+}
+
+type ReturnPhoto struct {
+	ID        int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ReturnID  int       `json:"return_id" gorm:"type:int;not null;index"`
+	URL       string    `json:"url" gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// ReturnTracking is one step in a return's history, as OrderTracking is for
+// orders.
+type ReturnTracking struct {
+	ID          int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ReturnID    int       `json:"return_id" gorm:"type:int;not null;index"`
+	Status      string    `json:"status" gorm:"type:varchar(20);not null"`
+	Description string    `json:"description" gorm:"type:varchar(100);not null"`
+	Actor       string    `json:"actor" gorm:"type:varchar(20)"`
+	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+type NewReturnItem struct {
+	OrderItemID int `json:"order_item_id"`
+	Quantity    int `json:"quantity"`
+}
+
+// NewReturn requests a return. Photos are links to pictures of the items,
+// such as of damage.
+type NewReturn struct {
+	Items  []NewReturnItem `json:"items"`
+	Reason string          `json:"reason"`
+	Note   string          `json:"note"`
+	Photos []string        `json:"photos"`
+}
+
+type ReviewReturn struct {
+	Note string `json:"note"`
+}
+
+// RefundReturn issues the refund of a received return. Amount defaults to
+// everything paid for the returned items; Restock puts them back in stock.
+type RefundReturn struct {
+	Amount  *float64 `json:"amount"`
+	Restock bool     `json:"restock"`
+	Note    string   `json:"note"`
+}
+
+type ReturnResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    *ReturnRequest `json:"data"`
+}
+
+type ReturnListResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    []*ReturnRequest `json:"data"`
+}
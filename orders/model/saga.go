@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+type SagaStatus string
+
+const (
+	SAGA_STATUS_RUNNING      SagaStatus = "running"
+	SAGA_STATUS_COMPLETED    SagaStatus = "completed"
+	SAGA_STATUS_COMPENSATING SagaStatus = "compensating"
+	SAGA_STATUS_COMPENSATED  SagaStatus = "compensated"
+	SAGA_STATUS_FAILED       SagaStatus = "failed"
+)
+
+type SagaStepStatus string
+
+const (
+	SAGA_STEP_STATUS_PENDING     SagaStepStatus = "pending"
+	SAGA_STEP_STATUS_RUNNING     SagaStepStatus = "running"
+	SAGA_STEP_STATUS_DONE        SagaStepStatus = "done"
+	SAGA_STEP_STATUS_FAILED      SagaStepStatus = "failed"
+	SAGA_STEP_STATUS_COMPENSATED SagaStepStatus = "compensated"
+)
+
+type SagaStep string
+
+// Checkout steps, in the order they run. Compensation runs them backwards.
+const (
+	SAGA_STEP_RESERVE_STOCK SagaStep = "reserve_stock"
+	SAGA_STEP_CREATE_ORDER  SagaStep = "create_order"
+	SAGA_STEP_CLEAR_CART    SagaStep = "clear_cart"
+	SAGA_STEP_COMMIT_STOCK  SagaStep = "commit_stock"
+)
+
+// CheckoutSaga is the persisted state of one checkout. CartItems holds the
+// cart items being bought as json, so they can be put back in the cart if the
+// checkout is undone.
+type CheckoutSaga struct {
+	ID            int                 `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	UserID        int                 `json:"user_id" gorm:"type:int;not null;index"`
+	CartID        int                 `json:"cart_id" gorm:"type:int;not null"`
+	Status        string              `json:"status" gorm:"type:varchar(20);not null;index"`
+	ReservationID *int                `json:"reservation_id" gorm:"type:int;null"`
+	OrderID       *int                `json:"order_id" gorm:"type:int;null;index"`
+	CartItems     string              `json:"-" gorm:"type:text;not null"`
+	Error         string              `json:"error" gorm:"type:varchar(255)"`
+	Attempts      int                 `json:"attempts" gorm:"type:int;not null;default:0"`
+	CreatedAt     time.Time           `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt     time.Time           `json:"updated_at" gorm:"type:timestamp;not null;index"`
+	Steps         []*CheckoutSagaStep `json:"steps" gorm:"-"`
+}
+
+type CheckoutSagaStep struct {
+	ID        int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	SagaID    int        `json:"saga_id" gorm:"type:int;not null;index"`
+	Step      string     `json:"step" gorm:"type:varchar(30);not null"`
+	Position  int        `json:"position" gorm:"type:int;not null"`
+	Status    string     `json:"status" gorm:"type:varchar(20);not null"`
+	Error     string     `json:"error" gorm:"type:varchar(255)"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+}
@@ -0,0 +1,78 @@
+package model
+
+import "time"
+
+// TAX_RULE_ANY matches every region or every tax category in a tax rule.
+const TAX_RULE_ANY = "*"
+
+// TaxRule sets how products of a tax category are taxed when they are shipped
+// to a region. Region is a country code such as DE, a subdivision such as
+// US-CA, or * for everywhere; TaxCategory is a product tax category or * for
+// all of them. When several rules match, the one with the most specific
+// region wins, then the one with the most specific category.
+type TaxRule struct {
+	ID               int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	Region           string     `json:"region" gorm:"type:varchar(10);not null;index"`
+	TaxCategory      string     `json:"tax_category" gorm:"type:varchar(30);not null"`
+	Rate             float64    `json:"rate" gorm:"type:decimal(7,4);not null;default:0"`
+	Exempt           bool       `json:"exempt" gorm:"type:boolean;not null;default:false"`
+	PriceIncludesTax bool       `json:"price_includes_tax" gorm:"type:boolean;not null;default:false"`
+	Description      string     `json:"description" gorm:"type:varchar(100)"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt        *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt        *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+}
+
+// NewTaxRule creates a tax rule. Rate is a percentage, so 20 means 20%.
+type NewTaxRule struct {
+	Region           string  `json:"region"`
+	TaxCategory      string  `json:"tax_category"`
+	Rate             float64 `json:"rate"`
+	Exempt           bool    `json:"exempt"`
+	PriceIncludesTax bool    `json:"price_includes_tax"`
+	Description      string  `json:"description"`
+}
+
+type UpdateTaxRule struct {
+	Rate             *float64 `json:"rate"`
+	Exempt           *bool    `json:"exempt"`
+	PriceIncludesTax *bool    `json:"price_includes_tax"`
+	Description      *string  `json:"description"`
+}
+
+// TaxLine is the tax on one order line. Net is the amount before tax and
+// Gross the amount the buyer pays for the line.
+type TaxLine struct {
+	Key              string  `json:"key"`
+	TaxCategory      string  `json:"tax_category"`
+	RuleID           *int    `json:"rule_id"`
+	Rate             float64 `json:"rate"`
+	Exempt           bool    `json:"exempt"`
+	PriceIncludesTax bool    `json:"price_includes_tax"`
+	Net              float64 `json:"net"`
+	Tax              float64 `json:"tax"`
+	Gross            float64 `json:"gross"`
+}
+
+// TaxBreakdown is the tax of a whole order. Lines are rounded one by one and
+// the order totals are their sums.
+type TaxBreakdown struct {
+	Region   string     `json:"region"`
+	Currency string     `json:"currency"`
+	Subtotal float64    `json:"subtotal"`
+	Tax      float64    `json:"tax"`
+	Total    float64    `json:"total"`
+	Lines    []*TaxLine `json:"lines"`
+}
+
+type TaxRuleResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message"`
+	Data    *TaxRule `json:"data"`
+}
+
+type TaxRuleListResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message"`
+	Data    []*TaxRule `json:"data"`
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"orders/config"
 	"orders/controller"
 	"utils/middleware"
 
@@ -8,20 +9,40 @@ import (
 )
 
 func ApiRouter(r *gin.Engine) {
+	idempotent := middleware.Idempotency(config.GetDB())
+
 	auth := r.Group("")
 	auth.Use(middleware.AuthMiddleware(), middleware.IsLogin())
 	{
 		auth.GET("/cart", controller.GetCart)
-		auth.POST("/cart", controller.AddToCart)
+		auth.POST("/cart", idempotent, controller.AddToCart)
 		auth.POST("/cart/update", controller.UpdateCartItem)
-		auth.POST("/checkout", controller.Checkout)
+		auth.POST("/checkout", idempotent, controller.Checkout)
 		auth.GET("/orders", controller.GetOrderHistory)
 		auth.GET("/orders/:id/track", controller.TrackOrder)
+		auth.POST("/orders/:id/updateStatus", idempotent, controller.UpdateOrderStatus)
+		auth.POST("/orders/:id/cancel", idempotent, controller.CancelOrder)
+		auth.GET("/orders/:id/returns", controller.GetOrderReturns)
+		auth.POST("/orders/:id/returns", idempotent, controller.CreateReturn)
+		auth.POST("/returns/:id/approve", controller.ApproveReturn)
+		auth.POST("/returns/:id/reject", controller.RejectReturn)
+		auth.POST("/returns/:id/receive", controller.ReceiveReturn)
+		auth.POST("/returns/:id/refund", idempotent, controller.RefundReturn)
 	}
 
-	seller := r.Group("")
+	seller := r.Group("/seller")
 	seller.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin(), middleware.IsSeller())
 	{
-		seller.POST("/orders/:id/updateStatus", controller.UpdateOrderStatus)
+		seller.POST("/orders/:id/cancel", idempotent, controller.SellerCancelOrder)
+		seller.GET("/returns", controller.GetSellerReturns)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin(), middleware.IsAdmin())
+	{
+		admin.GET("/tax-rules", controller.TaxRuleList)
+		admin.POST("/tax-rules", controller.CreateTaxRule)
+		admin.PUT("/tax-rules/:id", controller.UpdateTaxRule)
+		admin.DELETE("/tax-rules/:id", controller.DeleteTaxRule)
 	}
 }
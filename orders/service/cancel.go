@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"orders/model"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"utils/middleware"
+)
+
+type CancelReason string
+
+const (
+	CANCEL_REASON_CHANGED_MIND       CancelReason = "changed_mind"
+	CANCEL_REASON_ORDERED_BY_MISTAKE CancelReason = "ordered_by_mistake"
+	CANCEL_REASON_FOUND_CHEAPER      CancelReason = "found_cheaper"
+	CANCEL_REASON_DELIVERY_TOO_SLOW  CancelReason = "delivery_too_slow"
+	CANCEL_REASON_OUT_OF_STOCK       CancelReason = "out_of_stock"
+	CANCEL_REASON_CANNOT_SHIP        CancelReason = "cannot_ship"
+	CANCEL_REASON_PRICING_ERROR      CancelReason = "pricing_error"
+	CANCEL_REASON_BUYER_REQUEST      CancelReason = "buyer_request"
+	CANCEL_REASON_OTHER              CancelReason = "other"
+)
+
+// cancelReasons are the reasons the buyer and sellers can give for cancelling
+// an order, with the text shown in the order's tracking.
+var cancelReasons = map[OrderRole]map[CancelReason]string{
+	ORDER_ROLE_BUYER: {
+		CANCEL_REASON_CHANGED_MIND:       "changed my mind",
+		CANCEL_REASON_ORDERED_BY_MISTAKE: "ordered by mistake",
+		CANCEL_REASON_FOUND_CHEAPER:      "found a better price",
+		CANCEL_REASON_DELIVERY_TOO_SLOW:  "delivery takes too long",
+		CANCEL_REASON_OTHER:              "other",
+	},
+	ORDER_ROLE_SELLER: {
+		CANCEL_REASON_OUT_OF_STOCK:  "out of stock",
+		CANCEL_REASON_CANNOT_SHIP:   "cannot ship to this address",
+		CANCEL_REASON_PRICING_ERROR: "listed at the wrong price",
+		CANCEL_REASON_BUYER_REQUEST: "requested by the buyer",
+		CANCEL_REASON_OTHER:         "other",
+	},
+}
+
+// OrderCancel cancels a pending or paid order, as its buyer or as its seller.
+// A seller can only cancel an order whose items are all theirs. The stock of
+// every item is put back, and a paid order is refunded in full.
+func (s *Service) OrderCancel(ctx context.Context, orderID int, user *middleware.User, as OrderRole, input model.CancelOrder) (*model.Order, error) {
+	reasons, ok := cancelReasons[as]
+	if !ok {
+		return nil, fmt.Errorf("orders cannot be cancelled as %s", as)
+	}
+
+	reason := CancelReason(strings.ToLower(strings.TrimSpace(input.Reason)))
+	label, ok := reasons[reason]
+	if !ok {
+		return nil, fmt.Errorf("invalid input: reason must be one of %s", cancelReasonCodes(reasons))
+	}
+
+	note := strings.TrimSpace(input.Note)
+	if reason == CANCEL_REASON_OTHER && note == "" {
+		return nil, fmt.Errorf("invalid input: a note is required when the reason is other")
+	}
+	if len(note) > 255 {
+		return nil, fmt.Errorf("invalid input: note must be at most 255 characters")
+	}
+
+	order, err := s.orderLock(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.OrderRoles(ctx, order, user)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(roles, as) {
+		return nil, ErrOrderTransitionForbidden
+	}
+
+	items, err := s.OrderGetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// cancelling would put back stock and refund lines of the other sellers
+	if as == ORDER_ROLE_SELLER {
+		for _, item := range items {
+			if item.SellerID != user.ID {
+				return nil, fmt.Errorf("%w: it has items from other sellers", ErrOrderTransitionForbidden)
+			}
+		}
+	}
+
+	paid := order.Status == string(ORDER_STATUS_PAID)
+
+	description := label
+	if reason == CANCEL_REASON_OTHER {
+		description = note
+	} else if note != "" {
+		description += " - " + note
+	}
+
+	if _, err := s.orderTransitionApply(order, ORDER_STATUS_CANCELLED, []OrderRole{as}, description); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.DB.Model(order).Updates(map[string]interface{}{
+		"cancel_reason": string(reason),
+		"cancel_note":   note,
+		"cancelled_by":  string(as),
+		"cancelled_at":  now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	order.CancelReason = string(reason)
+	order.CancelNote = note
+	order.CancelledBy = string(as)
+	order.CancelledAt = &now
+
+	order.Items = items
+
+	if _, err := s.RestoreStock(ctx, order.ID, STOCK_RESTORE_ORDER_CANCEL, fmt.Sprintf("order-%d-cancel", order.ID), items); err != nil {
+		return nil, fmt.Errorf("failed to restore stock: %w", err)
+	}
+
+	if paid && order.TotalAmount > order.RefundedAmount {
+		if _, err := s.RefundCreate(ctx, order, order.TotalAmount-order.RefundedAmount, "order cancelled: "+label, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func cancelReasonCodes(reasons map[CancelReason]string) string {
+	codes := make([]string, 0, len(reasons))
+	for code := range reasons {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+
+	return strings.Join(codes, ", ")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"orders/model"
 	"utils/middleware"
+	"utils/product"
 
 	"gorm.io/gorm"
 )
@@ -44,11 +45,19 @@ func (s *Service) AddToCart(ctx context.Context, newItem model.CartItemInput) (b
 	}
 
 	// gRPC call to get product details
-	product, err := s.GetProductDetails(ctx, newItem.ProductID)
+	product, err := s.GetProductVariantDetails(ctx, newItem.ProductID, newItem.VariantID)
 	if err != nil {
 		return false, fmt.Errorf("failed to get product details: %w", err)
 	}
 
+	if !product.Purchasable() {
+		return false, fmt.Errorf("product is not available for purchase")
+	}
+
+	if product.HasVariants && product.VariantID == 0 {
+		return false, fmt.Errorf("a variant must be selected for this product")
+	}
+
 	// validate product
 	if newItem.Quantity > int(product.Stock) {
 		return false, fmt.Errorf("item is out of stock")
@@ -57,8 +66,10 @@ func (s *Service) AddToCart(ctx context.Context, newItem model.CartItemInput) (b
 	newItemDetails := model.NewCartItem{
 		CartID:    cart.ID,
 		ProductID: int(product.ID),
+		VariantID: product.VariantID,
 		Quantity:  newItem.Quantity,
 		Price:     product.Price,
+		Currency:  product.Currency,
 	}
 
 	item, err := s.CartCreateItem(ctx, newItemDetails)
@@ -97,6 +108,43 @@ func (s *Service) CartGetDetails(ctx context.Context) (*model.Cart, error) {
 	return cart, nil
 }
 
+// CartLoadProducts attaches current product details to every cart item with
+// a single lookup, priced in the display currency when one is given. Items
+// whose product is gone stay in the cart but are marked unavailable.
+func (s *Service) CartLoadProducts(ctx context.Context, cart *model.Cart, currency string) error {
+	lookups := make([]ProductLookup, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		lookups = append(lookups, ProductLookup{ProductID: item.ProductID, VariantID: item.VariantID})
+	}
+
+	details, _, err := s.GetProductsDetails(ctx, lookups, false, currency)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range cart.Items {
+		detail, ok := details[product.LookupKey(int64(item.ProductID), int64(item.VariantID))]
+		if !ok {
+			item.Product = &model.CartProduct{Available: false}
+			continue
+		}
+
+		item.Product = &model.CartProduct{
+			Name:         detail.Name,
+			ShopName:     detail.ShopName,
+			PrimaryImage: detail.PrimaryImage,
+			Options:      detail.Options,
+			Price:        detail.Price,
+			Currency:     detail.Currency,
+			OnSale:       detail.OnSale,
+			Stock:        detail.Stock,
+			Available:    detail.Purchasable(),
+		}
+	}
+
+	return nil
+}
+
 func (s *Service) CartGetItemDetails(ctx context.Context, cartID int, itemID int) (*model.CartItem, error) {
 	var (
 		cartItem *model.CartItem
@@ -157,8 +205,10 @@ func (s *Service) CartCreateItem(ctx context.Context, newItem model.NewCartItem)
 	item := model.CartItem{
 		CartID:    newItem.CartID,
 		ProductID: newItem.ProductID,
+		VariantID: newItem.VariantID,
 		Quantity:  newItem.Quantity,
 		Price:     newItem.Price,
+		Currency:  newItem.Currency,
 	}
 
 	if err := s.DB.Model(&item).Create(&item).Error; err != nil {
@@ -173,14 +223,17 @@ func (s *Service) CartUpdateItem(ctx context.Context, itemDetails model.EditCart
 		return false, fmt.Errorf("cart ID and item ID cannot be empty")
 	}
 
-	product, err := s.GetProductDetails(ctx, itemDetails.ProductID)
+	cartItem, err := s.CartGetItemDetails(ctx, itemDetails.CartID, itemDetails.ID)
 	if err != nil {
 		return false, err
 	}
 
-	price := float64(itemDetails.Quantity) * product.Price
+	product, err := s.GetProductVariantDetails(ctx, itemDetails.ProductID, cartItem.VariantID)
+	if err != nil {
+		return false, err
+	}
 
-	if err := s.DB.Table("cart_item").Where("id = ?", itemDetails.ID).UpdateColumns(model.CartItem{Quantity: itemDetails.Quantity, Price: price}).Error; err != nil {
+	if err := s.DB.Table("cart_item").Where("id = ?", itemDetails.ID).UpdateColumns(model.CartItem{Quantity: itemDetails.Quantity, Price: product.Price, Currency: product.Currency}).Error; err != nil {
 		return false, err
 	}
 
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"orders/model"
 	"orders/tools"
+	"strconv"
 	"time"
+	"utils/currency"
 	"utils/middleware"
+	"utils/product"
 
 	"gorm.io/gorm"
 )
@@ -29,10 +32,10 @@ const (
 	PAYMENT_METHOD_CARD PaymentMethod = "credit_card"
 )
 
-func (s *Service) CreateOrder(ctx context.Context, cartID int, cartItemIDs []int, paymentMethod string) (*model.Order, error) {
+func (s *Service) CreateOrder(ctx context.Context, cartID int, cartItemIDs []int, paymentMethod string, orderCurrency string) (*model.Order, error) {
 	var (
-		totalAmount float64 = 0
-		ctxData             = middleware.AuthContext(ctx)
+		ctxData  = middleware.AuthContext(ctx)
+		taxLines []TaxableLine
 	)
 
 	if cartID <= 0 || len(cartItemIDs) == 0 || paymentMethod == "" {
@@ -60,8 +63,34 @@ func (s *Service) CreateOrder(ctx context.Context, cartID int, cartItemIDs []int
 
 	fmt.Printf("cart items: %v", cartItems)
 
+	orderCurrency, err = s.orderResolveCurrency(cartItems, orderCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.GetCartItemsProducts(ctx, cartItems, false, orderCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	// cart prices were taken when the item was added, so reprice every item
+	// at checkout in case a sale has started or ended since, converting them
+	// at the current rates
 	for _, item := range cartItems {
-		totalAmount += item.Price * float64(item.Quantity)
+		productDetail := products[product.LookupKey(int64(item.ProductID), int64(item.VariantID))]
+		if !productDetail.Purchasable() {
+			return nil, fmt.Errorf("product %d is not available for purchase", item.ProductID)
+		}
+
+		item.Price = productDetail.Price
+		item.Currency = productDetail.Currency
+
+		taxLines = append(taxLines, TaxableLine{
+			Key:         strconv.Itoa(item.ID),
+			TaxCategory: productDetail.TaxCategory,
+			UnitPrice:   item.Price,
+			Quantity:    item.Quantity,
+		})
 	}
 
 	// grpc call
@@ -72,48 +101,101 @@ func (s *Service) CreateOrder(ctx context.Context, cartID int, cartItemIDs []int
 
 	fmt.Printf("user details: %v", userDetails)
 
+	// tax is charged where the order ships to, so it comes from the buyer's
+	// address rather than anything sent with the checkout
+	if userDetails.Region == "" {
+		return nil, fmt.Errorf("shipping address has no region, please update your address before checking out")
+	}
+
+	taxes, err := s.TaxCalculate(ctx, userDetails.Region, orderCurrency, taxLines)
+	if err != nil {
+		return nil, err
+	}
+
 	order := model.Order{
 		UserID:          ctxData.ID,
 		Status:          string(ORDER_STATUS_PENDING),
-		TotalAmount:     totalAmount,
+		Subtotal:        taxes.Subtotal,
+		TaxAmount:       taxes.Tax,
+		TotalAmount:     taxes.Total,
+		Currency:        orderCurrency,
+		TaxRegion:       taxes.Region,
 		ShippingAddress: userDetails.Address,
 		PaymentMethod:   paymentMethod,
 	}
 
 	fmt.Printf("order details: %v", order)
 
-	if err := s.DB.Create(&order).Error; err != nil {
+	// every step from here on changes state in this service or in products,
+	// so they run as a saga that is undone as a whole if any of them fails
+	// before the cart is cleared
+	saga, err := checkoutSagaStart(ctxData.ID, cartID, cartItems)
+	if err != nil {
 		return nil, err
 	}
 
-	success, err := s.OrderAddItems(ctx, order, cartItems)
+	err = s.checkoutSagaRun(ctx, saga, map[model.SagaStep]sagaAction{
+		model.SAGA_STEP_RESERVE_STOCK: func(tx *Service) error {
+			// hold stock before the order is written so two checkouts cannot oversell
+			reservationID, err := tx.ReserveStock(ctx, cartItems)
+			if err != nil {
+				return fmt.Errorf("failed to reserve stock: %w", err)
+			}
+
+			saga.ReservationID = &reservationID
+			order.ReservationID = &reservationID
+
+			return tx.DB.Model(saga).Update("reservation_id", reservationID).Error
+		},
+		model.SAGA_STEP_CREATE_ORDER: func(tx *Service) error {
+			if err := tx.DB.Create(&order).Error; err != nil {
+				return err
+			}
+
+			success, err := tx.OrderAddItems(ctx, order, cartItems, products, taxes)
+			if err != nil {
+				return err
+			} else if !success {
+				return fmt.Errorf("failed to add items to order")
+			}
+
+			saga.OrderID = &order.ID
+
+			return tx.DB.Model(saga).Update("order_id", order.ID).Error
+		},
+		model.SAGA_STEP_CLEAR_CART: func(tx *Service) error {
+			success, err := tx.CartRemoveItems(ctx, cartItemIDs)
+			if err != nil {
+				return err
+			} else if !success {
+				return fmt.Errorf("failed to remove items from cart")
+			}
+
+			return nil
+		},
+		model.SAGA_STEP_COMMIT_STOCK: checkoutSagaCommitStock(ctx, saga),
+	})
 	if err != nil {
 		return nil, err
-	} else if !success {
-		return nil, fmt.Errorf("failed to add items to order")
 	}
 
-	success, err = s.CartRemoveItems(ctx, cartItemIDs)
-	if err != nil {
-		return nil, err
-	} else if !success {
-		return nil, fmt.Errorf("failed to remove items from cart")
+	return &order, nil
+}
+
+// orderResolveCurrency picks the currency an order is charged in: the one the
+// buyer asked for, else the one every item is listed in, else the default.
+func (s *Service) orderResolveCurrency(items []*model.CartItem, requested string) (string, error) {
+	if requested != "" {
+		return currency.Normalize(requested)
 	}
 
-	for _, item := range cartItems {
-		updateStock, err := s.UpdateStock(ctx, item.ProductID, item.Quantity)
-		if err != nil {
-			return nil, err
-		}
-		if !updateStock {
-			return nil, fmt.Errorf("failed to update stock")
+	for _, item := range items {
+		if item.Currency != items[0].Currency || item.Currency == "" {
+			return currency.Default(), nil
 		}
 	}
 
-	cartItemsLeft, _ := s.CartGetItemsByCartID(ctx, cartID)
-	cart.Items = cartItemsLeft
-
-	return &order, nil
+	return items[0].Currency, nil
 }
 
 func (s *Service) OrderOnCreate(ctx context.Context, cart model.Cart, paymentMethod string) (bool, error) {
@@ -128,11 +210,22 @@ func (s *Service) OrderOnCreate(ctx context.Context, cart model.Cart, paymentMet
 	return true, nil
 }
 
-func (s *Service) OrderAddItems(ctx context.Context, order model.Order, items []*model.CartItem) (bool, error) {
+// OrderAddItems writes the order items together with a snapshot of each
+// product. products holds the details fetched at checkout, keyed by
+// product.LookupKey; any item missing from it is looked up again. taxes holds
+// the tax of each line keyed by cart item id.
+func (s *Service) OrderAddItems(ctx context.Context, order model.Order, items []*model.CartItem, products map[string]*ProductDetail, taxes *model.TaxBreakdown) (bool, error) {
 	var (
 		orderItems []*model.OrderItem
+		lineTaxes  = make(map[string]*model.TaxLine)
 	)
 
+	if taxes != nil {
+		for _, line := range taxes.Lines {
+			lineTaxes[line.Key] = line
+		}
+	}
+
 	if len(items) == 0 {
 		return false, fmt.Errorf("no items to add")
 	}
@@ -145,8 +238,31 @@ func (s *Service) OrderAddItems(ctx context.Context, order model.Order, items []
 		return false, fmt.Errorf("order does not exist")
 	}
 
+	var missing []*model.CartItem
+	for _, item := range items {
+		if _, ok := products[product.LookupKey(int64(item.ProductID), int64(item.VariantID))]; !ok {
+			missing = append(missing, item)
+		}
+	}
+
+	if len(missing) > 0 {
+		fetched, err := s.GetCartItemsProducts(ctx, missing, true, order.Currency)
+		if err != nil {
+			return false, err
+		}
+
+		if products == nil {
+			products = make(map[string]*ProductDetail, len(fetched))
+		}
+		for key, detail := range fetched {
+			products[key] = detail
+		}
+	}
+
 	for _, item := range items {
-		snapshot, err := s.CreateProductSnapshot(ctx, order.ID, item.ProductID)
+		productDetail := products[product.LookupKey(int64(item.ProductID), int64(item.VariantID))]
+
+		snapshot, err := s.BuildProductSnapshot(productDetail)
 		if err != nil {
 			return false, err
 		}
@@ -154,10 +270,20 @@ func (s *Service) OrderAddItems(ctx context.Context, order model.Order, items []
 		newOrderItem := model.NewOrderItem{
 			OrderID:         order.ID,
 			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			SellerID:        productDetail.SellerID,
 			Quantity:        item.Quantity,
 			PriceAtPurchase: item.Price,
+			BaseCurrency:    productDetail.BaseCurrency,
+			ExchangeRate:    productDetail.ExchangeRate,
+			TaxCategory:     productDetail.TaxCategory,
 			ProductSnapshot: snapshot,
 		}
+		if line, ok := lineTaxes[strconv.Itoa(item.ID)]; ok {
+			newOrderItem.TaxRate = line.Rate
+			newOrderItem.TaxAmount = line.Tax
+			newOrderItem.PriceIncludesTax = line.PriceIncludesTax
+		}
 
 		orderItem, err := s.CreateOrderItem(ctx, newOrderItem)
 		if err != nil {
@@ -172,17 +298,35 @@ func (s *Service) OrderAddItems(ctx context.Context, order model.Order, items []
 	return true, nil
 }
 
+func (s *Service) OrderGetItems(ctx context.Context, orderID int) ([]*model.OrderItem, error) {
+	var items []*model.OrderItem
+
+	if err := s.DB.Model(&items).Scopes(tools.IsDeletedAtNull).Where("order_id = ?", orderID).Order("id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 func (s *Service) CreateOrderItem(ctx context.Context, item model.NewOrderItem) (*model.OrderItem, error) {
 	if item.OrderID <= 0 || item.ProductID <= 0 || item.Quantity <= 0 || item.PriceAtPurchase <= 0 {
 		return nil, fmt.Errorf("data cannot be empty")
 	}
 
 	orderItem := model.OrderItem{
-		OrderID:         item.OrderID,
-		ProductID:       item.ProductID,
-		Quantity:        item.Quantity,
-		PriceAtPurchase: item.PriceAtPurchase,
-		ProductSnapshot: item.ProductSnapshot,
+		OrderID:          item.OrderID,
+		ProductID:        item.ProductID,
+		VariantID:        item.VariantID,
+		SellerID:         item.SellerID,
+		Quantity:         item.Quantity,
+		PriceAtPurchase:  item.PriceAtPurchase,
+		BaseCurrency:     item.BaseCurrency,
+		ExchangeRate:     item.ExchangeRate,
+		TaxCategory:      item.TaxCategory,
+		TaxRate:          item.TaxRate,
+		TaxAmount:        item.TaxAmount,
+		PriceIncludesTax: item.PriceIncludesTax,
+		ProductSnapshot:  item.ProductSnapshot,
 	}
 
 	if err := s.DB.Model(&model.OrderItem{}).Create(&orderItem).Error; err != nil {
@@ -192,32 +336,61 @@ func (s *Service) CreateOrderItem(ctx context.Context, item model.NewOrderItem)
 	return &orderItem, nil
 }
 
-func (s *Service) CreateProductSnapshot(ctx context.Context, orderID int, productID int) (string, error) {
+func (s *Service) CreateProductSnapshot(ctx context.Context, orderID int, productID int, variantID int) (string, error) {
 	if orderID <= 0 || productID <= 0 {
 		return "", fmt.Errorf("order id and product id cannot be empty")
 	}
 
-	orderItem, err := s.GetOrderItemByOrderIDAndProductID(ctx, orderID, productID)
+	productDetail, err := s.GetProductSnapshotDetails(ctx, productID, variantID)
 	if err != nil {
 		return "", err
 	}
 
-	// maybe can use redis here to save grpc details
-	productDetail, err := s.GetProductDetails(ctx, productID)
-	if err != nil {
-		return "", err
+	return s.BuildProductSnapshot(productDetail)
+}
+
+// BuildProductSnapshot renders the product as it was sold, to be stored on the
+// order item.
+func (s *Service) BuildProductSnapshot(productDetail *ProductDetail) (string, error) {
+	if productDetail == nil {
+		return "", fmt.Errorf("product details are missing")
+	}
+
+	var (
+		snapshotVariantID                       *int
+		categoryPath, primaryImage, taxCategory *string
+	)
+	if productDetail.VariantID > 0 {
+		snapshotVariantID = &productDetail.VariantID
+	}
+	if productDetail.CategoryPath != "" {
+		categoryPath = &productDetail.CategoryPath
+	}
+	if productDetail.PrimaryImage != "" {
+		primaryImage = &productDetail.PrimaryImage
+	}
+	if productDetail.TaxCategory != "" {
+		taxCategory = &productDetail.TaxCategory
 	}
 
 	productSnapshot := model.ProductSnapshot{
-		ID:              productID,
+		ID:              productDetail.ID,
+		VariantID:       snapshotVariantID,
+		Options:         productDetail.Options,
 		Name:            productDetail.Name,
 		Description:     productDetail.Description,
 		SellerID:        (int(productDetail.SellerID)),
 		ShopName:        productDetail.ShopName,
-		PriceAtPurchase: orderItem.PriceAtPurchase,
+		PriceAtPurchase: productDetail.Price,
+		ListPrice:       productDetail.ListPrice,
+		Currency:        productDetail.Currency,
 		SKU:             productDetail.SKU,
-		PrimaryImage:    nil,
-		TaxCategory:     nil,
+		CategoryPath:    categoryPath,
+		PrimaryImage:    primaryImage,
+		TaxCategory:     taxCategory,
+		Attributes:      productDetail.Attributes,
+		ProductType:     productDetail.Type,
+		Components:      productDetail.Components,
 		CapturedAt:      time.Now(),
 	}
 
@@ -229,12 +402,12 @@ func (s *Service) CreateProductSnapshot(ctx context.Context, orderID int, produc
 	return string(snapshotJSON), nil
 }
 
-func (s *Service) GetOrderItemByOrderIDAndProductID(ctx context.Context, orderID int, productID int) (*model.OrderItem, error) {
+func (s *Service) GetOrderItemByOrderIDAndProductID(ctx context.Context, orderID int, productID int, variantID int) (*model.OrderItem, error) {
 	var (
 		orderItem *model.OrderItem
 	)
 
-	err := s.DB.Model(&orderItem).Scopes(tools.IsDeletedAtNull).Where("order_id = ? AND product_id = ?", orderID, productID).Find(&orderItem).Error
+	err := s.DB.Model(&orderItem).Scopes(tools.IsDeletedAtNull).Where("order_id = ? AND product_id = ? AND variant_id = ?", orderID, productID, variantID).Find(&orderItem).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, fmt.Errorf("order record not found")
 	} else if err != nil {
@@ -253,6 +426,85 @@ func (s *Service) CheckOrderExists(ctx context.Context, orderID int) (bool, erro
 	return count > 0, nil
 }
 
+// OrderGetCompletedPurchase returns the id of the latest completed order in
+// which the user bought the product, or 0 when there is none.
+func (s *Service) OrderGetCompletedPurchase(ctx context.Context, userID int, productID int) (int, error) {
+	var orderIDs []int
+
+	if err := s.DB.Model(&model.Order{}).
+		Joins("JOIN order_item ON order_item.order_id = `order`.id").
+		Where("`order`.user_id = ? AND `order`.status = ? AND `order`.deleted_at IS NULL", userID, ORDER_STATUS_COMPLETED).
+		Where("order_item.product_id = ? AND order_item.deleted_at IS NULL", productID).
+		Order("`order`.id DESC").
+		Limit(1).
+		Pluck("`order`.id", &orderIDs).Error; err != nil {
+		return 0, err
+	}
+
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+
+	return orderIDs[0], nil
+}
+
+// OrderItemGetDailySales totals the quantity sold of each product per day,
+// leaving out cancelled orders.
+func (s *Service) OrderItemGetDailySales(ctx context.Context, productIDs []int, since time.Time) ([]*model.DailySales, error) {
+	sales := []*model.DailySales{}
+
+	if len(productIDs) == 0 {
+		return sales, nil
+	}
+
+	if err := s.DB.Model(&model.OrderItem{}).
+		Select("order_item.product_id, DATE_FORMAT(`order`.created_at, '%Y-%m-%d') AS day, SUM(order_item.quantity) AS quantity").
+		Joins("JOIN `order` ON `order`.id = order_item.order_id").
+		Where("order_item.product_id IN (?) AND order_item.deleted_at IS NULL", productIDs).
+		Where("`order`.created_at >= ? AND `order`.status <> ? AND `order`.deleted_at IS NULL", since, ORDER_STATUS_CANCELLED).
+		Group("order_item.product_id, day").
+		Order("order_item.product_id ASC").Order("day ASC").
+		Scan(&sales).Error; err != nil {
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+// OrderItemGetCoPurchases counts, for every pair of products bought in the
+// same completed order since the given time, how many orders contained both.
+// Each pair is returned in both directions. The number of completed orders
+// containing each product is returned alongside so the pairs can be scored.
+func (s *Service) OrderItemGetCoPurchases(ctx context.Context, since time.Time) ([]*model.CoPurchase, []*model.ProductOrders, error) {
+	var (
+		pairs    = []*model.CoPurchase{}
+		products = []*model.ProductOrders{}
+	)
+
+	if err := s.DB.Table("order_item AS item").
+		Select("item.product_id, related.product_id AS related_product_id, COUNT(DISTINCT item.order_id) AS orders").
+		Joins("JOIN order_item AS related ON related.order_id = item.order_id AND related.product_id <> item.product_id AND related.deleted_at IS NULL").
+		Joins("JOIN `order` ON `order`.id = item.order_id").
+		Where("item.deleted_at IS NULL").
+		Where("`order`.created_at >= ? AND `order`.status = ? AND `order`.deleted_at IS NULL", since, ORDER_STATUS_COMPLETED).
+		Group("item.product_id, related.product_id").
+		Scan(&pairs).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.DB.Model(&model.OrderItem{}).
+		Select("order_item.product_id, COUNT(DISTINCT order_item.order_id) AS orders").
+		Joins("JOIN `order` ON `order`.id = order_item.order_id").
+		Where("order_item.deleted_at IS NULL").
+		Where("`order`.created_at >= ? AND `order`.status = ? AND `order`.deleted_at IS NULL", since, ORDER_STATUS_COMPLETED).
+		Group("order_item.product_id").
+		Scan(&products).Error; err != nil {
+		return nil, nil, err
+	}
+
+	return pairs, products, nil
+}
+
 func (s *Service) OrderGetHistoryByUserID(ctx context.Context) ([]*model.Order, error) {
 	var (
 		orders  []*model.Order
@@ -278,44 +530,6 @@ func (s *Service) OrderGetTrackingInfo(orderID int) ([]*model.OrderTracking, err
 	return trackingInfo, nil
 }
 
-func (s *Service) OrderUpdateStatus(orderID int, status string) (bool, error) {
-	if orderID <= 0 || status == "" {
-		return false, fmt.Errorf("invalid input to update order status")
-	}
-
-	orderExist, err := s.CheckOrderExists(context.Background(), orderID)
-	if err != nil {
-		return false, err
-	}
-	if !orderExist {
-		return false, fmt.Errorf("order does not exist")
-	}
-
-	if !s.isValidOrderStatus(status) {
-		return false, fmt.Errorf("invalid order status")
-	}
-
-	if err := s.DB.Model(&model.Order{}).Where("id = ?", orderID).Update("status", status).Error; err != nil {
-		return false, err
-	}
-
-	trackingInfo := &model.OrderTracking{
-		OrderID:     orderID,
-		Status:      status,
-		Description: "",
-	}
-
-	addTrackingInfo, err := s.OrderAddTrackingInfo(orderID, trackingInfo)
-	if err != nil {
-		return false, err
-	}
-	if !addTrackingInfo {
-		return false, fmt.Errorf("failed to add tracking info")
-	}
-
-	return true, nil
-}
-
 func (s *Service) OrderAddTrackingInfo(orderID int, trackingInfo *model.OrderTracking) (bool, error) {
 	if orderID <= 0 || trackingInfo == nil || trackingInfo.Status == "" {
 		return false, fmt.Errorf("invalid input to add tracking info")
@@ -327,12 +541,3 @@ func (s *Service) OrderAddTrackingInfo(orderID int, trackingInfo *model.OrderTra
 
 	return true, nil
 }
-
-func (s *Service) isValidOrderStatus(status string) bool {
-	switch status {
-	case string(ORDER_STATUS_PENDING), string(ORDER_STATUS_PAID), string(ORDER_STATUS_SHIPPED), string(ORDER_STATUS_CANCELLED), string(ORDER_STATUS_COMPLETED):
-		return true
-	default:
-		return false
-	}
-}
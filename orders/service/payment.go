@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"orders/model"
+	"strings"
+	"utils/currency"
+)
+
+// OrderConfirmPayment marks a card order as paid once the payment provider
+// has captured its total. It acts as the system, and a confirmation for an
+// order that is already past pending is accepted again so the provider can
+// retry safely.
+func (s *Service) OrderConfirmPayment(ctx context.Context, orderID int, amount float64, code string, reference string) (*model.Order, error) {
+	order, err := s.orderLock(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.PaymentMethod != string(PAYMENT_METHOD_CARD) {
+		return nil, fmt.Errorf("order is not paid by card")
+	}
+
+	switch OrderStatus(order.Status) {
+	case ORDER_STATUS_PAID, ORDER_STATUS_SHIPPED, ORDER_STATUS_COMPLETED:
+		return order, nil
+	case ORDER_STATUS_CANCELLED:
+		return nil, fmt.Errorf("order was cancelled before it was paid")
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(code), order.Currency) {
+		return nil, fmt.Errorf("payment is in %s but the order is in %s", code, order.Currency)
+	}
+	if currency.Round(amount, order.Currency) != currency.Round(order.TotalAmount, order.Currency) {
+		return nil, fmt.Errorf("payment of %.2f does not match the order total of %.2f", amount, order.TotalAmount)
+	}
+
+	note := ""
+	if reference = strings.TrimSpace(reference); reference != "" {
+		note = "payment " + reference
+	}
+
+	if _, err := s.orderTransitionApply(order, ORDER_STATUS_PAID, []OrderRole{ORDER_ROLE_SYSTEM}, note); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -4,53 +4,304 @@ import (
 	"context"
 	"fmt"
 	grpcclient "orders/grpc_client"
+	"orders/model"
 	"utils/product"
 )
 
 type ProductDetail struct {
-	ID          int
-	SellerID    int
-	Name        string
-	Description string
-	Price       float64
-	Stock       int
-	SKU         string
-	ShopName    string
+	ID           int
+	SellerID     int
+	Name         string
+	Description  string
+	Price        float64
+	ListPrice    float64
+	OnSale       bool
+	Stock        int
+	SKU          string
+	ShopName     string
+	CategoryPath string
+	ImageURLs    []string
+	PrimaryImage string
+	HasVariants  bool
+	VariantID    int
+	Options      map[string]string
+	Deleted      bool
+	Status       string
+	Attributes   []*model.SnapshotAttribute
+	Type         string
+	Components   []*model.SnapshotComponent
+	TaxCategory  string
+	// Currency is what Price and ListPrice are in. BaseCurrency is what the
+	// product is listed in, and ExchangeRate converts from it to Currency.
+	Currency     string
+	BaseCurrency string
+	ExchangeRate float64
+}
+
+// productStatusActive is the only lifecycle status a product can be bought in.
+const productStatusActive = "active"
+
+// Purchasable reports whether the product is currently on sale.
+func (p *ProductDetail) Purchasable() bool {
+	return !p.Deleted && p.Status == productStatusActive
 }
 
 func (s *Service) GetProductDetails(ctx context.Context, id int) (*ProductDetail, error) {
-	if id <= 0 {
+	return s.GetProductVariantDetails(ctx, id, 0)
+}
+
+// GetProductVariantDetails returns the product with price, stock and sku taken
+// from the given variant. A variant id of 0 returns the base product.
+func (s *Service) GetProductVariantDetails(ctx context.Context, id int, variantID int) (*ProductDetail, error) {
+	return s.getProductDetails(ctx, id, variantID, false)
+}
+
+// GetProductSnapshotDetails is GetProductVariantDetails for products that were
+// already sold; it still resolves products the seller has since deleted.
+func (s *Service) GetProductSnapshotDetails(ctx context.Context, id int, variantID int) (*ProductDetail, error) {
+	return s.getProductDetails(ctx, id, variantID, true)
+}
+
+func (s *Service) getProductDetails(ctx context.Context, id int, variantID int, includeDeleted bool) (*ProductDetail, error) {
+	if id <= 0 || variantID < 0 {
 		return nil, fmt.Errorf("product id is invalid")
 	}
 
-	product, err := grpcclient.GetProductDetails(ctx, &product.GetProductDetailsRequest{Id: int64(id)})
+	product, err := grpcclient.GetProductDetails(ctx, &product.GetProductDetailsRequest{
+		Id:             int64(id),
+		VariantId:      int64(variantID),
+		IncludeDeleted: includeDeleted,
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	return toProductDetail(product), nil
+}
+
+// ProductLookup names a product, or one of its variants, to fetch with
+// GetProductsDetails.
+type ProductLookup struct {
+	ProductID int
+	VariantID int
+}
+
+// GetProductsDetails fetches many products in a single call, with prices in
+// the given currency or, when it is empty, in each product's own. Results are
+// keyed by product.LookupKey; products that could not be found are left out
+// and reported in the second map instead.
+func (s *Service) GetProductsDetails(ctx context.Context, lookups []ProductLookup, includeDeleted bool, currency string) (map[string]*ProductDetail, map[string]string, error) {
+	var (
+		details  = make(map[string]*ProductDetail, len(lookups))
+		notFound = make(map[string]string)
+	)
+
+	if len(lookups) == 0 {
+		return details, notFound, nil
+	}
+
+	req := &product.GetProductsDetailsRequest{IncludeDeleted: includeDeleted, Currency: currency}
+	for _, lookup := range lookups {
+		if lookup.ProductID <= 0 || lookup.VariantID < 0 {
+			return nil, nil, fmt.Errorf("product id is invalid")
+		}
+
+		req.Items = append(req.Items, &product.ProductLookup{
+			Id:        int64(lookup.ProductID),
+			VariantId: int64(lookup.VariantID),
+		})
+	}
+
+	resp, err := grpcclient.GetProductsDetails(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for key, result := range resp.Products {
+		if !result.Found || result.Product == nil {
+			notFound[key] = result.Error
+			continue
+		}
+
+		details[key] = toProductDetail(result.Product)
+	}
+
+	return details, notFound, nil
+}
+
+// GetCartItemsProducts fetches the product behind every cart item and fails if
+// any of them is no longer available.
+func (s *Service) GetCartItemsProducts(ctx context.Context, items []*model.CartItem, includeDeleted bool, currency string) (map[string]*ProductDetail, error) {
+	lookups := make([]ProductLookup, 0, len(items))
+	for _, item := range items {
+		lookups = append(lookups, ProductLookup{ProductID: item.ProductID, VariantID: item.VariantID})
+	}
+
+	details, notFound, err := s.GetProductsDetails(ctx, lookups, includeDeleted, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product details: %w", err)
+	}
+
+	for _, item := range items {
+		key := product.LookupKey(int64(item.ProductID), int64(item.VariantID))
+		if _, ok := details[key]; !ok {
+			return nil, fmt.Errorf("product %d is no longer available: %s", item.ProductID, notFound[key])
+		}
+	}
+
+	return details, nil
+}
+
+func toProductDetail(product *product.GetProductDetailsResponse) *ProductDetail {
 	productDetails := ProductDetail{
-		ID:          int(product.Id),
-		SellerID:    int(product.SellerId),
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		Stock:       int(product.Stock),
-		SKU:         product.Sku,
-		ShopName:    product.ShopName,
+		ID:           int(product.Id),
+		SellerID:     int(product.SellerId),
+		Name:         product.Name,
+		Description:  product.Description,
+		Price:        product.EffectivePrice,
+		ListPrice:    product.ListPrice,
+		Currency:     product.Currency,
+		BaseCurrency: product.BaseCurrency,
+		ExchangeRate: product.ExchangeRate,
+		OnSale:       product.OnSale,
+		Stock:        int(product.Stock),
+		SKU:          product.Sku,
+		ShopName:     product.ShopName,
+		CategoryPath: product.CategoryPath,
+		ImageURLs:    product.ImageUrls,
+		PrimaryImage: product.PrimaryImage,
+		HasVariants:  product.HasVariants,
+		Deleted:      product.Deleted,
+		Status:       product.Status,
+		Type:         product.Type,
+		TaxCategory:  product.TaxCategory,
 	}
 
-	return &productDetails, nil
+	for _, component := range product.Components {
+		snapshotComponent := &model.SnapshotComponent{
+			ProductID: int(component.ProductId),
+			Quantity:  int(component.Quantity),
+			Name:      component.Name,
+			SKU:       component.Sku,
+		}
+		if component.VariantId > 0 {
+			variantID := int(component.VariantId)
+			snapshotComponent.VariantID = &variantID
+		}
+		productDetails.Components = append(productDetails.Components, snapshotComponent)
+	}
+
+	for _, attribute := range product.Attributes {
+		productDetails.Attributes = append(productDetails.Attributes, &model.SnapshotAttribute{
+			Code:  attribute.Code,
+			Name:  attribute.Name,
+			Type:  attribute.Type,
+			Unit:  attribute.Unit,
+			Value: attribute.Value,
+		})
+	}
+
+	if product.Variant != nil {
+		productDetails.VariantID = int(product.Variant.Id)
+		productDetails.Options = product.Variant.Options
+	}
+
+	return &productDetails
 }
 
-func (s *Service) UpdateStock(ctx context.Context, id int, qty int) (bool, error) {
-	if id <= 0 || qty <= 0 {
+func (s *Service) UpdateStock(ctx context.Context, id int, variantID int, qty int) (bool, error) {
+	if id <= 0 || variantID < 0 || qty <= 0 {
 		return false, fmt.Errorf("invalid input to update stock")
 	}
 
-	stockUpdated, err := grpcclient.UpdateStock(ctx, &product.UpdateStockRequest{Id: int64(id), QtyBought: int64(qty)})
+	stockUpdated, err := grpcclient.UpdateStock(ctx, &product.UpdateStockRequest{Id: int64(id), VariantId: int64(variantID), QtyBought: int64(qty)})
 	if err != nil {
 		return false, err
 	}
 
 	return stockUpdated.Success, nil
 }
+
+func (s *Service) ReserveStock(ctx context.Context, items []*model.CartItem) (int, error) {
+	if len(items) == 0 {
+		return 0, fmt.Errorf("no items to reserve")
+	}
+
+	req := &product.ReserveStockRequest{}
+	for _, item := range items {
+		req.Items = append(req.Items, &product.StockItem{
+			ProductId: int64(item.ProductID),
+			VariantId: int64(item.VariantID),
+			Quantity:  int64(item.Quantity),
+		})
+	}
+
+	reservation, err := grpcclient.ReserveStock(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(reservation.ReservationId), nil
+}
+
+func (s *Service) CommitReservation(ctx context.Context, reservationID int, orderID int) (bool, error) {
+	if reservationID <= 0 {
+		return false, fmt.Errorf("invalid reservation id")
+	}
+
+	committed, err := grpcclient.CommitReservation(ctx, &product.ReservationRequest{ReservationId: int64(reservationID), OrderId: int64(orderID)})
+	if err != nil {
+		return false, err
+	}
+
+	return committed.Success, nil
+}
+
+func (s *Service) ReleaseReservation(ctx context.Context, reservationID int) (bool, error) {
+	if reservationID <= 0 {
+		return false, fmt.Errorf("invalid reservation id")
+	}
+
+	released, err := grpcclient.ReleaseReservation(ctx, &product.ReservationRequest{ReservationId: int64(reservationID)})
+	if err != nil {
+		return false, err
+	}
+
+	return released.Success, nil
+}
+
+type StockRestoreReason string
+
+const (
+	STOCK_RESTORE_ORDER_CANCEL StockRestoreReason = "order_cancel"
+	STOCK_RESTORE_RETURN       StockRestoreReason = "return"
+)
+
+// RestoreStock gives the stock of order items back to products. reference
+// names the restock, so calling again with the same reference does not add
+// the stock twice.
+func (s *Service) RestoreStock(ctx context.Context, orderID int, reason StockRestoreReason, reference string, items []*model.OrderItem) (bool, error) {
+	if orderID <= 0 || reference == "" || len(items) == 0 {
+		return false, fmt.Errorf("invalid input to restore stock")
+	}
+
+	req := &product.IncrementStockRequest{
+		OrderId:   int64(orderID),
+		Reason:    string(reason),
+		Reference: reference,
+	}
+	for _, item := range items {
+		req.Items = append(req.Items, &product.StockItem{
+			ProductId: int64(item.ProductID),
+			VariantId: int64(item.VariantID),
+			Quantity:  int64(item.Quantity),
+		})
+	}
+
+	restored, err := grpcclient.IncrementStock(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	return restored.Success, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"orders/model"
+	"utils/currency"
+
+	"gorm.io/gorm"
+)
+
+// RefundCreate records a refund of amount on the order, for a return when
+// returnID is set, and adds it to the order's refunded amount. An order
+// cannot be refunded more than its total.
+func (s *Service) RefundCreate(ctx context.Context, order *model.Order, amount float64, reason string, returnID *int) (*model.Refund, error) {
+	amount = currency.Round(amount, order.Currency)
+	if amount <= 0 {
+		return nil, fmt.Errorf("refund amount must be positive")
+	}
+
+	if remaining := currency.Round(order.TotalAmount-order.RefundedAmount, order.Currency); amount > remaining {
+		return nil, fmt.Errorf("refund of %.2f is more than the %.2f left to refund", amount, remaining)
+	}
+
+	if len(reason) > 100 {
+		reason = reason[:100]
+	}
+
+	refund := model.Refund{
+		OrderID:       order.ID,
+		ReturnID:      returnID,
+		Amount:        amount,
+		Currency:      order.Currency,
+		PaymentMethod: order.PaymentMethod,
+		Status:        string(model.REFUND_STATUS_PENDING),
+		Reason:        reason,
+	}
+
+	if err := s.DB.Create(&refund).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(order).Update("refunded_amount", gorm.Expr("refunded_amount + ?", amount)).Error; err != nil {
+		return nil, err
+	}
+
+	order.RefundedAmount = currency.Round(order.RefundedAmount+amount, order.Currency)
+
+	return &refund, nil
+}
+
+func (s *Service) RefundGetByOrderID(ctx context.Context, orderID int) ([]*model.Refund, error) {
+	var refunds []*model.Refund
+
+	if err := s.DB.Model(&refunds).Where("order_id = ?", orderID).Order("id ASC").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
@@ -0,0 +1,581 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"orders/model"
+	"sort"
+	"strings"
+	"utils/currency"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReturnReason string
+
+const (
+	RETURN_REASON_DAMAGED          ReturnReason = "damaged"
+	RETURN_REASON_WRONG_ITEM       ReturnReason = "wrong_item"
+	RETURN_REASON_NOT_AS_DESCRIBED ReturnReason = "not_as_described"
+	RETURN_REASON_NO_LONGER_NEEDED ReturnReason = "no_longer_needed"
+	RETURN_REASON_OTHER            ReturnReason = "other"
+)
+
+const maxReturnPhotos = 5
+
+// returnReasons are the reasons a buyer can give for a return, with the text
+// shown in the return's history.
+var returnReasons = map[ReturnReason]string{
+	RETURN_REASON_DAMAGED:          "arrived damaged",
+	RETURN_REASON_WRONG_ITEM:       "wrong item sent",
+	RETURN_REASON_NOT_AS_DESCRIBED: "not as described",
+	RETURN_REASON_NO_LONGER_NEEDED: "no longer needed",
+	RETURN_REASON_OTHER:            "other",
+}
+
+type returnTransition struct {
+	From        model.ReturnStatus
+	To          model.ReturnStatus
+	Description string
+}
+
+// returnTransitions are the steps a return goes through after it has been
+// requested, all taken by the seller or an admin. rejected and refunded are
+// final.
+var returnTransitions = []returnTransition{
+	{From: model.RETURN_STATUS_REQUESTED, To: model.RETURN_STATUS_APPROVED, Description: "Return approved"},
+	{From: model.RETURN_STATUS_REQUESTED, To: model.RETURN_STATUS_REJECTED, Description: "Return rejected"},
+	{From: model.RETURN_STATUS_APPROVED, To: model.RETURN_STATUS_RECEIVED, Description: "Returned items received"},
+	{From: model.RETURN_STATUS_RECEIVED, To: model.RETURN_STATUS_REFUNDED, Description: "Refund issued"},
+}
+
+// ReturnCreate requests a return of items of a completed order for its buyer.
+// An item cannot be returned more times than it was bought, counting earlier
+// returns that were not rejected.
+func (s *Service) ReturnCreate(ctx context.Context, orderID int, user *middleware.User, input model.NewReturn) (*model.ReturnRequest, error) {
+	reason, note, err := returnValidateReason(input.Reason, input.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := returnValidatePhotos(input.Photos)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Items) == 0 {
+		return nil, fmt.Errorf("invalid input: select the items to return")
+	}
+
+	order, err := s.orderLock(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || order.UserID != user.ID {
+		return nil, ErrOrderTransitionForbidden
+	}
+	if order.Status != string(ORDER_STATUS_COMPLETED) {
+		return nil, fmt.Errorf("only completed orders can be returned")
+	}
+
+	orderItems, err := s.OrderGetItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[int]*model.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		items[item.ID] = item
+	}
+
+	returned, err := s.returnGetReturnedQuantities(order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sellerID    int
+		returnItems []*model.ReturnItem
+		seen        = make(map[int]bool)
+	)
+
+	for _, input := range input.Items {
+		item, ok := items[input.OrderItemID]
+		if !ok {
+			return nil, fmt.Errorf("item %d is not part of this order", input.OrderItemID)
+		}
+		if seen[item.ID] {
+			return nil, fmt.Errorf("item %d is listed more than once", item.ID)
+		}
+		seen[item.ID] = true
+
+		if input.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid input: quantity of item %d must be positive", item.ID)
+		}
+		if left := item.Quantity - returned[item.ID]; input.Quantity > left {
+			return nil, fmt.Errorf("only %d of item %d can still be returned", left, item.ID)
+		}
+
+		if sellerID == 0 {
+			sellerID = item.SellerID
+		} else if item.SellerID != sellerID {
+			return nil, fmt.Errorf("items from different sellers must be returned separately")
+		}
+
+		returnItems = append(returnItems, &model.ReturnItem{
+			OrderItemID: item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    input.Quantity,
+		})
+	}
+
+	request := model.ReturnRequest{
+		OrderID:  order.ID,
+		UserID:   order.UserID,
+		SellerID: sellerID,
+		Status:   string(model.RETURN_STATUS_REQUESTED),
+		Reason:   string(reason),
+		Note:     note,
+	}
+
+	if err := s.DB.Create(&request).Error; err != nil {
+		return nil, err
+	}
+
+	for _, item := range returnItems {
+		item.ReturnID = request.ID
+		if err := s.DB.Create(item).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, photo := range photos {
+		if err := s.DB.Create(&model.ReturnPhoto{ReturnID: request.ID, URL: photo}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	description := returnReasons[reason]
+	if reason == RETURN_REASON_OTHER {
+		description = note
+	}
+
+	if err := s.returnAddTracking(request.ID, model.RETURN_STATUS_REQUESTED, "Return requested: "+description, ORDER_ROLE_BUYER); err != nil {
+		return nil, err
+	}
+
+	return s.ReturnGetByID(ctx, request.ID)
+}
+
+// ReturnApprove accepts a requested return, so the buyer can send the items.
+func (s *Service) ReturnApprove(ctx context.Context, id int, user *middleware.User, input model.ReviewReturn) (*model.ReturnRequest, error) {
+	return s.returnReview(ctx, id, user, model.RETURN_STATUS_APPROVED, strings.TrimSpace(input.Note))
+}
+
+// ReturnReject turns down a requested return. The buyer is shown the note, so
+// it is required.
+func (s *Service) ReturnReject(ctx context.Context, id int, user *middleware.User, input model.ReviewReturn) (*model.ReturnRequest, error) {
+	note := strings.TrimSpace(input.Note)
+	if note == "" {
+		return nil, fmt.Errorf("invalid input: a note is required to reject a return")
+	}
+
+	return s.returnReview(ctx, id, user, model.RETURN_STATUS_REJECTED, note)
+}
+
+func (s *Service) returnReview(ctx context.Context, id int, user *middleware.User, to model.ReturnStatus, note string) (*model.ReturnRequest, error) {
+	if len(note) > 255 {
+		return nil, fmt.Errorf("invalid input: note must be at most 255 characters")
+	}
+
+	request, role, err := s.returnLockAsSeller(id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.returnTransitionApply(request, to, role, note); err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(request).Update("resolution_note", note).Error; err != nil {
+		return nil, err
+	}
+
+	return s.ReturnGetByID(ctx, request.ID)
+}
+
+// ReturnReceive marks the items of an approved return as back with the
+// seller.
+func (s *Service) ReturnReceive(ctx context.Context, id int, user *middleware.User) (*model.ReturnRequest, error) {
+	request, role, err := s.returnLockAsSeller(id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.returnTransitionApply(request, model.RETURN_STATUS_RECEIVED, role, ""); err != nil {
+		return nil, err
+	}
+
+	return s.ReturnGetByID(ctx, request.ID)
+}
+
+// ReturnRefund refunds a received return, in full unless an amount is given,
+// and optionally puts the items back in stock. The refund is added to the
+// order's refunded amount.
+func (s *Service) ReturnRefund(ctx context.Context, id int, user *middleware.User, input model.RefundReturn) (*model.ReturnRequest, error) {
+	note := strings.TrimSpace(input.Note)
+	if len(note) > 255 {
+		return nil, fmt.Errorf("invalid input: note must be at most 255 characters")
+	}
+
+	request, role, err := s.returnLockAsSeller(id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderLock(request.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&request.Items).Where("return_id = ?", request.ID).Order("id ASC").Find(&request.Items).Error; err != nil {
+		return nil, err
+	}
+
+	paid, err := s.returnPaidAmount(ctx, order, request.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	// the full refund cannot be more than is left on the order, which can be
+	// less than the items' price after discounts or earlier refunds
+	amount := paid
+	if remaining := currency.Round(order.TotalAmount-order.RefundedAmount, order.Currency); amount > remaining {
+		amount = remaining
+	}
+	if input.Amount != nil {
+		amount = currency.Round(*input.Amount, order.Currency)
+		if amount <= 0 {
+			return nil, fmt.Errorf("invalid input: refund amount must be positive")
+		}
+		if amount > paid {
+			return nil, fmt.Errorf("refund of %.2f is more than the %.2f paid for the returned items", amount, paid)
+		}
+	}
+
+	description := fmt.Sprintf("%.2f %s", amount, order.Currency)
+	if note != "" {
+		description += " - " + note
+	}
+
+	if err := s.returnTransitionApply(request, model.RETURN_STATUS_REFUNDED, role, description); err != nil {
+		return nil, err
+	}
+
+	refund, err := s.RefundCreate(ctx, order, amount, "return: "+returnReasons[ReturnReason(request.Reason)], &request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Restock {
+		var items []*model.OrderItem
+		for _, item := range request.Items {
+			items = append(items, &model.OrderItem{
+				ProductID: item.ProductID,
+				VariantID: item.VariantID,
+				Quantity:  item.Quantity,
+			})
+		}
+
+		if _, err := s.RestoreStock(ctx, order.ID, STOCK_RESTORE_RETURN, fmt.Sprintf("return-%d", request.ID), items); err != nil {
+			return nil, fmt.Errorf("failed to restock returned items: %w", err)
+		}
+	}
+
+	updates := map[string]interface{}{
+		"refund_id":     refund.ID,
+		"refund_amount": amount,
+		"restocked":     input.Restock,
+	}
+	if note != "" {
+		updates["resolution_note"] = note
+	}
+
+	if err := s.DB.Model(request).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return s.ReturnGetByID(ctx, request.ID)
+}
+
+// ReturnGetByOrderID lists the returns of an order that user can see: all of
+// them for its buyer and admins, and a seller's own for a seller.
+func (s *Service) ReturnGetByOrderID(ctx context.Context, orderID int, user *middleware.User) ([]*model.ReturnRequest, error) {
+	var order model.Order
+
+	if err := s.DB.Model(&order).Where("id = ? AND deleted_at IS NULL", orderID).First(&order).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("order does not exist")
+	} else if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.OrderRoles(ctx, &order, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, ErrOrderTransitionForbidden
+	}
+
+	query := s.DB.Model(&model.ReturnRequest{}).Where("order_id = ?", order.ID)
+	if len(roles) == 1 && roles[0] == ORDER_ROLE_SELLER {
+		query = query.Where("seller_id = ?", user.ID)
+	}
+
+	return s.returnFind(ctx, query)
+}
+
+// ReturnGetBySellerID lists a seller's returns, newest first, optionally only
+// those with the given status.
+func (s *Service) ReturnGetBySellerID(ctx context.Context, sellerID int, status string) ([]*model.ReturnRequest, error) {
+	query := s.DB.Model(&model.ReturnRequest{}).Where("seller_id = ?", sellerID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	return s.returnFind(ctx, query)
+}
+
+func (s *Service) ReturnGetByID(ctx context.Context, id int) (*model.ReturnRequest, error) {
+	requests, err := s.returnFind(ctx, s.DB.Model(&model.ReturnRequest{}).Where("id = ?", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("return not found")
+	}
+
+	return requests[0], nil
+}
+
+// returnFind loads the returns matched by query with their items, photos and
+// history.
+func (s *Service) returnFind(ctx context.Context, query *gorm.DB) ([]*model.ReturnRequest, error) {
+	var (
+		requests []*model.ReturnRequest
+		items    []*model.ReturnItem
+		photos   []*model.ReturnPhoto
+		history  []*model.ReturnTracking
+	)
+
+	if err := query.Order("id DESC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	if len(requests) == 0 {
+		return requests, nil
+	}
+
+	ids := make([]int, 0, len(requests))
+	byID := make(map[int]*model.ReturnRequest, len(requests))
+	for _, request := range requests {
+		ids = append(ids, request.ID)
+		byID[request.ID] = request
+	}
+
+	if err := s.DB.Model(&items).Where("return_id IN (?)", ids).Order("id ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		byID[item.ReturnID].Items = append(byID[item.ReturnID].Items, item)
+	}
+
+	if err := s.DB.Model(&photos).Where("return_id IN (?)", ids).Order("id ASC").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+	for _, photo := range photos {
+		byID[photo.ReturnID].Photos = append(byID[photo.ReturnID].Photos, photo)
+	}
+
+	if err := s.DB.Model(&history).Where("return_id IN (?)", ids).Order("created_at ASC").Order("id ASC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+	for _, entry := range history {
+		byID[entry.ReturnID].History = append(byID[entry.ReturnID].History, entry)
+	}
+
+	return requests, nil
+}
+
+// returnLockAsSeller loads and locks a return for its seller or an admin,
+// and returns the role the user acts as.
+func (s *Service) returnLockAsSeller(id int, user *middleware.User) (*model.ReturnRequest, OrderRole, error) {
+	var request model.ReturnRequest
+
+	if err := s.DB.Model(&request).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&request).Error; err == gorm.ErrRecordNotFound {
+		return nil, "", fmt.Errorf("return not found")
+	} else if err != nil {
+		return nil, "", err
+	}
+
+	switch {
+	case user == nil:
+		return nil, "", ErrOrderTransitionForbidden
+	case user.Seller && request.SellerID == user.ID:
+		return &request, ORDER_ROLE_SELLER, nil
+	case user.Role == string(ORDER_ROLE_ADMIN):
+		return &request, ORDER_ROLE_ADMIN, nil
+	}
+
+	return nil, "", ErrOrderTransitionForbidden
+}
+
+func (s *Service) returnTransitionApply(request *model.ReturnRequest, to model.ReturnStatus, role OrderRole, note string) error {
+	var transition *returnTransition
+	for i := range returnTransitions {
+		if returnTransitions[i].From == model.ReturnStatus(request.Status) && returnTransitions[i].To == to {
+			transition = &returnTransitions[i]
+		}
+	}
+	if transition == nil {
+		return fmt.Errorf("return cannot go from %s to %s", request.Status, to)
+	}
+
+	if err := s.DB.Model(request).Update("status", string(to)).Error; err != nil {
+		return err
+	}
+
+	description := transition.Description
+	if note != "" {
+		description += ": " + note
+	}
+
+	if err := s.returnAddTracking(request.ID, to, description, role); err != nil {
+		return err
+	}
+
+	request.Status = string(to)
+
+	return nil
+}
+
+func (s *Service) returnAddTracking(returnID int, status model.ReturnStatus, description string, role OrderRole) error {
+	if len(description) > 100 {
+		description = description[:100]
+	}
+
+	return s.DB.Create(&model.ReturnTracking{
+		ReturnID:    returnID,
+		Status:      string(status),
+		Description: description,
+		Actor:       string(role),
+	}).Error
+}
+
+// returnGetReturnedQuantities is how many of each order item are already in
+// a return that was not rejected, keyed by order item id.
+func (s *Service) returnGetReturnedQuantities(orderID int) (map[int]int, error) {
+	var rows []struct {
+		OrderItemID int
+		Quantity    int
+	}
+
+	if err := s.DB.Model(&model.ReturnItem{}).
+		Select("return_item.order_item_id, SUM(return_item.quantity) AS quantity").
+		Joins("JOIN return_request ON return_request.id = return_item.return_id").
+		Where("return_request.order_id = ? AND return_request.status <> ?", orderID, model.RETURN_STATUS_REJECTED).
+		Group("return_item.order_item_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	returned := make(map[int]int, len(rows))
+	for _, row := range rows {
+		returned[row.OrderItemID] = row.Quantity
+	}
+
+	return returned, nil
+}
+
+// returnPaidAmount is what the buyer paid for the returned items, tax
+// included.
+func (s *Service) returnPaidAmount(ctx context.Context, order *model.Order, items []*model.ReturnItem) (float64, error) {
+	orderItems, err := s.OrderGetItems(ctx, order.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	byID := make(map[int]*model.OrderItem, len(orderItems))
+	for _, item := range orderItems {
+		byID[item.ID] = item
+	}
+
+	var paid float64
+	for _, item := range items {
+		orderItem, ok := byID[item.OrderItemID]
+		if !ok || orderItem.Quantity <= 0 {
+			return 0, fmt.Errorf("item %d is not part of this order", item.OrderItemID)
+		}
+
+		paid += orderItem.PriceAtPurchase * float64(item.Quantity)
+		if !orderItem.PriceIncludesTax {
+			paid += orderItem.TaxAmount * float64(item.Quantity) / float64(orderItem.Quantity)
+		}
+	}
+
+	return currency.Round(paid, order.Currency), nil
+}
+
+func returnValidateReason(code string, note string) (ReturnReason, string, error) {
+	reason := ReturnReason(strings.ToLower(strings.TrimSpace(code)))
+	if _, ok := returnReasons[reason]; !ok {
+		return "", "", fmt.Errorf("invalid input: reason must be one of %s", returnReasonCodes())
+	}
+
+	note = strings.TrimSpace(note)
+	if reason == RETURN_REASON_OTHER && note == "" {
+		return "", "", fmt.Errorf("invalid input: a note is required when the reason is other")
+	}
+	if len(note) > 255 {
+		return "", "", fmt.Errorf("invalid input: note must be at most 255 characters")
+	}
+
+	return reason, note, nil
+}
+
+func returnReasonCodes() string {
+	codes := make([]string, 0, len(returnReasons))
+	for code := range returnReasons {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+
+	return strings.Join(codes, ", ")
+}
+
+func returnValidatePhotos(photos []string) ([]string, error) {
+	if len(photos) > maxReturnPhotos {
+		return nil, fmt.Errorf("invalid input: at most %d photos can be attached", maxReturnPhotos)
+	}
+
+	var valid []string
+	for _, photo := range photos {
+		photo = strings.TrimSpace(photo)
+		if photo == "" {
+			continue
+		}
+
+		link, err := url.ParseRequestURI(photo)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" || len(photo) > 255 {
+			return nil, fmt.Errorf("invalid input: photo %q is not a valid link", photo)
+		}
+
+		valid = append(valid, photo)
+	}
+
+	return valid, nil
+}
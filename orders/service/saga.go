@@ -0,0 +1,483 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"orders/model"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// sagaStaleAfter is how long a saga can go without progress before
+	// recovery takes it over from the process that was running it.
+	sagaStaleAfter  = 2 * time.Minute
+	maxSagaAttempts = 5
+)
+
+var checkoutSagaSteps = []model.SagaStep{
+	model.SAGA_STEP_RESERVE_STOCK,
+	model.SAGA_STEP_CREATE_ORDER,
+	model.SAGA_STEP_CLEAR_CART,
+	model.SAGA_STEP_COMMIT_STOCK,
+}
+
+// sagaAction runs one checkout step. The step is marked done in the same
+// transaction, so a local step that is not done has left nothing behind.
+type sagaAction func(tx *Service) error
+
+// checkoutSagaStart records a checkout before any of its steps run, so that
+// recovery can find it if the process stops part way.
+func checkoutSagaStart(userID int, cartID int, items []*model.CartItem) (*model.CheckoutSaga, error) {
+	cartItems, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := GetTransaction()
+
+	saga := model.CheckoutSaga{
+		UserID:    userID,
+		CartID:    cartID,
+		Status:    string(model.SAGA_STATUS_RUNNING),
+		CartItems: string(cartItems),
+	}
+
+	if err := tx.DB.Create(&saga).Error; err != nil {
+		tx.DB.Rollback()
+		return nil, err
+	}
+
+	for i, step := range checkoutSagaSteps {
+		sagaStep := model.CheckoutSagaStep{
+			SagaID:   saga.ID,
+			Step:     string(step),
+			Position: i,
+			Status:   string(model.SAGA_STEP_STATUS_PENDING),
+		}
+
+		if err := tx.DB.Create(&sagaStep).Error; err != nil {
+			tx.DB.Rollback()
+			return nil, err
+		}
+
+		saga.Steps = append(saga.Steps, &sagaStep)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &saga, nil
+}
+
+// checkoutSagaRun runs every step in order. When a step before the cart is
+// cleared fails, the steps that were done are compensated in reverse and the
+// step's error is returned.
+func (s *Service) checkoutSagaRun(ctx context.Context, saga *model.CheckoutSaga, actions map[model.SagaStep]sagaAction) error {
+	for _, step := range saga.Steps {
+		if err := s.checkoutSagaExec(saga, step, actions[model.SagaStep(step.Step)]); err != nil {
+			// the order stands once the cart is cleared, so a failed stock
+			// commit is left for recovery to retry rather than undone
+			if checkoutSagaPastNoReturn(saga) {
+				log.Printf("checkout saga %d: %v, leaving it for recovery to retry", saga.ID, err)
+				return nil
+			}
+
+			if cerr := s.checkoutSagaCompensate(ctx, saga, err); cerr != nil {
+				log.Printf("failed to compensate checkout saga %d: %v", saga.ID, cerr)
+			}
+			return err
+		}
+	}
+
+	return sagaSetStatus(GetService().DB, saga, model.SAGA_STATUS_COMPLETED, nil)
+}
+
+func (s *Service) checkoutSagaExec(saga *model.CheckoutSaga, step *model.CheckoutSagaStep, action sagaAction) error {
+	if err := sagaTouch(GetService().DB, saga); err != nil {
+		return err
+	}
+
+	if err := sagaStepSet(GetService().DB, step, model.SAGA_STEP_STATUS_RUNNING, nil); err != nil {
+		return err
+	}
+
+	tx := GetTransaction()
+
+	err := action(tx)
+	if err == nil {
+		err = sagaStepSet(tx.DB, step, model.SAGA_STEP_STATUS_DONE, nil)
+	}
+	if err != nil {
+		tx.DB.Rollback()
+	} else {
+		err = tx.Commit()
+	}
+
+	if err != nil {
+		sagaStepSet(GetService().DB, step, model.SAGA_STEP_STATUS_FAILED, err)
+		return err
+	}
+
+	return nil
+}
+
+// checkoutSagaCompensate undoes the done steps, last first. A step that
+// cannot be undone stops compensation; the saga stays compensating so that
+// recovery tries again, until it runs out of attempts.
+func (s *Service) checkoutSagaCompensate(ctx context.Context, saga *model.CheckoutSaga, cause error) error {
+	if err := sagaSetStatus(GetService().DB, saga, model.SAGA_STATUS_COMPENSATING, cause); err != nil {
+		return err
+	}
+
+	for i := len(saga.Steps) - 1; i >= 0; i-- {
+		step := saga.Steps[i]
+		status := model.SagaStepStatus(step.Status)
+
+		// the reservation is made before its step is marked done, so it is
+		// released even if the step did not get that far
+		held := model.SagaStep(step.Step) == model.SAGA_STEP_RESERVE_STOCK && saga.ReservationID != nil
+		if status != model.SAGA_STEP_STATUS_DONE && (!held || status == model.SAGA_STEP_STATUS_COMPENSATED) {
+			continue
+		}
+
+		if err := sagaTouch(GetService().DB, saga); err != nil {
+			return err
+		}
+
+		tx := GetTransaction()
+
+		err := tx.checkoutSagaUndo(ctx, saga, step)
+		if err == nil {
+			err = sagaStepSet(tx.DB, step, model.SAGA_STEP_STATUS_COMPENSATED, nil)
+		}
+		if err != nil {
+			tx.DB.Rollback()
+		} else {
+			err = tx.Commit()
+		}
+
+		if err != nil {
+			sagaStepSet(GetService().DB, step, status, err)
+			if saga.Attempts >= maxSagaAttempts {
+				sagaSetStatus(GetService().DB, saga, model.SAGA_STATUS_FAILED, err)
+			}
+			return fmt.Errorf("failed to compensate %s: %w", step.Step, err)
+		}
+	}
+
+	return sagaSetStatus(GetService().DB, saga, model.SAGA_STATUS_COMPENSATED, nil)
+}
+
+func (s *Service) checkoutSagaUndo(ctx context.Context, saga *model.CheckoutSaga, step *model.CheckoutSagaStep) error {
+	switch model.SagaStep(step.Step) {
+	case model.SAGA_STEP_RESERVE_STOCK:
+		if saga.ReservationID != nil {
+			if _, err := s.ReleaseReservation(ctx, *saga.ReservationID); err != nil {
+				return err
+			}
+		}
+	case model.SAGA_STEP_CREATE_ORDER:
+		if saga.OrderID != nil {
+			return s.checkoutSagaCancelOrder(ctx, *saga.OrderID)
+		}
+	case model.SAGA_STEP_CLEAR_CART:
+		return s.checkoutSagaRestoreCart(saga)
+	}
+
+	return nil
+}
+
+func (s *Service) checkoutSagaCancelOrder(ctx context.Context, orderID int) error {
+	_, err := s.OrderTransition(ctx, orderID, ORDER_STATUS_CANCELLED, nil, "checkout could not be completed")
+
+	return err
+}
+
+// checkoutSagaRestoreCart puts the bought items back in the cart. An item
+// whose product has been added to the cart again since is merged into it.
+func (s *Service) checkoutSagaRestoreCart(saga *model.CheckoutSaga) error {
+	var items []*model.CartItem
+
+	if err := json.Unmarshal([]byte(saga.CartItems), &items); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		merged := s.DB.Model(&model.CartItem{}).
+			Where("cart_id = ? AND product_id = ? AND variant_id = ?", item.CartID, item.ProductID, item.VariantID).
+			Update("quantity", gorm.Expr("quantity + ?", item.Quantity))
+		if merged.Error != nil {
+			return merged.Error
+		}
+		if merged.RowsAffected > 0 {
+			continue
+		}
+
+		item.Product = nil
+		if err := s.DB.Create(item).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkoutSagaCommitStock(ctx context.Context, saga *model.CheckoutSaga) sagaAction {
+	return func(tx *Service) error {
+		if saga.ReservationID == nil || saga.OrderID == nil {
+			return fmt.Errorf("checkout has no stock reservation or order")
+		}
+
+		if _, err := tx.CommitReservation(ctx, *saga.ReservationID, *saga.OrderID); err != nil {
+			return fmt.Errorf("failed to commit stock reservation: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// StartCheckoutSagaRecovery finishes or undoes checkouts that stopped part
+// way, such as when the process was restarted mid checkout. It blocks, so run
+// it in its own goroutine.
+func StartCheckoutSagaRecovery(interval time.Duration) {
+	RecoverCheckoutSagas(context.Background())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		RecoverCheckoutSagas(context.Background())
+	}
+}
+
+func RecoverCheckoutSagas(ctx context.Context) {
+	var (
+		s     = GetService()
+		sagas []*model.CheckoutSaga
+	)
+
+	if err := s.DB.Model(&sagas).
+		Where("status IN (?) AND updated_at <= ?", []model.SagaStatus{model.SAGA_STATUS_RUNNING, model.SAGA_STATUS_COMPENSATING}, time.Now().Add(-sagaStaleAfter)).
+		Find(&sagas).Error; err != nil {
+		log.Println("failed to load checkout sagas:", err)
+		return
+	}
+
+	for _, saga := range sagas {
+		claimed, err := checkoutSagaClaim(saga)
+		if err != nil {
+			log.Printf("failed to claim checkout saga %d: %v", saga.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.checkoutSagaRecover(ctx, saga); err != nil {
+			log.Printf("failed to recover checkout saga %d: %v", saga.ID, err)
+		}
+	}
+}
+
+// checkoutSagaRecover picks a stopped saga up where it was left. Once the
+// cart is cleared committing the stock is retried rather than undoing the
+// checkout, until the saga runs out of attempts or the reservation is gone,
+// when the checkout is abandoned.
+func (s *Service) checkoutSagaRecover(ctx context.Context, saga *model.CheckoutSaga) error {
+	if err := s.DB.Model(&saga.Steps).Where("saga_id = ?", saga.ID).Order("position ASC").Find(&saga.Steps).Error; err != nil {
+		return err
+	}
+
+	if saga.Status == string(model.SAGA_STATUS_COMPENSATING) {
+		return s.checkoutSagaCompensate(ctx, saga, fmt.Errorf("%s", saga.Error))
+	}
+
+	if !checkoutSagaPastNoReturn(saga) {
+		return s.checkoutSagaCompensate(ctx, saga, fmt.Errorf("checkout was interrupted"))
+	}
+
+	commit := saga.Steps[len(saga.Steps)-1]
+	if model.SagaStepStatus(commit.Status) != model.SAGA_STEP_STATUS_DONE {
+		if err := s.checkoutSagaExec(saga, commit, checkoutSagaCommitStock(ctx, saga)); err != nil {
+			if saga.Attempts >= maxSagaAttempts || checkoutSagaReservationLost(err) {
+				if aerr := s.checkoutSagaAbandon(ctx, saga, err); aerr != nil {
+					return fmt.Errorf("failed to abandon checkout after %v: %w", err, aerr)
+				}
+			}
+			return err
+		}
+	}
+
+	return sagaSetStatus(s.DB, saga, model.SAGA_STATUS_COMPLETED, nil)
+}
+
+// checkoutSagaAbandon gives up on a checkout whose stock could not be
+// committed after the cart was cleared. Its reservation expires on its own and
+// the stock goes back on sale, so the order cannot be left standing: it is
+// cancelled, refunded if it was paid, and the items go back in the cart. The
+// saga stays running if that fails, so that recovery tries again.
+func (s *Service) checkoutSagaAbandon(ctx context.Context, saga *model.CheckoutSaga, cause error) error {
+	if saga.OrderID != nil {
+		tx := GetTransaction()
+
+		err := tx.checkoutSagaCancelUncommitted(ctx, saga, *saga.OrderID)
+		if err != nil {
+			tx.DB.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	// the reservation may already have expired, which frees the stock too
+	if saga.ReservationID != nil {
+		if _, err := s.ReleaseReservation(ctx, *saga.ReservationID); err != nil {
+			log.Printf("checkout saga %d: failed to release reservation %d: %v", saga.ID, *saga.ReservationID, err)
+		}
+	}
+
+	return sagaSetStatus(s.DB, saga, model.SAGA_STATUS_FAILED, cause)
+}
+
+// checkoutSagaCancelUncommitted cancels an order whose stock was never taken
+// for it. No stock is restored, since the reservation still holds it or has
+// already given it back.
+func (s *Service) checkoutSagaCancelUncommitted(ctx context.Context, saga *model.CheckoutSaga, orderID int) error {
+	order, err := s.orderLock(orderID)
+	if err != nil {
+		return err
+	}
+
+	switch OrderStatus(order.Status) {
+	case ORDER_STATUS_PENDING, ORDER_STATUS_PAID:
+	default:
+		return nil
+	}
+
+	paid := order.Status == string(ORDER_STATUS_PAID)
+
+	if _, err := s.orderTransitionApply(order, ORDER_STATUS_CANCELLED, []OrderRole{ORDER_ROLE_SYSTEM}, "stock could not be confirmed"); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.DB.Model(order).Updates(map[string]interface{}{
+		"cancel_reason": string(CANCEL_REASON_OUT_OF_STOCK),
+		"cancelled_by":  string(ORDER_ROLE_SYSTEM),
+		"cancelled_at":  now,
+	}).Error; err != nil {
+		return err
+	}
+
+	if paid && order.TotalAmount > order.RefundedAmount {
+		if _, err := s.RefundCreate(ctx, order, order.TotalAmount-order.RefundedAmount, "order cancelled: stock could not be confirmed", nil); err != nil {
+			return err
+		}
+	}
+
+	return s.checkoutSagaRestoreCart(saga)
+}
+
+// checkoutSagaReservationLost reports whether a stock commit failed because
+// the reservation is no longer active, which no retry can fix.
+func checkoutSagaReservationLost(err error) bool {
+	return strings.Contains(err.Error(), "reservation is already")
+}
+
+// checkoutSagaPastNoReturn reports whether the cart has been cleared, after
+// which the checkout is finished rather than undone.
+func checkoutSagaPastNoReturn(saga *model.CheckoutSaga) bool {
+	for _, step := range saga.Steps {
+		if model.SagaStep(step.Step) == model.SAGA_STEP_CLEAR_CART {
+			return model.SagaStepStatus(step.Status) == model.SAGA_STEP_STATUS_DONE
+		}
+	}
+
+	return false
+}
+
+// checkoutSagaClaim takes a stale saga for this process. It only succeeds if
+// nothing else has touched the saga since it was loaded.
+func checkoutSagaClaim(saga *model.CheckoutSaga) (bool, error) {
+	now := time.Now()
+
+	claim := GetService().DB.Model(&model.CheckoutSaga{}).
+		Where("id = ? AND updated_at = ?", saga.ID, saga.UpdatedAt).
+		Updates(map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "updated_at": now})
+	if claim.Error != nil {
+		return false, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return false, nil
+	}
+
+	saga.Attempts++
+	saga.UpdatedAt = now
+
+	return true, nil
+}
+
+func sagaSetStatus(db *gorm.DB, saga *model.CheckoutSaga, status model.SagaStatus, cause error) error {
+	updates := map[string]interface{}{"status": string(status)}
+	if cause != nil {
+		updates["error"] = sagaErrorMessage(cause)
+	}
+
+	if err := db.Model(saga).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	saga.Status = string(status)
+	if cause != nil {
+		saga.Error = updates["error"].(string)
+	}
+
+	return nil
+}
+
+// sagaTouch marks a saga as making progress, so recovery does not take over
+// a checkout that is slow but still running. A single step that takes longer
+// than sagaStaleAfter can still be taken over.
+func sagaTouch(db *gorm.DB, saga *model.CheckoutSaga) error {
+	now := time.Now()
+
+	if err := db.Model(&model.CheckoutSaga{}).Where("id = ?", saga.ID).Update("updated_at", now).Error; err != nil {
+		return err
+	}
+
+	saga.UpdatedAt = now
+
+	return nil
+}
+
+func sagaStepSet(db *gorm.DB, step *model.CheckoutSagaStep, status model.SagaStepStatus, cause error) error {
+	message := ""
+	if cause != nil {
+		message = sagaErrorMessage(cause)
+	}
+
+	if err := db.Model(step).Updates(map[string]interface{}{"status": string(status), "error": message}).Error; err != nil {
+		return err
+	}
+
+	step.Status = string(status)
+	step.Error = message
+
+	return nil
+}
+
+func sagaErrorMessage(err error) string {
+	message := err.Error()
+	if len(message) > 255 {
+		message = message[:255]
+	}
+
+	return message
+}
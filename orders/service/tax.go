@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"orders/model"
+	"orders/tools"
+	"strings"
+	"time"
+	"utils/currency"
+	"utils/region"
+
+	"gorm.io/gorm"
+)
+
+// TaxableLine is an order line to be taxed. UnitPrice is the price the
+// product is sold at, which already includes tax when the matching rule says
+// so.
+type TaxableLine struct {
+	Key         string
+	TaxCategory string
+	UnitPrice   float64
+	Quantity    int
+}
+
+func (s *Service) TaxRuleGetAll(ctx context.Context) ([]*model.TaxRule, error) {
+	rules := []*model.TaxRule{}
+
+	if err := s.DB.Model(&rules).Scopes(tools.IsDeletedAtNull).
+		Order("region ASC").Order("tax_category ASC").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *Service) TaxRuleGetByID(ctx context.Context, id int) (*model.TaxRule, error) {
+	var rule model.TaxRule
+
+	if err := s.DB.Model(&rule).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).First(&rule).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("tax rule not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (s *Service) TaxRuleCreate(ctx context.Context, input model.NewTaxRule) (*model.TaxRule, error) {
+	valid, err := s.TaxRuleOnCreate(ctx, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid tax rule")
+	}
+
+	rule := model.TaxRule{
+		Region:           input.Region,
+		TaxCategory:      input.TaxCategory,
+		Rate:             input.Rate,
+		Exempt:           input.Exempt,
+		PriceIncludesTax: input.PriceIncludesTax,
+		Description:      strings.TrimSpace(input.Description),
+	}
+
+	if err := s.DB.Create(&rule).Error; err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (s *Service) TaxRuleOnCreate(ctx context.Context, input *model.NewTaxRule) (bool, error) {
+	ruleRegion, err := normalizeTaxRegion(input.Region)
+	if err != nil {
+		return false, err
+	}
+	input.Region = ruleRegion
+
+	input.TaxCategory = strings.ToLower(strings.TrimSpace(input.TaxCategory))
+	if input.TaxCategory == "" || len(input.TaxCategory) > 30 {
+		return false, fmt.Errorf("invalid input: tax category is required")
+	}
+
+	if input.Rate < 0 || input.Rate > 100 {
+		return false, fmt.Errorf("invalid input: rate must be a percentage between 0 and 100")
+	}
+
+	var count int64
+	if err := s.DB.Model(&model.TaxRule{}).Scopes(tools.IsDeletedAtNull).
+		Where("region = ? AND tax_category = ?", input.Region, input.TaxCategory).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, fmt.Errorf("a tax rule for %s in %s already exists", input.TaxCategory, input.Region)
+	}
+
+	return true, nil
+}
+
+// TaxRuleUpdate changes how a rule taxes. The region and category a rule
+// applies to cannot change; delete it and create another instead.
+func (s *Service) TaxRuleUpdate(ctx context.Context, id int, input model.UpdateTaxRule) (*model.TaxRule, error) {
+	rule, err := s.TaxRuleGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.Rate != nil {
+		if *input.Rate < 0 || *input.Rate > 100 {
+			return nil, fmt.Errorf("invalid input: rate must be a percentage between 0 and 100")
+		}
+		updates["rate"] = *input.Rate
+	}
+	if input.Exempt != nil {
+		updates["exempt"] = *input.Exempt
+	}
+	if input.PriceIncludesTax != nil {
+		updates["price_includes_tax"] = *input.PriceIncludesTax
+	}
+	if input.Description != nil {
+		updates["description"] = strings.TrimSpace(*input.Description)
+	}
+
+	if len(updates) == 0 {
+		return rule, nil
+	}
+
+	if err := s.DB.Model(rule).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return s.TaxRuleGetByID(ctx, id)
+}
+
+func (s *Service) TaxRuleDelete(ctx context.Context, id int) error {
+	if _, err := s.TaxRuleGetByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.DB.Model(&model.TaxRule{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+// TaxCalculate works out the tax of every line shipped to a region, and the
+// order totals. A line no rule matches is not taxed.
+func (s *Service) TaxCalculate(ctx context.Context, shipTo string, code string, lines []TaxableLine) (*model.TaxBreakdown, error) {
+	destination, err := region.Normalize(shipTo)
+	if err != nil {
+		return nil, fmt.Errorf("invalid shipping region: %w", err)
+	}
+
+	regions := []string{model.TAX_RULE_ANY, region.Country(destination), destination}
+
+	var rules []*model.TaxRule
+	if err := s.DB.Model(&rules).Scopes(tools.IsDeletedAtNull).Where("region IN (?)", regions).Find(&rules).Error; err != nil {
+		return nil, err
+	}
+
+	return taxApply(rules, destination, code, lines), nil
+}
+
+// taxApply taxes lines shipped to a region with the rules that can apply
+// there.
+func taxApply(rules []*model.TaxRule, destination string, code string, lines []TaxableLine) *model.TaxBreakdown {
+	breakdown := &model.TaxBreakdown{Region: destination, Currency: code}
+
+	for _, line := range lines {
+		var (
+			amount = currency.Round(line.UnitPrice*float64(line.Quantity), code)
+			rule   = taxRuleMatch(rules, destination, line.TaxCategory)
+			taxed  = &model.TaxLine{Key: line.Key, TaxCategory: line.TaxCategory, Net: amount, Gross: amount}
+		)
+
+		if rule != nil {
+			taxed.RuleID = &rule.ID
+			taxed.Exempt = rule.Exempt
+			taxed.PriceIncludesTax = rule.PriceIncludesTax
+
+			if !rule.Exempt {
+				taxed.Rate = rule.Rate
+
+				if rule.PriceIncludesTax {
+					taxed.Net = currency.Round(amount/(1+rule.Rate/100), code)
+					taxed.Tax = currency.Round(amount-taxed.Net, code)
+				} else {
+					taxed.Tax = currency.Round(amount*rule.Rate/100, code)
+					taxed.Gross = currency.Round(amount+taxed.Tax, code)
+				}
+			}
+		}
+
+		breakdown.Subtotal += taxed.Net
+		breakdown.Tax += taxed.Tax
+		breakdown.Lines = append(breakdown.Lines, taxed)
+	}
+
+	breakdown.Subtotal = currency.Round(breakdown.Subtotal, code)
+	breakdown.Tax = currency.Round(breakdown.Tax, code)
+	breakdown.Total = currency.Round(breakdown.Subtotal+breakdown.Tax, code)
+
+	return breakdown
+}
+
+// taxRuleMatch picks the most specific rule for a category shipped to a
+// region: a subdivision beats its country, which beats everywhere, and a
+// named category beats *.
+func taxRuleMatch(rules []*model.TaxRule, destination string, category string) *model.TaxRule {
+	var (
+		best      *model.TaxRule
+		bestScore = -1
+	)
+
+	for _, rule := range rules {
+		score := 0
+
+		switch {
+		case rule.Region == destination && destination != "":
+			score += 4
+		case rule.Region != model.TAX_RULE_ANY && strings.HasPrefix(destination, rule.Region+"-"):
+			score += 2
+		case rule.Region != model.TAX_RULE_ANY:
+			continue
+		}
+
+		switch rule.TaxCategory {
+		case category:
+			score++
+		case model.TAX_RULE_ANY:
+		default:
+			continue
+		}
+
+		if score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+
+	return best
+}
+
+// normalizeTaxRegion upper-cases the region of a rule: a region code as in
+// DE or US-CA, or the wildcard.
+func normalizeTaxRegion(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == model.TAX_RULE_ANY {
+		return code, nil
+	}
+
+	normalized, err := region.Normalize(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid tax region %q", code)
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"orders/model"
+	"testing"
+)
+
+func TestTaxRuleMatch(t *testing.T) {
+	rules := []*model.TaxRule{
+		{ID: 1, Region: model.TAX_RULE_ANY, TaxCategory: model.TAX_RULE_ANY},
+		{ID: 2, Region: model.TAX_RULE_ANY, TaxCategory: "books"},
+		{ID: 3, Region: "US", TaxCategory: model.TAX_RULE_ANY},
+		{ID: 4, Region: "US", TaxCategory: "food"},
+		{ID: 5, Region: "US-CA", TaxCategory: model.TAX_RULE_ANY},
+		{ID: 6, Region: "US-CA", TaxCategory: "food"},
+		{ID: 7, Region: "DE", TaxCategory: "books"},
+	}
+
+	tests := []struct {
+		name        string
+		destination string
+		category    string
+		want        int
+	}{
+		{"subdivision and category", "US-CA", "food", 6},
+		{"subdivision beats country category", "US-CA", "books", 5},
+		{"country and category", "US-NY", "food", 4},
+		{"country for other subdivision", "US-NY", "toys", 3},
+		{"country without subdivision", "US", "food", 4},
+		{"country category beats everywhere", "DE", "books", 7},
+		{"everywhere category", "FR", "books", 2},
+		{"everywhere any category", "FR", "toys", 1},
+		{"other country falls back", "DE", "food", 1},
+		{"prefix is not a country match", "USA", "food", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := taxRuleMatch(rules, tt.destination, tt.category)
+			if rule == nil {
+				t.Fatalf("taxRuleMatch(%s, %s) = nil, want rule %d", tt.destination, tt.category, tt.want)
+			}
+			if rule.ID != tt.want {
+				t.Fatalf("taxRuleMatch(%s, %s) = rule %d, want rule %d", tt.destination, tt.category, rule.ID, tt.want)
+			}
+		})
+	}
+
+	if rule := taxRuleMatch([]*model.TaxRule{{ID: 1, Region: "DE", TaxCategory: "books"}}, "FR", "books"); rule != nil {
+		t.Fatalf("taxRuleMatch for an unmatched region = rule %d, want nil", rule.ID)
+	}
+}
+
+func TestTaxApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		rules    []*model.TaxRule
+		code     string
+		lines    []TaxableLine
+		wantNet  []float64
+		wantTax  []float64
+		subtotal float64
+		tax      float64
+		total    float64
+	}{
+		{
+			name:     "no rule",
+			code:     "USD",
+			lines:    []TaxableLine{{Key: "1", TaxCategory: "books", UnitPrice: 10, Quantity: 2}},
+			wantNet:  []float64{20},
+			wantTax:  []float64{0},
+			subtotal: 20, tax: 0, total: 20,
+		},
+		{
+			name:     "tax added on top",
+			rules:    []*model.TaxRule{{ID: 1, Region: "US-CA", TaxCategory: model.TAX_RULE_ANY, Rate: 7.25}},
+			code:     "USD",
+			lines:    []TaxableLine{{Key: "1", UnitPrice: 19.99, Quantity: 3}},
+			wantNet:  []float64{59.97},
+			wantTax:  []float64{4.35},
+			subtotal: 59.97, tax: 4.35, total: 64.32,
+		},
+		{
+			// 10.00 incl. 19% is 8.403..., so net rounds to 8.40 and the tax is
+			// what is left, keeping net + tax equal to the price paid
+			name:     "tax included in the price",
+			rules:    []*model.TaxRule{{ID: 1, Region: "US", TaxCategory: model.TAX_RULE_ANY, Rate: 19, PriceIncludesTax: true}},
+			code:     "USD",
+			lines:    []TaxableLine{{Key: "1", UnitPrice: 10, Quantity: 1}, {Key: "2", UnitPrice: 0.99, Quantity: 7}},
+			wantNet:  []float64{8.40, 5.82},
+			wantTax:  []float64{1.60, 1.11},
+			subtotal: 14.22, tax: 2.71, total: 16.93,
+		},
+		{
+			name:     "exempt category",
+			rules:    []*model.TaxRule{{ID: 1, Region: "US", TaxCategory: model.TAX_RULE_ANY, Rate: 10}, {ID: 2, Region: "US", TaxCategory: "food", Exempt: true}},
+			code:     "USD",
+			lines:    []TaxableLine{{Key: "1", TaxCategory: "food", UnitPrice: 5, Quantity: 2}, {Key: "2", TaxCategory: "toys", UnitPrice: 5, Quantity: 2}},
+			wantNet:  []float64{10, 10},
+			wantTax:  []float64{0, 1},
+			subtotal: 20, tax: 1, total: 21,
+		},
+		{
+			name:     "zero decimal currency",
+			rules:    []*model.TaxRule{{ID: 1, Region: "US", TaxCategory: model.TAX_RULE_ANY, Rate: 10, PriceIncludesTax: true}},
+			code:     "JPY",
+			lines:    []TaxableLine{{Key: "1", UnitPrice: 1000, Quantity: 1}},
+			wantNet:  []float64{909},
+			wantTax:  []float64{91},
+			subtotal: 909, tax: 91, total: 1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breakdown := taxApply(tt.rules, "US-CA", tt.code, tt.lines)
+
+			if len(breakdown.Lines) != len(tt.lines) {
+				t.Fatalf("got %d lines, want %d", len(breakdown.Lines), len(tt.lines))
+			}
+			for i, line := range breakdown.Lines {
+				if line.Net != tt.wantNet[i] || line.Tax != tt.wantTax[i] {
+					t.Errorf("line %d: net %v tax %v, want net %v tax %v", i, line.Net, line.Tax, tt.wantNet[i], tt.wantTax[i])
+				}
+			}
+
+			if breakdown.Subtotal != tt.subtotal || breakdown.Tax != tt.tax || breakdown.Total != tt.total {
+				t.Errorf("totals = %v + %v = %v, want %v + %v = %v",
+					breakdown.Subtotal, breakdown.Tax, breakdown.Total, tt.subtotal, tt.tax, tt.total)
+			}
+		})
+	}
+}
+
+func TestNormalizeTaxRegion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"*", "*", false},
+		{" de ", "DE", false},
+		{"us-ca", "US-CA", false},
+		{"", "", true},
+		{"USA", "", true},
+		{"US-", "", true},
+		{"US-CALI", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := normalizeTaxRegion(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("normalizeTaxRegion(%q) = %q, %v, want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
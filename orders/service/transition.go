@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"orders/model"
+	"orders/tools"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRole string
+
+const (
+	ORDER_ROLE_BUYER  OrderRole = "buyer"
+	ORDER_ROLE_SELLER OrderRole = "seller"
+	ORDER_ROLE_SYSTEM OrderRole = "system"
+	ORDER_ROLE_ADMIN  OrderRole = "admin"
+)
+
+var ErrOrderTransitionForbidden = fmt.Errorf("you are not allowed to make this change to the order")
+
+// orderGuard rejects a transition the order is not ready for.
+type orderGuard func(order *model.Order) error
+
+type orderTransition struct {
+	From        OrderStatus
+	To          OrderStatus
+	Roles       []OrderRole
+	Description string
+	Guards      []orderGuard
+}
+
+// orderTransitions are the only status changes an order can go through.
+// cancelled and completed are final.
+var orderTransitions = []orderTransition{
+	{
+		From:        ORDER_STATUS_PENDING,
+		To:          ORDER_STATUS_PAID,
+		Roles:       []OrderRole{ORDER_ROLE_SYSTEM, ORDER_ROLE_ADMIN},
+		Description: "Payment received",
+	},
+	{
+		From:        ORDER_STATUS_PENDING,
+		To:          ORDER_STATUS_SHIPPED,
+		Roles:       []OrderRole{ORDER_ROLE_SELLER, ORDER_ROLE_ADMIN},
+		Description: "Order shipped, to be paid on delivery",
+		Guards:      []orderGuard{orderGuardPayOnDelivery, orderGuardShippingAddress},
+	},
+	{
+		From:        ORDER_STATUS_PAID,
+		To:          ORDER_STATUS_SHIPPED,
+		Roles:       []OrderRole{ORDER_ROLE_SELLER, ORDER_ROLE_ADMIN},
+		Description: "Order shipped",
+		Guards:      []orderGuard{orderGuardShippingAddress},
+	},
+	{
+		From:        ORDER_STATUS_PENDING,
+		To:          ORDER_STATUS_CANCELLED,
+		Roles:       []OrderRole{ORDER_ROLE_BUYER, ORDER_ROLE_SELLER, ORDER_ROLE_SYSTEM, ORDER_ROLE_ADMIN},
+		Description: "Order cancelled",
+	},
+	{
+		From:        ORDER_STATUS_PAID,
+		To:          ORDER_STATUS_CANCELLED,
+		Roles:       []OrderRole{ORDER_ROLE_BUYER, ORDER_ROLE_SELLER, ORDER_ROLE_SYSTEM, ORDER_ROLE_ADMIN},
+		Description: "Order cancelled",
+	},
+	{
+		From:        ORDER_STATUS_SHIPPED,
+		To:          ORDER_STATUS_COMPLETED,
+		Roles:       []OrderRole{ORDER_ROLE_BUYER, ORDER_ROLE_SYSTEM, ORDER_ROLE_ADMIN},
+		Description: "Order delivered",
+	},
+}
+
+// OrderTransition moves an order to a new status on behalf of user, or of
+// the system when user is nil, and records it in the order's tracking. note
+// is added to the tracking description. Users cancel through OrderCancel,
+// which also puts the stock back and refunds the order.
+func (s *Service) OrderTransition(ctx context.Context, orderID int, to OrderStatus, user *middleware.User, note string) (*model.Order, error) {
+	if to == ORDER_STATUS_CANCELLED && user != nil {
+		return nil, fmt.Errorf("orders cannot be cancelled by updating their status; cancel through /orders/:id/cancel, or /seller/orders/:id/cancel as a seller")
+	}
+
+	order, err := s.orderLock(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.OrderRoles(ctx, order, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.orderTransitionApply(order, to, roles, note); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// orderTransitionApply moves a loaded, locked order to a new status under
+// one of roles, and returns the role it was done as.
+func (s *Service) orderTransitionApply(order *model.Order, to OrderStatus, roles []OrderRole, note string) (OrderRole, error) {
+	transition := orderTransitionFind(OrderStatus(order.Status), to)
+	if transition == nil {
+		return "", fmt.Errorf("order cannot go from %s to %s", order.Status, to)
+	}
+
+	role, ok := orderTransitionRole(transition, roles)
+	if !ok {
+		return "", ErrOrderTransitionForbidden
+	}
+
+	for _, guard := range transition.Guards {
+		if err := guard(order); err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.DB.Model(order).Update("status", string(to)).Error; err != nil {
+		return "", err
+	}
+
+	description := transition.Description
+	if note != "" {
+		description += ": " + note
+	}
+	if len(description) > 100 {
+		description = description[:100]
+	}
+
+	if _, err := s.OrderAddTrackingInfo(order.ID, &model.OrderTracking{
+		OrderID:     order.ID,
+		Status:      string(to),
+		Description: description,
+		Actor:       string(role),
+	}); err != nil {
+		return "", err
+	}
+
+	order.Status = string(to)
+
+	return role, nil
+}
+
+// OrderRoles is every role user holds on the order: buyer if they placed it,
+// seller if they sell one of its items, and admin. A nil user is the system.
+func (s *Service) OrderRoles(ctx context.Context, order *model.Order, user *middleware.User) ([]OrderRole, error) {
+	if user == nil {
+		return []OrderRole{ORDER_ROLE_SYSTEM}, nil
+	}
+
+	var roles []OrderRole
+
+	if user.Role == string(ORDER_ROLE_ADMIN) {
+		roles = append(roles, ORDER_ROLE_ADMIN)
+	}
+
+	if order.UserID == user.ID {
+		roles = append(roles, ORDER_ROLE_BUYER)
+	}
+
+	if user.Seller {
+		var count int64
+		if err := s.DB.Model(&model.OrderItem{}).Scopes(tools.IsDeletedAtNull).
+			Where("order_id = ? AND seller_id = ?", order.ID, user.ID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			roles = append(roles, ORDER_ROLE_SELLER)
+		}
+	}
+
+	return roles, nil
+}
+
+// orderLock loads an order and locks its row until the transaction ends, so
+// two changes to the same order cannot interleave.
+func (s *Service) orderLock(orderID int) (*model.Order, error) {
+	var order model.Order
+
+	if err := s.DB.Model(&order).Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tools.IsDeletedAtNull).
+		Where("id = ?", orderID).First(&order).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("order does not exist")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func orderTransitionFind(from OrderStatus, to OrderStatus) *orderTransition {
+	for i := range orderTransitions {
+		if orderTransitions[i].From == from && orderTransitions[i].To == to {
+			return &orderTransitions[i]
+		}
+	}
+
+	return nil
+}
+
+// orderTransitionRole picks the role the transition is made under, in the
+// order the transition lists them.
+func orderTransitionRole(transition *orderTransition, roles []OrderRole) (OrderRole, bool) {
+	for _, allowed := range transition.Roles {
+		for _, role := range roles {
+			if role == allowed {
+				return role, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func orderGuardPayOnDelivery(order *model.Order) error {
+	if order.PaymentMethod != string(PAYMENT_METHOD_COD) {
+		return fmt.Errorf("card orders cannot be shipped before they are paid")
+	}
+
+	return nil
+}
+
+func orderGuardShippingAddress(order *model.Order) error {
+	if order.ShippingAddress == "" {
+		return fmt.Errorf("order has no shipping address")
+	}
+
+	return nil
+}
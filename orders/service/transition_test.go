@@ -0,0 +1,101 @@
+package service
+
+import (
+	"orders/model"
+	"testing"
+)
+
+func TestOrderTransitionFind(t *testing.T) {
+	tests := []struct {
+		name string
+		from OrderStatus
+		to   OrderStatus
+		want bool
+	}{
+		{"pending to paid", ORDER_STATUS_PENDING, ORDER_STATUS_PAID, true},
+		{"pending to shipped", ORDER_STATUS_PENDING, ORDER_STATUS_SHIPPED, true},
+		{"paid to shipped", ORDER_STATUS_PAID, ORDER_STATUS_SHIPPED, true},
+		{"pending to cancelled", ORDER_STATUS_PENDING, ORDER_STATUS_CANCELLED, true},
+		{"paid to cancelled", ORDER_STATUS_PAID, ORDER_STATUS_CANCELLED, true},
+		{"shipped to completed", ORDER_STATUS_SHIPPED, ORDER_STATUS_COMPLETED, true},
+		{"pending to completed", ORDER_STATUS_PENDING, ORDER_STATUS_COMPLETED, false},
+		{"shipped to cancelled", ORDER_STATUS_SHIPPED, ORDER_STATUS_CANCELLED, false},
+		{"paid to pending", ORDER_STATUS_PAID, ORDER_STATUS_PENDING, false},
+		{"cancelled is final", ORDER_STATUS_CANCELLED, ORDER_STATUS_PENDING, false},
+		{"completed is final", ORDER_STATUS_COMPLETED, ORDER_STATUS_SHIPPED, false},
+		{"same status", ORDER_STATUS_PAID, ORDER_STATUS_PAID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition := orderTransitionFind(tt.from, tt.to)
+			if got := transition != nil; got != tt.want {
+				t.Fatalf("orderTransitionFind(%s, %s) found = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+			if transition != nil && (transition.From != tt.from || transition.To != tt.to) {
+				t.Fatalf("orderTransitionFind(%s, %s) = %s to %s", tt.from, tt.to, transition.From, transition.To)
+			}
+		})
+	}
+}
+
+func TestOrderTransitionRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     OrderStatus
+		to       OrderStatus
+		roles    []OrderRole
+		wantRole OrderRole
+		wantOK   bool
+	}{
+		{"system confirms payment", ORDER_STATUS_PENDING, ORDER_STATUS_PAID, []OrderRole{ORDER_ROLE_SYSTEM}, ORDER_ROLE_SYSTEM, true},
+		{"admin confirms payment", ORDER_STATUS_PENDING, ORDER_STATUS_PAID, []OrderRole{ORDER_ROLE_ADMIN}, ORDER_ROLE_ADMIN, true},
+		{"buyer cannot mark paid", ORDER_STATUS_PENDING, ORDER_STATUS_PAID, []OrderRole{ORDER_ROLE_BUYER}, "", false},
+		{"seller cannot mark paid", ORDER_STATUS_PENDING, ORDER_STATUS_PAID, []OrderRole{ORDER_ROLE_SELLER}, "", false},
+		{"seller ships", ORDER_STATUS_PAID, ORDER_STATUS_SHIPPED, []OrderRole{ORDER_ROLE_SELLER}, ORDER_ROLE_SELLER, true},
+		{"buyer cannot ship", ORDER_STATUS_PAID, ORDER_STATUS_SHIPPED, []OrderRole{ORDER_ROLE_BUYER}, "", false},
+		{"buyer completes", ORDER_STATUS_SHIPPED, ORDER_STATUS_COMPLETED, []OrderRole{ORDER_ROLE_BUYER}, ORDER_ROLE_BUYER, true},
+		{"seller cannot complete", ORDER_STATUS_SHIPPED, ORDER_STATUS_COMPLETED, []OrderRole{ORDER_ROLE_SELLER}, "", false},
+		{"system cancels paid order", ORDER_STATUS_PAID, ORDER_STATUS_CANCELLED, []OrderRole{ORDER_ROLE_SYSTEM}, ORDER_ROLE_SYSTEM, true},
+		{"no roles", ORDER_STATUS_PENDING, ORDER_STATUS_CANCELLED, nil, "", false},
+		// the transition's own order of roles decides, not the user's
+		{"buyer and seller cancel as buyer", ORDER_STATUS_PAID, ORDER_STATUS_CANCELLED, []OrderRole{ORDER_ROLE_SELLER, ORDER_ROLE_BUYER}, ORDER_ROLE_BUYER, true},
+		{"admin and seller ship as seller", ORDER_STATUS_PAID, ORDER_STATUS_SHIPPED, []OrderRole{ORDER_ROLE_ADMIN, ORDER_ROLE_SELLER}, ORDER_ROLE_SELLER, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transition := orderTransitionFind(tt.from, tt.to)
+			if transition == nil {
+				t.Fatalf("no transition from %s to %s", tt.from, tt.to)
+			}
+
+			role, ok := orderTransitionRole(transition, tt.roles)
+			if ok != tt.wantOK || role != tt.wantRole {
+				t.Fatalf("orderTransitionRole(%v) = %q, %v, want %q, %v", tt.roles, role, ok, tt.wantRole, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestOrderGuards(t *testing.T) {
+	tests := []struct {
+		name    string
+		guard   orderGuard
+		order   model.Order
+		wantErr bool
+	}{
+		{"cash on delivery ships unpaid", orderGuardPayOnDelivery, model.Order{PaymentMethod: string(PAYMENT_METHOD_COD)}, false},
+		{"card does not ship unpaid", orderGuardPayOnDelivery, model.Order{PaymentMethod: string(PAYMENT_METHOD_CARD)}, true},
+		{"has shipping address", orderGuardShippingAddress, model.Order{ShippingAddress: "1 Main St"}, false},
+		{"no shipping address", orderGuardShippingAddress, model.Order{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.guard(&tt.order); (err != nil) != tt.wantErr {
+				t.Fatalf("guard error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ type UserDetails struct {
 	Email   string
 	Phone   string
 	Address string
+	Region  string
 }
 
 func (s *Service) GetUserDetails(ctx context.Context, id int) (*UserDetails, error) {
@@ -29,6 +30,7 @@ func (s *Service) GetUserDetails(ctx context.Context, id int) (*UserDetails, err
 		Email:   userDetails.Email,
 		Phone:   userDetails.Phone,
 		Address: userDetails.Address,
+		Region:  userDetails.Region,
 	}
 
 	return &details, nil
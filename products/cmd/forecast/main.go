@@ -0,0 +1,53 @@
+// Command forecast recomputes demand forecasts. With -product it refits a
+// single product and prints its daily forecast; otherwise it refits every
+// product on sale.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"products/config"
+	"products/service"
+	"time"
+)
+
+func main() {
+	productID := flag.Int("product", 0, "forecast a single product and print it")
+	flag.Parse()
+
+	config.ConnectDB()
+
+	ctx := context.Background()
+
+	if *productID == 0 {
+		fmt.Printf("saved %d forecasts\n", service.RecomputeDemandForecasts(ctx))
+		return
+	}
+
+	s := service.GetTransaction()
+	forecast, err := s.DemandForecastCompute(ctx, *productID)
+	if err != nil {
+		s.DB.Rollback()
+		log.Fatalf("forecast failed: %v", err)
+	}
+	if err := s.Commit(); err != nil {
+		log.Fatalf("forecast failed: %v", err)
+	}
+
+	stockOut := "-"
+	if forecast.StockOutDate != nil {
+		stockOut = forecast.StockOutDate.Format(time.DateOnly)
+	}
+
+	fmt.Printf("method %s (alpha %.1f, beta %.1f, gamma %.1f), rmse %.2f over %d days\n",
+		forecast.Method, forecast.Alpha, forecast.Beta, forecast.Gamma, forecast.RMSE, forecast.HistoryDays)
+	fmt.Printf("stock %d, reorder point %d, reorder quantity %d, stock out %s\n\n",
+		forecast.Stock, forecast.ReorderPoint, forecast.ReorderQuantity, stockOut)
+
+	fmt.Printf("%-12s %-10s %-10s %-10s\n", "DATE", "DEMAND", "LOWER", "UPPER")
+	for _, point := range forecast.Points {
+		fmt.Printf("%-12s %-10.2f %-10.2f %-10.2f\n", point.Date.Format(time.DateOnly), point.Demand, point.Lower, point.Upper)
+	}
+}
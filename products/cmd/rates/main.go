@@ -0,0 +1,50 @@
+// Command rates loads exchange rates from a currency,rate csv file, for rate
+// feeds that are dropped on disk instead of entered by an admin.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"products/config"
+	"products/service"
+)
+
+func main() {
+	path := flag.String("file", "", "csv file with a currency,rate header")
+	flag.Parse()
+
+	if *path == "" {
+		log.Fatal("-file is required")
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("failed to open rates: %v", err)
+	}
+	defer file.Close()
+
+	rates, err := service.ExchangeRateParseCSV(file)
+	if err != nil {
+		log.Fatalf("failed to read rates: %v", err)
+	}
+
+	config.ConnectDB()
+
+	s := service.GetTransaction()
+	table, err := s.ExchangeRateImport(context.Background(), rates)
+	if err != nil {
+		s.DB.Rollback()
+		log.Fatalf("failed to import rates: %v", err)
+	}
+	if err := s.Commit(); err != nil {
+		log.Fatalf("failed to import rates: %v", err)
+	}
+
+	fmt.Printf("rates against %s\n", table.Reference)
+	for _, rate := range table.Rates {
+		fmt.Printf("%-4s %-16.8f %s\n", rate.Currency, rate.Rate, rate.Source)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"products/model"
 	"time"
+	"utils/currency"
+	"utils/middleware"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -59,4 +61,79 @@ func GetDB() *gorm.DB {
 
 func SyncDB() {
 	db.AutoMigrate(&model.Product{})
+	db.AutoMigrate(&model.Category{})
+	db.AutoMigrate(&model.ProductCategory{})
+	db.AutoMigrate(&model.ProductImage{})
+	db.AutoMigrate(&model.ProductOption{})
+	db.AutoMigrate(&model.ProductOptionValue{})
+	db.AutoMigrate(&model.ProductVariant{})
+	db.AutoMigrate(&model.ProductVariantValue{})
+	db.AutoMigrate(&model.StockReservation{})
+	db.AutoMigrate(&model.StockReservationItem{})
+	db.AutoMigrate(&model.InventoryMovement{})
+	db.AutoMigrate(&model.AppliedStockRestore{})
+	db.AutoMigrate(&model.ImportJob{})
+	db.AutoMigrate(&model.ImportJobError{})
+	db.AutoMigrate(&model.StockAlert{})
+	// reviews deleted before live existed must not hold the buyer's slot
+	// when the unique key is built over it
+	if db.Migrator().HasTable(&model.Review{}) && !db.Migrator().HasColumn(&model.Review{}, "Live") {
+		db.Migrator().AddColumn(&model.Review{}, "Live")
+		db.Model(&model.Review{}).Where("deleted_at IS NOT NULL").Update("live", nil)
+	}
+	db.AutoMigrate(&model.Review{})
+	db.AutoMigrate(&model.PriceSchedule{})
+	db.AutoMigrate(&model.PriceHistory{})
+	db.AutoMigrate(&model.Warehouse{})
+	db.AutoMigrate(&model.LocationStock{})
+	db.AutoMigrate(&model.StockAllocation{})
+	db.AutoMigrate(&model.ProductStatusHistory{})
+	db.AutoMigrate(&model.DemandForecast{})
+	db.AutoMigrate(&model.DemandForecastPoint{})
+	db.AutoMigrate(&model.CategoryAttribute{})
+	db.AutoMigrate(&model.CategoryAttributeOption{})
+	db.AutoMigrate(&model.ProductAttribute{})
+	db.AutoMigrate(&model.ProductAffinity{})
+	db.AutoMigrate(&model.BundleComponent{})
+	db.AutoMigrate(&model.ExchangeRate{})
+	db.AutoMigrate(&middleware.IdempotencyKey{})
+
+	// variants deleted before live existed must not hold on to their options
+	// and sku, which the old unique keys covered even after deletion
+	db.Model(&model.ProductVariant{}).Where("deleted_at IS NOT NULL AND live IS NOT NULL").Update("live", nil)
+	for _, index := range []string{"idx_variant_options", "uni_product_variant_sku", "sku"} {
+		if db.Migrator().HasIndex(&model.ProductVariant{}, index) {
+			db.Migrator().DropIndex(&model.ProductVariant{}, index)
+		}
+	}
+
+	if db.Migrator().HasIndex(&model.Review{}, "idx_review_product_user") {
+		db.Migrator().DropIndex(&model.Review{}, "idx_review_product_user")
+	}
+
+	// restores applied before they were recorded on their own are only known
+	// by the reference on their movements
+	db.Exec(`INSERT IGNORE INTO applied_stock_restore (reference, order_id, reason, created_at)
+		SELECT reference, MIN(order_id), MIN(reason), MIN(created_at) FROM inventory_movement
+		WHERE reference <> '' GROUP BY reference`)
+
+	// stock held before the ledger existed has no movement to account for
+	// it, so each such product and variant opens with its current stock
+	db.Exec(`INSERT INTO inventory_movement (product_id, variant_id, delta, stock_after, reason, note, reference, created_at)
+		SELECT product.id, 0, product.stock, product.stock, ?, 'opening balance', '', NOW() FROM product
+		WHERE product.deleted_at IS NULL AND product.type <> ? AND product.stock <> 0
+		AND NOT EXISTS (SELECT 1 FROM product_variant WHERE product_variant.product_id = product.id AND product_variant.deleted_at IS NULL)
+		AND NOT EXISTS (SELECT 1 FROM inventory_movement WHERE inventory_movement.product_id = product.id AND inventory_movement.variant_id = 0)`,
+		model.MOVEMENT_REASON_OPENING_BALANCE, model.PRODUCT_TYPE_BUNDLE)
+	db.Exec(`INSERT INTO inventory_movement (product_id, variant_id, delta, stock_after, reason, note, reference, created_at)
+		SELECT product_variant.product_id, product_variant.id, product_variant.stock, product_variant.stock, ?, 'opening balance', '', NOW() FROM product_variant
+		WHERE product_variant.deleted_at IS NULL AND product_variant.stock <> 0
+		AND NOT EXISTS (SELECT 1 FROM inventory_movement WHERE inventory_movement.variant_id = product_variant.id)`,
+		model.MOVEMENT_REASON_OPENING_BALANCE)
+
+	// products listed before currencies existed are priced in the default one
+	db.Model(&model.Product{}).Where("currency = ''").Updates(map[string]interface{}{
+		"currency":        currency.Default(),
+		"reference_price": gorm.Expr("price"),
+	})
 }
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func UpdateReorderThreshold(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	var input model.UpdateReorderThreshold
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	valid, err := s.ProductCheckBelongToSeller(c.Request.Context(), productID, user.ID)
+	if err != nil || !valid {
+		c.AbortWithStatusJSON(http.StatusForbidden, &model.GlobalResponse{
+			Success: false,
+			Message: "product does not belong to seller",
+		})
+		return
+	}
+
+	if _, err := s.ProductSetReorderThreshold(c.Request.Context(), productID, input.ReorderThreshold); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Reorder threshold successfully updated",
+	})
+}
+
+func StockAlertList(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	alerts, err := s.StockAlertGetBySeller(c.Request.Context(), user.ID, model.StockAlertStatus(c.Query("status")))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.StockAlertListResponse{
+		Success: true,
+		Message: "Stock alerts retrieved successfully",
+		Data:    alerts,
+	})
+}
+
+func AcknowledgeStockAlert(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	alertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid alert ID",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	alert, err := s.StockAlertAcknowledge(c.Request.Context(), alertID, user.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.StockAlertResponse{
+		Success: true,
+		Message: "Stock alert acknowledged",
+		Data:    alert,
+	})
+}
+
+func SnoozeStockAlert(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	alertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid alert ID",
+		})
+		return
+	}
+
+	var input model.SnoozeStockAlert
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	alert, err := s.StockAlertSnooze(c.Request.Context(), alertID, user.ID, input.Minutes)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.StockAlertResponse{
+		Success: true,
+		Message: "Stock alert snoozed",
+		Data:    alert,
+	})
+}
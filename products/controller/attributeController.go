@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CategoryAttributeList(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	attributes, err := s.CategoryAttributeGetByCategoryID(c.Request.Context(), categoryID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.CategoryAttributeListResponse{
+		Success: true,
+		Message: "Category attributes retrieved successfully",
+		Data:    attributes,
+	})
+}
+
+func CreateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	var input model.NewCategoryAttribute
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	attribute, err := s.CategoryAttributeCreate(c.Request.Context(), categoryID, input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.CategoryAttributeResponse{
+		Success: true,
+		Message: "Category attribute successfully created",
+		Data:    attribute,
+	})
+}
+
+func UpdateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	attributeID, err := strconv.Atoi(c.Param("attribute_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid attribute ID",
+		})
+		return
+	}
+
+	var input model.UpdateCategoryAttribute
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	attribute, err := s.CategoryAttributeUpdate(c.Request.Context(), categoryID, attributeID, input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.CategoryAttributeResponse{
+		Success: true,
+		Message: "Category attribute successfully updated",
+		Data:    attribute,
+	})
+}
+
+func DeleteCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	attributeID, err := strconv.Atoi(c.Param("attribute_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid attribute ID",
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	if _, err := s.CategoryAttributeDelete(c.Request.Context(), categoryID, attributeID); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Category attribute successfully deleted",
+	})
+}
+
+func SetProductAttributes(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	var input model.SetProductAttributes
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	valid, err := s.ProductCheckBelongToSeller(c.Request.Context(), productID, user.ID)
+	if err != nil || !valid {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusForbidden, &model.GlobalResponse{
+			Success: false,
+			Message: "product does not belong to seller",
+		})
+		return
+	}
+
+	attributes, err := s.ProductSetAttributes(c.Request.Context(), productID, input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.ProductAttributeListResponse{
+		Success: true,
+		Message: "Product attributes successfully updated",
+		Data:    attributes,
+	})
+}
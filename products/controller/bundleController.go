@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetBundleComponents(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	var input model.SetBundleComponents
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	valid, err := s.ProductCheckBelongToSeller(c.Request.Context(), productID, user.ID)
+	if err != nil || !valid {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusForbidden, &model.GlobalResponse{
+			Success: false,
+			Message: "product does not belong to seller",
+		})
+		return
+	}
+
+	components, err := s.BundleSetComponents(c.Request.Context(), productID, input.Components)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.BundleComponentListResponse{
+		Success: true,
+		Message: "Bundle components successfully updated",
+		Data:    components,
+	})
+}
@@ -0,0 +1,141 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"time"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ImportCatalog(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "csv file is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	rows, err := service.CatalogParseCSV(file)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	job, err := s.ImportJobCreate(c.Request.Context(), user.ID, fileHeader.Filename, len(rows))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// the job outlives the request, but keeps the seller on the context so
+	// stock changes are attributed to them in the ledger
+	go service.RunCatalogImport(context.WithoutCancel(c.Request.Context()), job.ID, user.ID, rows)
+
+	c.JSON(http.StatusAccepted, &model.ImportJobResponse{
+		Success: true,
+		Message: "Catalog import started",
+		Data:    job,
+	})
+}
+
+func CatalogImportStatus(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	jobID, err := strconv.Atoi(c.Param("job_id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid job ID",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	job, err := s.ImportJobGetByID(c.Request.Context(), jobID, user.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ImportJobResponse{
+		Success: true,
+		Message: "Import job retrieved successfully",
+		Data:    job,
+	})
+}
+
+func ExportCatalog(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			s.ErrorCheck(r)
+			c.Abort()
+			return
+		}
+	}()
+
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=catalog-%s.csv", time.Now().Format("20060102")))
+	c.Status(http.StatusOK)
+
+	// headers are already sent once rows start streaming, so a failure part
+	// way through can only be logged and the response cut short
+	if err := s.CatalogExport(c.Request.Context(), user.ID, c.Writer); err != nil {
+		s.ErrorCheck(err)
+		c.Abort()
+	}
+}
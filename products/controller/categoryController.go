@@ -0,0 +1,235 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CategoryList(c *gin.Context) {
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	categories, err := s.CategoryGetAll(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.CategoryListResponse{
+		Success: true,
+		Message: "Categories retrieved successfully",
+		Data:    categories,
+	})
+}
+
+func CreateCategory(c *gin.Context) {
+	var input model.NewCategory
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	category, err := s.CategoryCreate(c.Request.Context(), input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.CategoryResponse{
+		Success: true,
+		Message: "Category successfully created",
+		Data:    category,
+	})
+}
+
+func UpdateCategory(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	var input model.UpdateCategory
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input.ID = categoryID
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	category, err := s.CategoryUpdate(c.Request.Context(), input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.CategoryResponse{
+		Success: true,
+		Message: "Category successfully updated",
+		Data:    category,
+	})
+}
+
+func DeleteCategory(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid category ID",
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	if _, err := s.CategoryDelete(c.Request.Context(), categoryID); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Category successfully deleted",
+	})
+}
+
+func AssignProductCategories(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	var input model.AssignCategories
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	valid, err := s.ProductCheckBelongToSeller(c.Request.Context(), productID, user.ID)
+	if err != nil || !valid {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusForbidden, &model.GlobalResponse{
+			Success: false,
+			Message: "product does not belong to seller",
+		})
+		return
+	}
+
+	if _, err := s.ProductAssignCategories(c.Request.Context(), productID, input.CategoryIDs); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Product categories successfully updated",
+	})
+}
@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ExchangeRateList(c *gin.Context) {
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	table, err := s.ExchangeRateGetAll(c.Request.Context())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ExchangeRateTableResponse{
+		Success: true,
+		Message: "Exchange rates retrieved successfully",
+		Data:    table,
+	})
+}
+
+func SetExchangeRate(c *gin.Context) {
+	var input model.NewExchangeRate
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	rate, err := s.ExchangeRateSet(c.Request.Context(), c.Param("currency"), input.Rate, model.EXCHANGE_RATE_SOURCE_ADMIN)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.ExchangeRateResponse{
+		Success: true,
+		Message: "Exchange rate successfully saved",
+		Data:    rate,
+	})
+}
+
+func DeleteExchangeRate(c *gin.Context) {
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	if err := s.ExchangeRateDelete(c.Request.Context(), c.Param("currency")); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Exchange rate successfully deleted",
+	})
+}
+
+// ImportExchangeRates sets every rate listed in an uploaded currency,rate csv
+// file. Rates that are not in the file are left as they are.
+func ImportExchangeRates(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "csv file is required",
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	rates, err := service.ExchangeRateParseCSV(file)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	table, err := s.ExchangeRateImport(c.Request.Context(), rates)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.ExchangeRateTableResponse{
+		Success: true,
+		Message: "Exchange rates successfully imported",
+		Data:    table,
+	})
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+	"utils/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ProductDemandForecast(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	refresh, _ := strconv.ParseBool(c.Query("refresh"))
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	valid, err := s.ProductCheckBelongToSeller(c.Request.Context(), productID, user.ID)
+	if err != nil || !valid {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusForbidden, &model.GlobalResponse{
+			Success: false,
+			Message: "product does not belong to seller",
+		})
+		return
+	}
+
+	forecast, err := s.DemandForecastGetByProductID(c.Request.Context(), productID, refresh)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.DemandForecastResponse{
+		Success: true,
+		Message: "Demand forecast retrieved successfully",
+		Data:    forecast,
+	})
+}
@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"products/model"
 	"products/service"
+	"strconv"
 	"utils/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -60,7 +62,15 @@ func CreateProduct(c *gin.Context) {
 		}
 	}()
 
-	s.ProductCreate(c.Request.Context(), input)
+	if _, err := s.ProductCreate(c.Request.Context(), input); err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
 	s.Commit()
 
 	c.JSON(http.StatusOK, &model.GlobalResponse{
@@ -69,17 +79,90 @@ func CreateProduct(c *gin.Context) {
 	})
 }
 
-func ProductDetail(c *gin.Context) {
-	var input int
+func UpdateProduct(c *gin.Context) {
+	saveProduct(c, false)
+}
+
+func PatchProduct(c *gin.Context) {
+	saveProduct(c, true)
+}
+
+// saveProduct handles both PUT, which must send every editable field, and
+// PATCH, which only sends the fields that change.
+func saveProduct(c *gin.Context, partial bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	var input model.UpdateProduct
 
 	if err := c.ShouldBind(&input); err != nil {
-		c.JSON(http.StatusBadRequest, &model.GlobalResponse{
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input.ID = productID
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	var product *model.Product
+	if partial {
+		product, err = s.ProductUpdate(c.Request.Context(), input)
+	} else {
+		product, err = s.ProductReplace(c.Request.Context(), input)
+	}
+	if err != nil {
+		s.Rollback(err)
+
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrProductVersionConflict) {
+			status = http.StatusConflict
+		}
+
+		c.AbortWithStatusJSON(status, &model.GlobalResponse{
 			Success: false,
 			Message: err.Error(),
 		})
 		return
 	}
 
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.ProductResponse{
+		Success: true,
+		Message: "Product successfully updated",
+		Data:    product,
+	})
+}
+
+func DeleteProduct(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
 	s := service.GetService()
 	defer func() {
 		if r := recover(); r != nil {
@@ -92,11 +175,63 @@ func ProductDetail(c *gin.Context) {
 		}
 	}()
 
-	s.ProductGetByID(c.Request.Context(), input)
+	if _, err := s.ProductDelete(c.Request.Context(), productID); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Product successfully deleted",
+	})
+}
+
+func ProductDetail(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	product, err := s.ProductGetDetail(c.Request.Context(), productID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.ProductDetailApplyDisplayCurrency(c.Request.Context(), product, c.Query("currency")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.ProductDetailResponse{
 		Success: true,
 		Message: "Product detail retrieved successfully",
+		Data:    product,
 	})
 }
 
@@ -130,3 +265,77 @@ func ProductList(c *gin.Context) {
 		Message: "Seller's product details retrieved successfully",
 	})
 }
+
+func SearchProducts(c *gin.Context) {
+	var input model.ProductSearchFilter
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	input.Attributes = c.QueryMap("attr")
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	products, nextCursor, err := s.ProductSearch(c.Request.Context(), input)
+	if err != nil {
+		code := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidSearch) {
+			code = http.StatusBadRequest
+		}
+
+		c.AbortWithStatusJSON(code, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := s.ProductApplyDisplayCurrency(c.Request.Context(), products, input.Currency); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// facets describe the whole result set, so they are only sent with the
+	// first page
+	var facets []*model.AttributeFacet
+	if input.Cursor == "" {
+		if facets, err = s.ProductSearchFacets(c.Request.Context(), input); err != nil {
+			code := http.StatusInternalServerError
+			if errors.Is(err, service.ErrInvalidSearch) {
+				code = http.StatusBadRequest
+			}
+
+			c.AbortWithStatusJSON(code, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, &model.ProductListResponse{
+		Success:    true,
+		Message:    "Products retrieved successfully",
+		Data:       products,
+		NextCursor: nextCursor,
+		Facets:     facets,
+	})
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"net/http"
+	"products/model"
+	"products/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RelatedProductList(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: "invalid product ID",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	s := service.GetService()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.ErrorCheck(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	related, err := s.ProductGetRelated(c.Request.Context(), productID, limit)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	products := make([]*model.Product, 0, len(related))
+	for _, product := range related {
+		products = append(products, product.Product)
+	}
+	if err := s.ProductApplyDisplayCurrency(c.Request.Context(), products, c.Query("currency")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, &model.RelatedProductListResponse{
+		Success: true,
+		Message: "Related products retrieved successfully",
+		Data:    related,
+	})
+}
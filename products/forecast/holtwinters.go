@@ -0,0 +1,180 @@
+// Package forecast fits exponential smoothing models to daily demand and
+// projects it forward with prediction intervals.
+package forecast
+
+import (
+	"fmt"
+	"math"
+)
+
+type Method string
+
+const (
+	METHOD_HOLT_WINTERS Method = "holt_winters"
+	METHOD_HOLT         Method = "holt"
+	METHOD_SIMPLE       Method = "simple"
+)
+
+// Z95 is the normal quantile for a 95% prediction interval.
+const Z95 = 1.96
+
+// smoothingGrid is searched for the parameters with the lowest one-step-ahead
+// error. It is kept coarse so a full catalog can be refit on every run.
+var smoothingGrid = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9}
+
+// Model is a fitted additive Holt-Winters model. Series too short to estimate
+// a season fall back to Holt's linear trend, and very short ones to simple
+// exponential smoothing.
+type Model struct {
+	Method Method
+	Alpha  float64
+	Beta   float64
+	Gamma  float64
+	Period int
+	// RMSE of the one-step-ahead errors over the fitted history
+	RMSE float64
+
+	level  float64
+	trend  float64
+	season []float64
+	n      int
+}
+
+// Point is the forecast for one step, with the bounds of its interval.
+type Point struct {
+	Mean  float64
+	Lower float64
+	Upper float64
+}
+
+// Fit chooses a method from the length of the series and searches for its
+// smoothing parameters.
+func Fit(series []float64, period int) (*Model, error) {
+	if len(series) == 0 {
+		return nil, fmt.Errorf("no history to fit")
+	}
+
+	var (
+		method = METHOD_SIMPLE
+		betas  = []float64{0}
+		gammas = []float64{0}
+	)
+
+	switch {
+	case period > 1 && len(series) >= 2*period:
+		method, betas, gammas = METHOD_HOLT_WINTERS, smoothingGrid, smoothingGrid
+	case len(series) >= 3:
+		method, betas = METHOD_HOLT, smoothingGrid
+	}
+	if method != METHOD_HOLT_WINTERS {
+		period = 1
+	}
+
+	var (
+		best    *Model
+		bestSSE = math.Inf(1)
+	)
+
+	for _, alpha := range smoothingGrid {
+		for _, beta := range betas {
+			for _, gamma := range gammas {
+				model := &Model{Method: method, Alpha: alpha, Beta: beta, Gamma: gamma, Period: period}
+				if sse := model.smooth(series); sse < bestSSE {
+					best, bestSSE = model, sse
+				}
+			}
+		}
+	}
+
+	return best, nil
+}
+
+// smooth runs the model over the series, leaving its final state in place, and
+// returns the sum of squared one-step-ahead errors.
+func (m *Model) smooth(series []float64) float64 {
+	var (
+		start = 1
+		sse   float64
+	)
+
+	m.n = len(series)
+	m.season = make([]float64, m.Period)
+	m.level = series[0]
+	m.trend = 0
+
+	switch m.Method {
+	case METHOD_HOLT_WINTERS:
+		first := mean(series[:m.Period])
+		m.level = first
+		m.trend = (mean(series[m.Period:2*m.Period]) - first) / float64(m.Period)
+		for i := 0; i < m.Period; i++ {
+			m.season[i] = series[i] - first
+		}
+		start = m.Period
+	case METHOD_HOLT:
+		m.trend = series[1] - series[0]
+	}
+
+	for t := start; t < len(series); t++ {
+		var (
+			i         = t % m.Period
+			predicted = m.level + m.trend + m.season[i]
+			err       = series[t] - predicted
+			level     = m.Alpha*(series[t]-m.season[i]) + (1-m.Alpha)*(m.level+m.trend)
+		)
+
+		m.trend = m.Beta*(level-m.level) + (1-m.Beta)*m.trend
+		m.season[i] = m.Gamma*(series[t]-level) + (1-m.Gamma)*m.season[i]
+		m.level = level
+		sse += err * err
+	}
+
+	if count := len(series) - start; count > 0 {
+		m.RMSE = math.Sqrt(sse / float64(count))
+	}
+
+	return sse
+}
+
+// Forecast projects the next steps after the fitted history. Demand cannot be
+// negative, so means and bounds are clamped at zero. The interval widens with
+// the horizon using the usual approximation for additive smoothing models.
+func (m *Model) Forecast(steps int, z float64) []Point {
+	var (
+		points   = make([]Point, 0, steps)
+		variance float64
+	)
+
+	for h := 1; h <= steps; h++ {
+		if h > 1 {
+			j := float64(h - 1)
+			c := m.Alpha * (1 + j*m.Beta)
+			if m.Method == METHOD_HOLT_WINTERS && (h-1)%m.Period == 0 {
+				c += m.Gamma
+			}
+			variance += c * c
+		}
+
+		var (
+			estimate = m.level + float64(h)*m.trend + m.season[(m.n+h-1)%m.Period]
+			margin   = z * m.RMSE * math.Sqrt(1+variance)
+		)
+
+		points = append(points, Point{
+			Mean:  math.Max(estimate, 0),
+			Lower: math.Max(estimate-margin, 0),
+			Upper: math.Max(estimate+margin, 0),
+		})
+	}
+
+	return points
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
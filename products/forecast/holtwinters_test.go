@@ -0,0 +1,123 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFitMethod(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		period int
+		want   Method
+	}{
+		{"one day", 1, 7, METHOD_SIMPLE},
+		{"two days", 2, 7, METHOD_SIMPLE},
+		{"under two seasons", 13, 7, METHOD_HOLT},
+		{"two seasons", 14, 7, METHOD_HOLT_WINTERS},
+		{"no season", 30, 1, METHOD_HOLT},
+	}
+
+	for _, tt := range tests {
+		series := make([]float64, tt.length)
+		for i := range series {
+			series[i] = float64(i%5 + 1)
+		}
+
+		model, err := Fit(series, tt.period)
+		if err != nil {
+			t.Fatalf("%s: Fit() error = %v", tt.name, err)
+		}
+		if model.Method != tt.want {
+			t.Errorf("%s: method = %s, want %s", tt.name, model.Method, tt.want)
+		}
+		if model.Method != METHOD_HOLT_WINTERS && model.Period != 1 {
+			t.Errorf("%s: period = %d, want 1 without a season", tt.name, model.Period)
+		}
+	}
+}
+
+func TestFitEmpty(t *testing.T) {
+	if _, err := Fit(nil, 7); err == nil {
+		t.Error("Fit() with no history succeeded, want an error")
+	}
+}
+
+func TestForecastSeasonal(t *testing.T) {
+	week := []float64{10, 12, 14, 16, 18, 30, 40}
+
+	// 30 days end two days into a week, so the forecast has to pick up the
+	// season from the third day on
+	series := make([]float64, 30)
+	for i := range series {
+		series[i] = week[i%len(week)]
+	}
+
+	model, err := Fit(series, len(week))
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	if model.Method != METHOD_HOLT_WINTERS {
+		t.Fatalf("method = %s, want %s", model.Method, METHOD_HOLT_WINTERS)
+	}
+	if model.RMSE > 1e-9 {
+		t.Errorf("RMSE = %v, want 0 for an exactly repeating season", model.RMSE)
+	}
+
+	points := model.Forecast(14, Z95)
+	for h, point := range points {
+		want := week[(len(series)+h)%len(week)]
+		if math.Abs(point.Mean-want) > 1e-9 {
+			t.Errorf("step %d mean = %v, want %v", h+1, point.Mean, want)
+		}
+		if point.Lower > point.Mean || point.Upper < point.Mean {
+			t.Errorf("step %d interval [%v, %v] does not hold mean %v", h+1, point.Lower, point.Upper, point.Mean)
+		}
+	}
+}
+
+func TestForecastTrend(t *testing.T) {
+	series := []float64{5, 7, 9, 11, 13}
+
+	model, err := Fit(series, 7)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	if model.Method != METHOD_HOLT {
+		t.Fatalf("method = %s, want %s", model.Method, METHOD_HOLT)
+	}
+
+	for h, point := range model.Forecast(3, Z95) {
+		want := 13 + 2*float64(h+1)
+		if math.Abs(point.Mean-want) > 1e-9 {
+			t.Errorf("step %d mean = %v, want %v", h+1, point.Mean, want)
+		}
+	}
+}
+
+func TestForecastShortSeries(t *testing.T) {
+	model, err := Fit([]float64{4}, 7)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+
+	for h, point := range model.Forecast(5, Z95) {
+		if point.Mean != 4 {
+			t.Errorf("step %d mean = %v, want 4", h+1, point.Mean)
+		}
+	}
+}
+
+func TestForecastClampsAtZero(t *testing.T) {
+	model, err := Fit([]float64{10, 8, 6, 4, 2}, 7)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+
+	for h, point := range model.Forecast(5, Z95) {
+		if point.Mean < 0 || point.Lower < 0 || point.Upper < 0 {
+			t.Errorf("step %d = %+v, want no negative demand", h+1, point)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package resolver
 
 import (
 	"context"
+	"fmt"
+	"products/model"
 	"products/service"
+	"time"
 	"utils/product"
 )
 
@@ -11,26 +14,204 @@ type Server struct {
 }
 
 func (s Server) GetProductDetails(ctx context.Context, req *product.GetProductDetailsRequest) (*product.GetProductDetailsResponse, error) {
+	return productDetails(ctx, service.GetService(), req)
+}
+
+// GetProductsDetails looks up many products at once. A product that cannot be
+// returned is reported in its own result instead of failing the whole call.
+func (s Server) GetProductsDetails(ctx context.Context, req *product.GetProductsDetailsRequest) (*product.GetProductsDetailsResponse, error) {
+	svc := service.GetService()
+
+	resp := &product.GetProductsDetailsResponse{
+		Products: make(map[string]*product.ProductDetailsResult, len(req.Items)),
+	}
+
+	for _, item := range req.Items {
+		key := product.LookupKey(item.Id, item.VariantId)
+		if _, ok := resp.Products[key]; ok {
+			continue
+		}
+
+		detail, err := productDetails(ctx, svc, &product.GetProductDetailsRequest{
+			Id:             item.Id,
+			VariantId:      item.VariantId,
+			IncludeDeleted: req.IncludeDeleted,
+			Currency:       req.Currency,
+		})
+		if err != nil {
+			resp.Products[key] = &product.ProductDetailsResult{
+				Found: false,
+				Error: err.Error(),
+			}
+			continue
+		}
+
+		resp.Products[key] = &product.ProductDetailsResult{
+			Found:   true,
+			Product: detail,
+		}
+	}
+
+	return resp, nil
+}
+
+func productDetails(ctx context.Context, svc *service.Service, req *product.GetProductDetailsRequest) (*product.GetProductDetailsResponse, error) {
 	productID := req.Id
 
-	productDetail, err := service.GetService().ProductGetByID(ctx, int(productID))
+	var (
+		productDetail *model.Product
+		err           error
+	)
+
+	if req.IncludeDeleted {
+		productDetail, err = svc.ProductGetByIDWithDeleted(ctx, int(productID))
+	} else {
+		productDetail, err = svc.ProductGetByID(ctx, int(productID))
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &product.GetProductDetailsResponse{
-		Id:          int64(productDetail.ID),
-		SellerId:    int64(productDetail.SellerID),
-		Name:        productDetail.Name,
-		Description: productDetail.Description,
-		Price:       productDetail.Price,
-		Stock:       int64(productDetail.Stock),
-	}, nil
+	categoryPath, err := svc.ProductGetCategoryPath(ctx, productDetail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := svc.ProductImageGetByProductID(ctx, productDetail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		sku          string
+		imageURLs    = make([]string, 0, len(images))
+		primaryImage string
+	)
+
+	for _, image := range images {
+		imageURLs = append(imageURLs, image.URL)
+		if image.IsPrimary {
+			primaryImage = image.URL
+		}
+	}
+
+	if productDetail.SKU != nil {
+		sku = *productDetail.SKU
+	}
+
+	hasVariants, err := svc.ProductHasVariants(ctx, productDetail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &product.GetProductDetailsResponse{
+		Id:           int64(productDetail.ID),
+		SellerId:     int64(productDetail.SellerID),
+		Name:         productDetail.Name,
+		Description:  productDetail.Description,
+		Price:        productDetail.Price,
+		Stock:        int64(productDetail.Stock),
+		Sku:          sku,
+		ShopName:     productDetail.ShopName,
+		CategoryPath: categoryPath,
+		ImageUrls:    imageURLs,
+		PrimaryImage: primaryImage,
+		HasVariants:  hasVariants,
+		Deleted:      productDetail.DeletedAt != nil,
+		Status:       productDetail.Status,
+		Type:         productDetail.Type,
+		TaxCategory:  productDetail.TaxCategory,
+	}
+
+	pricing, err := svc.ProductResolvePrice(ctx, productDetail.ID, int(req.VariantId), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	resp.BaseCurrency = pricing.Currency
+	resp.ExchangeRate = 1
+	if req.Currency != "" {
+		if pricing, err = svc.ResolvedPriceConvert(ctx, pricing, req.Currency); err != nil {
+			return nil, err
+		}
+		resp.ExchangeRate = pricing.ExchangeRate
+	}
+
+	resp.Currency = pricing.Currency
+	resp.Price = pricing.EffectivePrice
+	resp.ListPrice = pricing.ListPrice
+	resp.EffectivePrice = pricing.EffectivePrice
+	resp.OnSale = pricing.OnSale
+
+	if req.VariantId > 0 {
+		variant, err := svc.ProductVariantGetByID(ctx, productDetail.ID, int(req.VariantId))
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Stock = int64(variant.Stock)
+		resp.Sku = variant.SKU
+		resp.Variant = &product.ProductVariant{
+			Id:      int64(variant.ID),
+			Sku:     variant.SKU,
+			Price:   resp.Price,
+			Stock:   int64(variant.Stock),
+			Options: variant.Options,
+		}
+	}
+
+	locations, err := svc.LocationStockGetByProductID(ctx, productDetail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, location := range locations {
+		if req.VariantId > 0 && location.VariantID != int(req.VariantId) {
+			continue
+		}
+		resp.Locations = append(resp.Locations, toLocationStock(location))
+	}
+
+	attributes, err := svc.ProductGetAttributes(ctx, productDetail.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if productDetail.Type == string(model.PRODUCT_TYPE_BUNDLE) {
+		components, err := svc.BundleComponentGetByBundleID(ctx, productDetail.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, component := range components {
+			resp.Components = append(resp.Components, &product.BundleComponent{
+				ProductId: int64(component.ComponentID),
+				VariantId: int64(component.VariantID),
+				Quantity:  int64(component.Quantity),
+				Name:      component.Name,
+				Sku:       component.SKU,
+			})
+		}
+	}
+
+	for _, attribute := range attributes {
+		resp.Attributes = append(resp.Attributes, &product.ProductAttribute{
+			Code:  attribute.Code,
+			Name:  attribute.Name,
+			Type:  attribute.Type,
+			Unit:  attribute.Unit,
+			Value: attribute.Value,
+		})
+	}
+
+	return resp, nil
 }
 
 func (s Server) UpdateStock(ctx context.Context, req *product.UpdateStockRequest) (*product.UpdateStockResponse, error) {
 	ID := req.Id
 	qty := req.QtyBought
+	variantID := req.VariantId
+	orderID := req.OrderId
 
 	tx := service.GetTransaction()
 
@@ -41,14 +222,315 @@ func (s Server) UpdateStock(ctx context.Context, req *product.UpdateStockRequest
 		}
 	}()
 
-	success, err := tx.ProductUpdateStock(ctx, int(ID), int(qty))
+	allocations, err := tx.ProductUpdateStock(ctx, int(ID), int(variantID), int(qty), int(orderID))
 	if err != nil {
+		tx.DB.Rollback()
 		return nil, err
 	}
 
 	tx.Commit()
 
 	return &product.UpdateStockResponse{
+		Success:     true,
+		Allocations: toStockAllocations(allocations),
+	}, nil
+}
+
+func (s Server) SearchProducts(ctx context.Context, req *product.SearchProductsRequest) (*product.SearchProductsResponse, error) {
+	filter := model.ProductSearchFilter{
+		Query:       req.Query,
+		MinPrice:    req.MinPrice,
+		MaxPrice:    req.MaxPrice,
+		InStockOnly: req.InStockOnly,
+		Sort:        model.ProductSort(req.Sort),
+		Cursor:      req.Cursor,
+		Limit:       int(req.Limit),
+		Currency:    req.Currency,
+	}
+
+	if req.SellerId != nil {
+		sellerID := int(*req.SellerId)
+		filter.SellerID = &sellerID
+	}
+
+	if req.CategoryId != nil {
+		categoryID := int(*req.CategoryId)
+		filter.CategoryID = &categoryID
+	}
+
+	if req.CreatedAfter != "" {
+		createdAfter, err := time.Parse(time.RFC3339, req.CreatedAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid created_after: %w", err)
+		}
+		filter.CreatedAfter = &createdAfter
+	}
+
+	products, nextCursor, err := service.GetService().ProductSearch(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &product.SearchProductsResponse{
+		Products:   make([]*product.ProductItem, 0, len(products)),
+		NextCursor: nextCursor,
+	}
+
+	for _, p := range products {
+		resp.Products = append(resp.Products, toProductItem(p))
+	}
+
+	return resp, nil
+}
+
+func toProductItem(p *model.Product) *product.ProductItem {
+	item := &product.ProductItem{
+		Id:            int64(p.ID),
+		SellerId:      int64(p.SellerID),
+		Name:          p.Name,
+		Description:   p.Description,
+		Price:         p.Price,
+		Currency:      p.Currency,
+		Stock:         int64(p.Stock),
+		ShopName:      p.ShopName,
+		SoldCount:     int64(p.SoldCount),
+		CreatedAt:     p.CreatedAt.Format(time.RFC3339),
+		RatingAverage: p.RatingAverage,
+		RatingCount:   int64(p.RatingCount),
+	}
+
+	if p.SKU != nil {
+		item.Sku = *p.SKU
+	}
+	if p.UpdatedAt != nil {
+		item.UpdatedAt = p.UpdatedAt.Format(time.RFC3339)
+	}
+
+	return item
+}
+
+func (s Server) ReserveStock(ctx context.Context, req *product.ReserveStockRequest) (*product.ReserveStockResponse, error) {
+	items := make([]model.ReserveItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, model.ReserveItem{
+			ProductID: int(item.ProductId),
+			VariantID: int(item.VariantId),
+			Quantity:  int(item.Quantity),
+		})
+	}
+
+	tx := service.GetTransaction()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(r)
+			panic(r)
+		}
+	}()
+
+	reservation, err := tx.ReservationCreate(ctx, items, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		tx.DB.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	resp := &product.ReserveStockResponse{
+		ReservationId: int64(reservation.ID),
+		ExpiresAt:     reservation.ExpiresAt.Format(time.RFC3339),
+	}
+
+	for _, item := range reservation.Items {
+		resp.Allocations = append(resp.Allocations, toStockAllocations(item.Allocations)...)
+	}
+
+	return resp, nil
+}
+
+func (s Server) CommitReservation(ctx context.Context, req *product.ReservationRequest) (*product.ReservationResponse, error) {
+	tx := service.GetTransaction()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(r)
+			panic(r)
+		}
+	}()
+
+	success, err := tx.ReservationCommit(ctx, int(req.ReservationId), int(req.OrderId))
+	if err != nil {
+		tx.DB.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &product.ReservationResponse{
 		Success: success,
 	}, nil
 }
+
+func (s Server) ReleaseReservation(ctx context.Context, req *product.ReservationRequest) (*product.ReservationResponse, error) {
+	tx := service.GetTransaction()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(r)
+			panic(r)
+		}
+	}()
+
+	success, err := tx.ReservationRelease(ctx, int(req.ReservationId))
+	if err != nil {
+		tx.DB.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &product.ReservationResponse{
+		Success: success,
+	}, nil
+}
+
+func (s Server) IncrementStock(ctx context.Context, req *product.IncrementStockRequest) (*product.IncrementStockResponse, error) {
+	restore := model.StockRestore{
+		OrderID:   int(req.OrderId),
+		Reason:    model.MovementReason(req.Reason),
+		Reference: req.Reference,
+	}
+	for _, item := range req.Items {
+		restore.Items = append(restore.Items, model.ReserveItem{
+			ProductID: int(item.ProductId),
+			VariantID: int(item.VariantId),
+			Quantity:  int(item.Quantity),
+		})
+	}
+
+	tx := service.GetTransaction()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback(r)
+			panic(r)
+		}
+	}()
+
+	applied, err := tx.StockRestore(ctx, restore)
+	if err != nil {
+		tx.DB.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &product.IncrementStockResponse{
+		Success: true,
+		Applied: applied,
+	}, nil
+}
+
+func (s Server) GetStockAvailability(ctx context.Context, req *product.StockAvailabilityRequest) (*product.StockAvailabilityResponse, error) {
+	svc := service.GetService()
+
+	resp := &product.StockAvailabilityResponse{
+		Items: make([]*product.StockAvailability, 0, len(req.Items)),
+	}
+
+	for _, item := range req.Items {
+		availability, err := svc.StockGetAvailability(ctx, int(item.ProductId), int(item.VariantId))
+		if err != nil {
+			return nil, err
+		}
+
+		stockAvailability := &product.StockAvailability{
+			ProductId: int64(availability.ProductID),
+			VariantId: int64(availability.VariantID),
+			Stock:     int64(availability.Stock),
+		}
+		for _, location := range availability.Locations {
+			stockAvailability.Locations = append(stockAvailability.Locations, toLocationStock(location))
+		}
+
+		resp.Items = append(resp.Items, stockAvailability)
+	}
+
+	return resp, nil
+}
+
+func toLocationStock(location *model.LocationAvailability) *product.LocationStock {
+	return &product.LocationStock{
+		WarehouseId:   int64(location.WarehouseID),
+		WarehouseName: location.WarehouseName,
+		Priority:      int64(location.Priority),
+		VariantId:     int64(location.VariantID),
+		Stock:         int64(location.Stock),
+	}
+}
+
+func toStockAllocations(allocations []*model.StockAllocation) []*product.StockAllocation {
+	items := make([]*product.StockAllocation, 0, len(allocations))
+	for _, allocation := range allocations {
+		items = append(items, &product.StockAllocation{
+			ProductId:   int64(allocation.ProductID),
+			VariantId:   int64(allocation.VariantID),
+			WarehouseId: int64(allocation.WarehouseID),
+			Quantity:    int64(allocation.Quantity),
+		})
+	}
+
+	return items
+}
+
+func (s Server) GetRecommendations(ctx context.Context, req *product.RecommendationRequest) (*product.RecommendationResponse, error) {
+	svc := service.GetService()
+
+	related, err := svc.ProductGetRelated(ctx, int(req.ProductId), int(req.Limit))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &product.RecommendationResponse{
+		Recommendations: make([]*product.Recommendation, 0, len(related)),
+	}
+
+	for _, item := range related {
+		pricing, err := svc.ProductResolvePrice(ctx, item.ID, 0, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Recommendations = append(resp.Recommendations, &product.Recommendation{
+			ProductId: int64(item.ID),
+			Name:      item.Name,
+			Price:     pricing.EffectivePrice,
+			Currency:  pricing.Currency,
+			ShopName:  item.ShopName,
+			Source:    string(item.Source),
+			Score:     item.Score,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s Server) GetCurrencies(ctx context.Context, req *product.GetCurrenciesRequest) (*product.GetCurrenciesResponse, error) {
+	codes, err := service.GetService().ExchangeRateCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &product.GetCurrenciesResponse{
+		Reference:  codes[0],
+		Currencies: codes,
+	}, nil
+}
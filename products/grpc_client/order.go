@@ -0,0 +1,42 @@
+package grpcclient
+
+import (
+	"context"
+	"utils/orders"
+)
+
+func VerifyPurchase(ctx context.Context, req *orders.VerifyPurchaseRequest) (*orders.VerifyPurchaseResponse, error) {
+	orderConn, conn := orders.Connect(orders.ConnectionOption{})
+	defer conn.Close()
+
+	purchase, err := orderConn.VerifyPurchase(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return purchase, nil
+}
+
+func GetProductSales(ctx context.Context, req *orders.ProductSalesRequest) (*orders.ProductSalesResponse, error) {
+	orderConn, conn := orders.Connect(orders.ConnectionOption{})
+	defer conn.Close()
+
+	sales, err := orderConn.GetProductSales(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return sales, nil
+}
+
+func GetCoPurchases(ctx context.Context, req *orders.CoPurchaseRequest) (*orders.CoPurchaseResponse, error) {
+	orderConn, conn := orders.Connect(orders.ConnectionOption{})
+	defer conn.Close()
+
+	coPurchases, err := orderConn.GetCoPurchases(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return coPurchases, nil
+}
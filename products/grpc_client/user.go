@@ -11,7 +11,7 @@ func CheckSellerExists(ctx context.Context, id *user.CheckSellerExistsRequest) (
 
 	sellerExist, err := userConn.CheckSellerExists(ctx, id)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return sellerExist, nil
@@ -23,7 +23,7 @@ func GetSellerDetails(ctx context.Context, id *user.GetSellerDetailsRequest) (*u
 
 	seller, err := userConn.GetSellerDetails(ctx, id)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 
 	return seller, nil
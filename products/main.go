@@ -8,7 +8,9 @@ import (
 	"products/config"
 	"products/grpc/resolver"
 	"products/router"
+	"products/service"
 	"sync"
+	"time"
 	"utils/middleware"
 	"utils/product"
 
@@ -19,6 +21,12 @@ import (
 const (
 	defaultPort     = "8080"
 	defaultGRPCPort = "50051"
+
+	reservationSweepInterval = time.Minute
+	stockAlertScanInterval   = 5 * time.Minute
+	demandForecastInterval   = 24 * time.Hour
+	productAffinityInterval  = 6 * time.Hour
+	idempotencyPurgeInterval = time.Hour
 )
 
 func init() {
@@ -43,6 +51,12 @@ func main() {
 
 	var wg sync.WaitGroup
 
+	go service.StartReservationSweeper(reservationSweepInterval)
+	go service.StartStockAlertScanner(stockAlertScanInterval)
+	go service.StartDemandForecaster(demandForecastInterval)
+	go service.StartAffinityBuilder(productAffinityInterval)
+	go middleware.PurgeIdempotencyKeys(db, idempotencyPurgeInterval)
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type StockAlertStatus string
+
+const (
+	STOCK_ALERT_STATUS_ACTIVE       StockAlertStatus = "active"
+	STOCK_ALERT_STATUS_ACKNOWLEDGED StockAlertStatus = "acknowledged"
+	STOCK_ALERT_STATUS_SNOOZED      StockAlertStatus = "snoozed"
+	STOCK_ALERT_STATUS_RESOLVED     StockAlertStatus = "resolved"
+)
+
+// StockAlert is raised when a product falls to or below its reorder
+// threshold. It stays open until stock is back above the threshold, so a
+// product never has more than one open alert.
+type StockAlert struct {
+	ID             int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID      int        `json:"product_id" gorm:"type:int;not null;index"`
+	SellerID       int        `json:"seller_id" gorm:"type:int;not null;index"`
+	ProductName    string     `json:"product_name" gorm:"type:varchar(100);not null"`
+	Stock          int        `json:"stock" gorm:"type:int;not null"`
+	Threshold      int        `json:"threshold" gorm:"type:int;not null"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null;index"`
+	SnoozedUntil   *time.Time `json:"snoozed_until" gorm:"type:timestamp;null"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at" gorm:"type:timestamp;null"`
+	ResolvedAt     *time.Time `json:"resolved_at" gorm:"type:timestamp;null"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt      *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+}
+
+type UpdateReorderThreshold struct {
+	ReorderThreshold *int `json:"reorder_threshold"`
+}
+
+type SnoozeStockAlert struct {
+	Minutes int `json:"minutes"`
+}
+
+type StockAlertResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    *StockAlert `json:"data"`
+}
+
+type StockAlertListResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    []*StockAlert `json:"data"`
+}
@@ -0,0 +1,123 @@
+package model
+
+import "time"
+
+type AttributeType string
+
+const (
+	ATTRIBUTE_TYPE_STRING AttributeType = "string"
+	ATTRIBUTE_TYPE_NUMBER AttributeType = "number"
+	ATTRIBUTE_TYPE_BOOL   AttributeType = "bool"
+	ATTRIBUTE_TYPE_ENUM   AttributeType = "enum"
+)
+
+// CategoryAttribute is a typed spec that products in a category, or in any
+// category below it, can fill in. The code identifies the attribute in search
+// filters, so categories that share a code are faceted together.
+type CategoryAttribute struct {
+	ID         int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	CategoryID int        `json:"category_id" gorm:"type:int;not null;index"`
+	Code       string     `json:"code" gorm:"type:varchar(50);not null;index"`
+	Name       string     `json:"name" gorm:"type:varchar(100);not null"`
+	Type       string     `json:"type" gorm:"type:varchar(10);not null"`
+	Unit       string     `json:"unit" gorm:"type:varchar(20)"`
+	Required   bool       `json:"required" gorm:"type:boolean;not null;default:false"`
+	Filterable bool       `json:"filterable" gorm:"type:boolean;not null;default:true"`
+	Position   int        `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt  *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt  *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+	Options    []string   `json:"options" gorm:"-"`
+}
+
+// CategoryAttributeOption is one allowed value of an enum attribute.
+type CategoryAttributeOption struct {
+	ID          int    `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	AttributeID int    `json:"attribute_id" gorm:"type:int;not null;uniqueIndex:idx_attribute_option"`
+	Value       string `json:"value" gorm:"type:varchar(100);not null;uniqueIndex:idx_attribute_option"`
+	Position    int    `json:"position" gorm:"type:int;not null;default:0"`
+}
+
+// ProductAttribute is the value a product has for an attribute. Values are
+// stored as text so every type can be faceted the same way; numbers are also
+// kept as numbers for range filters.
+type ProductAttribute struct {
+	ID          int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID   int        `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_product_attribute"`
+	AttributeID int        `json:"attribute_id" gorm:"type:int;not null;uniqueIndex:idx_product_attribute;index"`
+	Value       string     `json:"value" gorm:"type:varchar(255);not null"`
+	NumberValue *float64   `json:"-" gorm:"type:decimal(20,4);null"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt   *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+}
+
+// ProductAttributeValue is an attribute value of a product together with the
+// definition needed to display it.
+type ProductAttributeValue struct {
+	AttributeID int    `json:"attribute_id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Unit        string `json:"unit"`
+	Value       string `json:"value"`
+}
+
+type NewCategoryAttribute struct {
+	Code       string        `json:"code"`
+	Name       string        `json:"name"`
+	Type       AttributeType `json:"type"`
+	Unit       string        `json:"unit"`
+	Options    []string      `json:"options"`
+	Required   bool          `json:"required"`
+	Filterable *bool         `json:"filterable"`
+	Position   int           `json:"position"`
+}
+
+// UpdateCategoryAttribute changes how an attribute is shown and validated. The
+// code and type cannot change once products may have values for them.
+type UpdateCategoryAttribute struct {
+	Name       string   `json:"name"`
+	Unit       *string  `json:"unit"`
+	Options    []string `json:"options"`
+	Required   *bool    `json:"required"`
+	Filterable *bool    `json:"filterable"`
+	Position   *int     `json:"position"`
+}
+
+// SetProductAttributes replaces every attribute value of a product. Values
+// are keyed by attribute code and must match the attribute type.
+type SetProductAttributes struct {
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+type AttributeFacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// AttributeFacet counts the matching products for each value of a filterable
+// attribute.
+type AttributeFacet struct {
+	Code   string                 `json:"code"`
+	Name   string                 `json:"name"`
+	Unit   string                 `json:"unit"`
+	Values []*AttributeFacetValue `json:"values"`
+}
+
+type CategoryAttributeResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    *CategoryAttribute `json:"data"`
+}
+
+type CategoryAttributeListResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    []*CategoryAttribute `json:"data"`
+}
+
+type ProductAttributeListResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Data    []*ProductAttributeValue `json:"data"`
+}
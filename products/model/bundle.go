@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+type ProductType string
+
+const (
+	PRODUCT_TYPE_SIMPLE ProductType = "simple"
+	// PRODUCT_TYPE_BUNDLE products hold no stock of their own; they are sold
+	// as a set of component products.
+	PRODUCT_TYPE_BUNDLE ProductType = "bundle"
+)
+
+// BundleComponent is a product, or one variant of it, included in a bundle.
+// Quantity units of it are taken for every bundle sold.
+type BundleComponent struct {
+	ID          int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	BundleID    int       `json:"bundle_id" gorm:"type:int;not null;uniqueIndex:idx_bundle_component"`
+	ComponentID int       `json:"component_id" gorm:"type:int;not null;uniqueIndex:idx_bundle_component;index"`
+	VariantID   int       `json:"variant_id" gorm:"type:int;not null;default:0;uniqueIndex:idx_bundle_component"`
+	Quantity    int       `json:"quantity" gorm:"type:int;not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+	Name        string    `json:"name" gorm:"-"`
+	SKU         string    `json:"sku" gorm:"-"`
+}
+
+type NewBundleComponent struct {
+	ProductID int `json:"product_id"`
+	VariantID int `json:"variant_id"`
+	Quantity  int `json:"quantity"`
+}
+
+type SetBundleComponents struct {
+	Components []NewBundleComponent `json:"components"`
+}
+
+type BundleComponentListResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    []*BundleComponent `json:"data"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+type Category struct {
+	ID        int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ParentID  *int       `json:"parent_id" gorm:"type:int;null;index"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+	Slug      string     `json:"slug" gorm:"type:varchar(100);unique;not null"`
+	Path      string     `json:"path" gorm:"type:varchar(255);not null;index"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+}
+
+type ProductCategory struct {
+	ID         int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID  int       `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_product_category"`
+	CategoryID int       `json:"category_id" gorm:"type:int;not null;uniqueIndex:idx_product_category"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+type NewCategory struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ParentID *int   `json:"parent_id"`
+}
+
+// UpdateCategory changes only the fields that are set. A category keeps its
+// parent unless ParentID moves it under another one or MoveToRoot is set.
+type UpdateCategory struct {
+	ID         int    `json:"-"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	ParentID   *int   `json:"parent_id"`
+	MoveToRoot bool   `json:"move_to_root"`
+}
+
+type AssignCategories struct {
+	CategoryIDs []int `json:"category_ids"`
+}
+
+type CategoryResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message"`
+	Data    *Category `json:"data"`
+}
+
+type CategoryListResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    []*Category `json:"data"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+type ExchangeRateSource string
+
+const (
+	EXCHANGE_RATE_SOURCE_ADMIN ExchangeRateSource = "admin"
+	EXCHANGE_RATE_SOURCE_FILE  ExchangeRateSource = "file"
+)
+
+// ExchangeRateCSVHeader is the header row rate files must start with.
+var ExchangeRateCSVHeader = []string{"currency", "rate"}
+
+// ExchangeRate is how many units of a currency one unit of the reference
+// currency buys. The reference currency itself has no row; its rate is
+// always 1.
+type ExchangeRate struct {
+	Currency  string    `json:"currency" gorm:"type:varchar(3);primaryKey"`
+	Rate      float64   `json:"rate" gorm:"type:decimal(18,8);not null"`
+	Source    string    `json:"source" gorm:"type:varchar(10);not null"`
+	UpdatedBy *int      `json:"updated_by" gorm:"type:int;null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"type:timestamp;not null"`
+}
+
+type NewExchangeRate struct {
+	Rate float64 `json:"rate"`
+}
+
+// ExchangeRateTable lists every known rate against the reference currency.
+type ExchangeRateTable struct {
+	Reference string          `json:"reference"`
+	Rates     []*ExchangeRate `json:"rates"`
+}
+
+type ExchangeRateResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    *ExchangeRate `json:"data"`
+}
+
+type ExchangeRateTableResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    *ExchangeRateTable `json:"data"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// DemandForecast is the latest demand forecast for a product, replaced every
+// time the forecasts are recomputed.
+type DemandForecast struct {
+	ID              int                    `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID       int                    `json:"product_id" gorm:"type:int;not null;uniqueIndex"`
+	SellerID        int                    `json:"seller_id" gorm:"type:int;not null;index"`
+	Method          string                 `json:"method" gorm:"type:varchar(20);not null"`
+	Alpha           float64                `json:"alpha" gorm:"type:decimal(4,2);not null"`
+	Beta            float64                `json:"beta" gorm:"type:decimal(4,2);not null"`
+	Gamma           float64                `json:"gamma" gorm:"type:decimal(4,2);not null"`
+	RMSE            float64                `json:"rmse" gorm:"type:decimal(12,4);not null"`
+	HistoryDays     int                    `json:"history_days" gorm:"type:int;not null"`
+	Stock           int                    `json:"stock" gorm:"type:int;not null"`
+	LeadTimeDays    int                    `json:"lead_time_days" gorm:"type:int;not null"`
+	ReorderPoint    int                    `json:"reorder_point" gorm:"type:int;not null"`
+	ReorderQuantity int                    `json:"reorder_quantity" gorm:"type:int;not null"`
+	StockOutDate    *time.Time             `json:"stock_out_date" gorm:"type:date;null"`
+	GeneratedAt     time.Time              `json:"generated_at" gorm:"type:timestamp;not null"`
+	Points          []*DemandForecastPoint `json:"points" gorm:"-"`
+}
+
+// DemandForecastPoint is the expected demand for one day, with the bounds of
+// its 95% prediction interval.
+type DemandForecastPoint struct {
+	ID         int       `json:"-" gorm:"type:int;primaryKey;autoIncrement"`
+	ForecastID int       `json:"-" gorm:"type:int;not null;index"`
+	Date       time.Time `json:"date" gorm:"type:date;not null"`
+	Demand     float64   `json:"demand" gorm:"type:decimal(12,2);not null"`
+	Lower      float64   `json:"lower" gorm:"type:decimal(12,2);not null"`
+	Upper      float64   `json:"upper" gorm:"type:decimal(12,2);not null"`
+}
+
+type DemandForecastResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    *DemandForecast `json:"data"`
+}
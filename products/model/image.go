@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type ProductImage struct {
+	ID          int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID   int        `json:"product_id" gorm:"type:int;not null;index"`
+	StorageKey  string     `json:"-" gorm:"type:varchar(255);not null"`
+	URL         string     `json:"url" gorm:"-"`
+	ContentType string     `json:"content_type" gorm:"type:varchar(50);not null"`
+	Size        int64      `json:"size" gorm:"type:bigint;not null"`
+	Position    int        `json:"position" gorm:"type:int;not null;default:0"`
+	IsPrimary   bool       `json:"is_primary" gorm:"type:boolean;not null;default:false"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	DeletedAt   *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+}
+
+type ReorderImages struct {
+	ImageIDs []int `json:"image_ids"`
+}
+
+type ProductImageResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    *ProductImage `json:"data"`
+}
+
+type ProductImageListResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    []*ProductImage `json:"data"`
+}
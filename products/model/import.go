@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+type ImportJobStatus string
+
+const (
+	IMPORT_JOB_STATUS_PENDING   ImportJobStatus = "pending"
+	IMPORT_JOB_STATUS_RUNNING   ImportJobStatus = "running"
+	IMPORT_JOB_STATUS_COMPLETED ImportJobStatus = "completed"
+	IMPORT_JOB_STATUS_FAILED    ImportJobStatus = "failed"
+)
+
+// CatalogCSVHeader is the column layout shared by catalog import and export.
+var CatalogCSVHeader = []string{"name", "description", "price", "stock", "sku", "category"}
+
+type ImportJob struct {
+	ID            int               `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	SellerID      int               `json:"seller_id" gorm:"type:int;not null;index"`
+	FileName      string            `json:"file_name" gorm:"type:varchar(255)"`
+	Status        string            `json:"status" gorm:"type:varchar(20);not null"`
+	TotalRows     int               `json:"total_rows" gorm:"type:int;not null;default:0"`
+	ProcessedRows int               `json:"processed_rows" gorm:"type:int;not null;default:0"`
+	CreatedRows   int               `json:"created_rows" gorm:"type:int;not null;default:0"`
+	UpdatedRows   int               `json:"updated_rows" gorm:"type:int;not null;default:0"`
+	FailedRows    int               `json:"failed_rows" gorm:"type:int;not null;default:0"`
+	Message       string            `json:"message" gorm:"type:varchar(255)"`
+	CreatedAt     time.Time         `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt     *time.Time        `json:"updated_at" gorm:"type:timestamp;null"`
+	FinishedAt    *time.Time        `json:"finished_at" gorm:"type:timestamp;null"`
+	Errors        []*ImportJobError `json:"errors" gorm:"-"`
+}
+
+type ImportJobError struct {
+	ID        int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	JobID     int       `json:"job_id" gorm:"type:int;not null;index"`
+	Line      int       `json:"line" gorm:"type:int;not null"`
+	SKU       string    `json:"sku" gorm:"type:varchar(100)"`
+	Message   string    `json:"message" gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// CatalogRow is a single parsed line of a catalog CSV. Line is the position in
+// the file, counting the header, so errors point at what the seller sees.
+type CatalogRow struct {
+	Line        int
+	Name        string
+	Description string
+	Price       string
+	Stock       string
+	SKU         string
+	Category    string
+	// Error is a problem found while reading the line, such as a missing
+	// column. The row fails with it when the job reaches it.
+	Error string
+}
+
+type ImportJobResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message"`
+	Data    *ImportJob `json:"data"`
+}
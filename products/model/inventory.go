@@ -0,0 +1,93 @@
+package model
+
+import "time"
+
+type MovementReason string
+
+const (
+	MOVEMENT_REASON_SALE                MovementReason = "sale"
+	MOVEMENT_REASON_RESTOCK             MovementReason = "restock"
+	MOVEMENT_REASON_MANUAL_ADJUSTMENT   MovementReason = "manual_adjustment"
+	MOVEMENT_REASON_RETURN              MovementReason = "return"
+	MOVEMENT_REASON_RESERVATION_RELEASE MovementReason = "reservation_release"
+	MOVEMENT_REASON_RESERVATION_EXPIRY  MovementReason = "reservation_expiry"
+	MOVEMENT_REASON_ORDER_CANCEL        MovementReason = "order_cancel"
+	MOVEMENT_REASON_OPENING_BALANCE     MovementReason = "opening_balance"
+)
+
+// InventoryMovement is an append-only ledger entry. Rows are never updated or
+// deleted, so summing Delta for a product gives its expected stock.
+type InventoryMovement struct {
+	ID            int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID     int       `json:"product_id" gorm:"type:int;not null;index"`
+	VariantID     int       `json:"variant_id" gorm:"type:int;not null;default:0"`
+	WarehouseID   *int      `json:"warehouse_id" gorm:"type:int;null"`
+	Delta         int       `json:"delta" gorm:"type:int;not null"`
+	StockAfter    int       `json:"stock_after" gorm:"type:int;not null"`
+	Reason        string    `json:"reason" gorm:"type:varchar(30);not null"`
+	ActorID       *int      `json:"actor_id" gorm:"type:int;null"`
+	OrderID       *int      `json:"order_id" gorm:"type:int;null"`
+	ReservationID *int      `json:"reservation_id" gorm:"type:int;null;index"`
+	Note          string    `json:"note" gorm:"type:varchar(255)"`
+	Reference     string    `json:"reference" gorm:"type:varchar(50);index"`
+	CreatedAt     time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// StockChange describes a single stock update and the reason recorded for it
+// in the ledger. Quantity is always positive; the direction comes from the
+// method it is passed to. WarehouseID pins the change to one location of a
+// product that tracks stock per location; 0 lets allocation choose.
+type StockChange struct {
+	ProductID         int
+	VariantID         int
+	Quantity          int
+	Reason            MovementReason
+	OrderID           *int
+	ReservationID     *int
+	ReservationItemID *int
+	WarehouseID       int
+	Note              string
+	Reference         string
+}
+
+// AppliedStockRestore records a stock restore by its reference. The unique
+// key is what stops two concurrent restores with the same reference from both
+// putting stock back.
+type AppliedStockRestore struct {
+	ID        int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	Reference string    `json:"reference" gorm:"type:varchar(50);not null;uniqueIndex"`
+	OrderID   int       `json:"order_id" gorm:"type:int;not null;index"`
+	Reason    string    `json:"reason" gorm:"type:varchar(30);not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// StockRestore puts sold stock back, for a cancelled order or for returned
+// items. Reference names the restore, so that a retried one is applied once.
+type StockRestore struct {
+	OrderID   int
+	Reason    MovementReason
+	Reference string
+	Items     []ReserveItem
+}
+
+type NewStockAdjustment struct {
+	VariantID   int            `json:"variant_id"`
+	WarehouseID int            `json:"warehouse_id"`
+	Quantity    int            `json:"quantity"`
+	Reason      MovementReason `json:"reason"`
+	Note        string         `json:"note"`
+}
+
+type InventoryDrift struct {
+	ProductID   int `json:"product_id"`
+	VariantID   int `json:"variant_id"`
+	Stock       int `json:"stock"`
+	LedgerStock int `json:"ledger_stock"`
+	Drift       int `json:"drift"`
+}
+
+type InventoryMovementListResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    []*InventoryMovement `json:"data"`
+}
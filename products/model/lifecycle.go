@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+type ProductStatus string
+
+const (
+	PRODUCT_STATUS_DRAFT          ProductStatus = "draft"
+	PRODUCT_STATUS_PENDING_REVIEW ProductStatus = "pending_review"
+	PRODUCT_STATUS_ACTIVE         ProductStatus = "active"
+	PRODUCT_STATUS_REJECTED       ProductStatus = "rejected"
+	PRODUCT_STATUS_ARCHIVED       ProductStatus = "archived"
+)
+
+type ProductModerationAction string
+
+const (
+	PRODUCT_MODERATION_APPROVE ProductModerationAction = "approve"
+	PRODUCT_MODERATION_REJECT  ProductModerationAction = "reject"
+)
+
+// ProductStatusHistory records every lifecycle transition of a product.
+type ProductStatusHistory struct {
+	ID         int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID  int       `json:"product_id" gorm:"type:int;not null;index"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	Reason     string    `json:"reason" gorm:"type:varchar(500)"`
+	ActorID    *int      `json:"actor_id" gorm:"type:int;null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+type ModerateProduct struct {
+	Action ProductModerationAction `json:"action"`
+	Reason string                  `json:"reason"`
+}
+
+type ProductStatusHistoryListResponse struct {
+	Success bool                    `json:"success"`
+	Message string                  `json:"message"`
+	Data    []*ProductStatusHistory `json:"data"`
+}
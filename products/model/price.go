@@ -0,0 +1,90 @@
+package model
+
+import "time"
+
+type PriceChangeReason string
+
+const (
+	PRICE_CHANGE_REASON_MANUAL             PriceChangeReason = "manual_update"
+	PRICE_CHANGE_REASON_SCHEDULE_CREATED   PriceChangeReason = "schedule_created"
+	PRICE_CHANGE_REASON_SCHEDULE_CANCELLED PriceChangeReason = "schedule_cancelled"
+)
+
+// PriceSchedule overrides the price of a product, or of one of its variants,
+// while StartsAt <= now < EndsAt. A nil EndsAt keeps the schedule running
+// until it is cancelled. Schedules for the same product and variant never
+// overlap.
+type PriceSchedule struct {
+	ID        int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID int        `json:"product_id" gorm:"type:int;not null;index"`
+	VariantID int        `json:"variant_id" gorm:"type:int;not null;default:0"`
+	ListPrice float64    `json:"list_price" gorm:"type:decimal(10,2);not null"`
+	SalePrice *float64   `json:"sale_price" gorm:"type:decimal(10,2);null"`
+	StartsAt  time.Time  `json:"starts_at" gorm:"type:timestamp;not null;index"`
+	EndsAt    *time.Time `json:"ends_at" gorm:"type:timestamp;null"`
+	CreatedBy *int       `json:"created_by" gorm:"type:int;null"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	DeletedAt *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+}
+
+// PriceHistory is an append-only record of every change made to a product's
+// price, either directly or by adding or cancelling a schedule.
+type PriceHistory struct {
+	ID             int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID      int        `json:"product_id" gorm:"type:int;not null;index"`
+	VariantID      int        `json:"variant_id" gorm:"type:int;not null;default:0"`
+	ListPrice      float64    `json:"list_price" gorm:"type:decimal(10,2);not null"`
+	SalePrice      *float64   `json:"sale_price" gorm:"type:decimal(10,2);null"`
+	EffectiveFrom  time.Time  `json:"effective_from" gorm:"type:timestamp;not null"`
+	EffectiveUntil *time.Time `json:"effective_until" gorm:"type:timestamp;null"`
+	Reason         string     `json:"reason" gorm:"type:varchar(30);not null"`
+	ScheduleID     *int       `json:"schedule_id" gorm:"type:int;null"`
+	ActorID        *int       `json:"actor_id" gorm:"type:int;null"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+type NewPriceSchedule struct {
+	VariantID int        `json:"variant_id"`
+	ListPrice float64    `json:"list_price"`
+	SalePrice *float64   `json:"sale_price"`
+	StartsAt  time.Time  `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+}
+
+// ResolvedPrice is the price of a product or variant at a given instant.
+type ResolvedPrice struct {
+	ProductID      int        `json:"product_id"`
+	VariantID      int        `json:"variant_id"`
+	ListPrice      float64    `json:"list_price"`
+	EffectivePrice float64    `json:"effective_price"`
+	Currency       string     `json:"currency"`
+	ExchangeRate   float64    `json:"exchange_rate,omitempty"`
+	OnSale         bool       `json:"on_sale"`
+	ScheduleID     *int       `json:"schedule_id"`
+	EndsAt         *time.Time `json:"ends_at"`
+	At             time.Time  `json:"at"`
+}
+
+type PriceScheduleResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    *PriceSchedule `json:"data"`
+}
+
+type PriceScheduleListResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    []*PriceSchedule `json:"data"`
+}
+
+type PriceHistoryListResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    []*PriceHistory `json:"data"`
+}
+
+type ResolvedPriceResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    *ResolvedPrice `json:"data"`
+}
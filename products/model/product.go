@@ -2,32 +2,117 @@ package model
 
 import "time"
 
+// DEFAULT_TAX_CATEGORY is the tax category of products that do not name one.
+const DEFAULT_TAX_CATEGORY = "standard"
+
 type Product struct {
-	ID          int        `json:"id" gorm:"type:int;primaryKey;"`
-	SellerID    int        `json:"seller_id" gorm:"type:int;not null;"`
-	Name        string     `json:"name" gorm:"type:varchar(100);not null;"`
-	Description string     `json:"description" gorm:"type:text;"`
-	Price       float64    `json:"price" gorm:"type:decimal(10,2);not null;"`
-	Stock       int        `json:"stock" gorm:"type:int;not null;"`
-	ShopName    string     `json:"shop_name" gorm:"type:varchar(255);not null"`
-	SKU         *string    `json:"sku" gorm:"type:varchar(100);"`
-	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
-	UpdatedAt   *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
-	DeletedAt   *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+	ID               int        `json:"id" gorm:"type:int;primaryKey;"`
+	SellerID         int        `json:"seller_id" gorm:"type:int;not null;"`
+	Name             string     `json:"name" gorm:"type:varchar(100);not null;"`
+	Description      string     `json:"description" gorm:"type:text;"`
+	Price            float64    `json:"price" gorm:"type:decimal(10,2);not null;"`
+	Currency         string     `json:"currency" gorm:"type:varchar(3);not null;index"`
+	ReferencePrice   float64    `json:"-" gorm:"type:decimal(16,4);not null;default:0;index"`
+	Stock            int        `json:"stock" gorm:"type:int;not null;"`
+	ShopName         string     `json:"shop_name" gorm:"type:varchar(255);not null"`
+	SKU              *string    `json:"sku" gorm:"type:varchar(100);"`
+	SoldCount        int        `json:"sold_count" gorm:"type:int;not null;default:0"`
+	ReorderThreshold *int       `json:"reorder_threshold" gorm:"type:int;null"`
+	RatingAverage    float64    `json:"rating_average" gorm:"type:decimal(3,2);not null;default:0"`
+	RatingCount      int        `json:"rating_count" gorm:"type:int;not null;default:0"`
+	Version          int        `json:"version" gorm:"type:int;not null;default:1"`
+	Status           string     `json:"status" gorm:"type:varchar(20);not null;default:active;index"`
+	Type             string     `json:"type" gorm:"type:varchar(10);not null;default:simple"`
+	TaxCategory      string     `json:"tax_category" gorm:"type:varchar(30);not null;default:standard"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt        *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt        *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+	DisplayPrice     *float64   `json:"display_price,omitempty" gorm:"-"`
+	DisplayCurrency  string     `json:"display_currency,omitempty" gorm:"-"`
 }
 
 type NewProduct struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Stock       int     `json:"stock"`
-	SellerID    int     `json:"-"`
+	Name             string               `json:"name"`
+	Description      string               `json:"description"`
+	Price            float64              `json:"price"`
+	Stock            int                  `json:"stock"`
+	SKU              string               `json:"sku"`
+	CategoryIDs      []int                `json:"category_ids"`
+	ReorderThreshold *int                 `json:"reorder_threshold"`
+	TaxCategory      string               `json:"tax_category"`
+	Submit           bool                 `json:"submit"`
+	Components       []NewBundleComponent `json:"components"`
+	SellerID         int                  `json:"-"`
 }
 
 type UpdateProduct struct {
-	ID          int
-	Name        *string
-	Description *string
-	Price       *float64
-	Stock       *int
+	ID               int      `json:"-"`
+	Name             *string  `json:"name"`
+	Description      *string  `json:"description"`
+	Price            *float64 `json:"price"`
+	Stock            *int     `json:"stock"`
+	ReorderThreshold *int     `json:"reorder_threshold"`
+	TaxCategory      *string  `json:"tax_category"`
+	Version          int      `json:"version"`
+}
+
+type ProductResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message"`
+	Data    *Product `json:"data"`
+}
+
+type ProductSort string
+
+const (
+	PRODUCT_SORT_PRICE_ASC    ProductSort = "price_asc"
+	PRODUCT_SORT_PRICE_DESC   ProductSort = "price_desc"
+	PRODUCT_SORT_NEWEST       ProductSort = "newest"
+	PRODUCT_SORT_BEST_SELLING ProductSort = "best_selling"
+)
+
+type ProductSearchFilter struct {
+	Query        string      `json:"q" form:"q"`
+	MinPrice     *float64    `json:"min_price" form:"min_price"`
+	MaxPrice     *float64    `json:"max_price" form:"max_price"`
+	SellerID     *int        `json:"seller_id" form:"seller_id"`
+	CategoryID   *int        `json:"category_id" form:"category_id"`
+	InStockOnly  bool        `json:"in_stock_only" form:"in_stock_only"`
+	CreatedAfter *time.Time  `json:"created_after" form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Sort         ProductSort `json:"sort" form:"sort"`
+	Cursor       string      `json:"cursor" form:"cursor"`
+	Limit        int         `json:"limit" form:"limit"`
+	// Currency is the display currency. Price bounds are read in it, or in
+	// the default currency when it is empty.
+	Currency string `json:"currency" form:"currency"`
+	// Attributes filters on attribute values keyed by attribute code. Values
+	// are comma separated, or min..max for a number range.
+	Attributes map[string]string `json:"attributes" form:"-"`
+}
+
+type ProductListResponse struct {
+	Success    bool              `json:"success"`
+	Message    string            `json:"message"`
+	Data       []*Product        `json:"data"`
+	NextCursor string            `json:"next_cursor"`
+	Facets     []*AttributeFacet `json:"facets,omitempty"`
+}
+
+type ProductDetail struct {
+	*Product
+	Categories []*Category       `json:"categories"`
+	Images     []*ProductImage   `json:"images"`
+	Options    []*ProductOption  `json:"options"`
+	Variants   []*ProductVariant `json:"variants"`
+	Pricing    *ResolvedPrice    `json:"pricing"`
+	// DisplayPricing is Pricing converted to the currency the buyer asked for.
+	DisplayPricing *ResolvedPrice           `json:"display_pricing,omitempty"`
+	Attributes     []*ProductAttributeValue `json:"attributes"`
+	Components     []*BundleComponent       `json:"components,omitempty"`
+}
+
+type ProductDetailResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    *ProductDetail `json:"data"`
 }
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+type RecommendationSource string
+
+const (
+	RECOMMENDATION_SOURCE_CO_PURCHASE RecommendationSource = "co_purchase"
+	RECOMMENDATION_SOURCE_CATEGORY    RecommendationSource = "category"
+	RECOMMENDATION_SOURCE_SELLER      RecommendationSource = "seller"
+)
+
+// ProductAffinity scores how often a related product is bought together with
+// a product. Scores are the cosine similarity of the two products' order sets
+// and are rebuilt from completed orders on a schedule.
+type ProductAffinity struct {
+	ID               int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID        int       `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_product_affinity"`
+	RelatedProductID int       `json:"related_product_id" gorm:"type:int;not null;uniqueIndex:idx_product_affinity"`
+	Orders           int       `json:"orders" gorm:"type:int;not null"`
+	Score            float64   `json:"score" gorm:"type:decimal(6,4);not null"`
+	ComputedAt       time.Time `json:"computed_at" gorm:"type:timestamp;not null"`
+}
+
+// RelatedProduct is a recommended product together with why it was chosen.
+type RelatedProduct struct {
+	*Product
+	Source RecommendationSource `json:"source"`
+	Score  float64              `json:"score"`
+}
+
+type RelatedProductListResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    []*RelatedProduct `json:"data"`
+}
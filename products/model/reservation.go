@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	RESERVATION_STATUS_ACTIVE    ReservationStatus = "active"
+	RESERVATION_STATUS_COMMITTED ReservationStatus = "committed"
+	RESERVATION_STATUS_RELEASED  ReservationStatus = "released"
+	RESERVATION_STATUS_EXPIRED   ReservationStatus = "expired"
+)
+
+type StockReservation struct {
+	ID        int                     `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	Status    string                  `json:"status" gorm:"type:varchar(20);not null;index"`
+	OrderID   *int                    `json:"order_id" gorm:"type:int;null"`
+	ExpiresAt time.Time               `json:"expires_at" gorm:"type:timestamp;not null;index"`
+	CreatedAt time.Time               `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt *time.Time              `json:"updated_at" gorm:"type:timestamp;null"`
+	Items     []*StockReservationItem `json:"items" gorm:"-"`
+}
+
+type StockReservationItem struct {
+	ID            int                `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ReservationID int                `json:"reservation_id" gorm:"type:int;not null;index"`
+	ProductID     int                `json:"product_id" gorm:"type:int;not null"`
+	VariantID     int                `json:"variant_id" gorm:"type:int;not null;default:0"`
+	Quantity      int                `json:"quantity" gorm:"type:int;not null"`
+	Allocations   []*StockAllocation `json:"allocations" gorm:"-"`
+}
+
+type ReserveItem struct {
+	ProductID int
+	VariantID int
+	Quantity  int
+}
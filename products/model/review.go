@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+type ReviewModerationAction string
+
+const (
+	REVIEW_MODERATION_APPROVE ReviewModerationAction = "approve"
+	REVIEW_MODERATION_HIDE    ReviewModerationAction = "hide"
+)
+
+type Review struct {
+	ID              int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID       int        `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_review_live_product_user"`
+	UserID          int        `json:"user_id" gorm:"type:int;not null;uniqueIndex:idx_review_live_product_user"`
+	OrderID         int        `json:"order_id" gorm:"type:int;not null"`
+	Rating          int        `json:"rating" gorm:"type:tinyint;not null"`
+	Title           string     `json:"title" gorm:"type:varchar(150)"`
+	Body            string     `json:"body" gorm:"type:text"`
+	SellerReply     *string    `json:"seller_reply" gorm:"type:text;null"`
+	SellerRepliedAt *time.Time `json:"seller_replied_at" gorm:"type:timestamp;null"`
+	IsFlagged       bool       `json:"is_flagged" gorm:"type:boolean;not null;default:false;index"`
+	FlagReason      string     `json:"flag_reason,omitempty" gorm:"type:varchar(255)"`
+	IsHidden        bool       `json:"-" gorm:"type:boolean;not null;default:false"`
+	ModeratedBy     *int       `json:"-" gorm:"type:int;null"`
+	ModeratedAt     *time.Time `json:"-" gorm:"type:timestamp;null"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt       *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt       *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+	// Live is true until the review is deleted and NULL after, so the unique
+	// key, which ignores NULLs, only allows one review per buyer at a time.
+	Live *bool `json:"-" gorm:"type:boolean;null;default:true;uniqueIndex:idx_review_live_product_user"`
+}
+
+type NewReview struct {
+	Rating int    `json:"rating"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+type UpdateReview struct {
+	ID     int     `json:"-"`
+	Rating *int    `json:"rating"`
+	Title  *string `json:"title"`
+	Body   *string `json:"body"`
+}
+
+type ReviewReply struct {
+	Reply string `json:"reply"`
+}
+
+type FlagReview struct {
+	Reason string `json:"reason"`
+}
+
+type ModerateReview struct {
+	Action ReviewModerationAction `json:"action"`
+}
+
+type ReviewResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message"`
+	Data    *Review `json:"data"`
+}
+
+type ReviewListResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message"`
+	Data    []*Review `json:"data"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+type ProductOption struct {
+	ID        int                   `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID int                   `json:"product_id" gorm:"type:int;not null;index"`
+	Name      string                `json:"name" gorm:"type:varchar(50);not null"`
+	Position  int                   `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt time.Time             `json:"created_at" gorm:"type:timestamp;not null"`
+	Values    []*ProductOptionValue `json:"values" gorm:"-"`
+}
+
+type ProductOptionValue struct {
+	ID        int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	OptionID  int       `json:"option_id" gorm:"type:int;not null;index"`
+	Value     string    `json:"value" gorm:"type:varchar(50);not null"`
+	Position  int       `json:"position" gorm:"type:int;not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+type ProductVariant struct {
+	ID        int               `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ProductID int               `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_variant_live_options"`
+	OptionKey string            `json:"-" gorm:"type:varchar(255);not null;uniqueIndex:idx_variant_live_options"`
+	SKU       string            `json:"sku" gorm:"type:varchar(100);not null;uniqueIndex:idx_variant_live_sku"`
+	Price     *float64          `json:"price" gorm:"type:decimal(10,2);null"`
+	Stock     int               `json:"stock" gorm:"type:int;not null;default:0"`
+	SoldCount int               `json:"sold_count" gorm:"type:int;not null;default:0"`
+	CreatedAt time.Time         `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt *time.Time        `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt *time.Time        `json:"deleted_at" gorm:"type:timestamp;null"`
+	Options   map[string]string `json:"options" gorm:"-"`
+	// Live is true until the variant is deleted and NULL after, so the unique
+	// keys, which ignore NULLs, only hold for variants that still exist.
+	Live *bool `json:"-" gorm:"type:boolean;null;default:true;uniqueIndex:idx_variant_live_options;uniqueIndex:idx_variant_live_sku"`
+}
+
+type ProductVariantValue struct {
+	ID            int `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	VariantID     int `json:"variant_id" gorm:"type:int;not null;uniqueIndex:idx_variant_value"`
+	OptionValueID int `json:"option_value_id" gorm:"type:int;not null;uniqueIndex:idx_variant_value"`
+}
+
+type NewProductOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type NewProductVariant struct {
+	Options map[string]string `json:"options"`
+	Price   *float64          `json:"price"`
+	Stock   int               `json:"stock"`
+	SKU     string            `json:"sku"`
+}
+
+type UpdateProductVariant struct {
+	ID        int      `json:"-"`
+	ProductID int      `json:"-"`
+	Price     *float64 `json:"price"`
+	Stock     *int     `json:"stock"`
+}
+
+type ProductOptionResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    *ProductOption `json:"data"`
+}
+
+type ProductVariantResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    *ProductVariant `json:"data"`
+}
+
+type ProductVariantListResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    []*ProductVariant `json:"data"`
+}
@@ -0,0 +1,98 @@
+package model
+
+import "time"
+
+// Warehouse is a location a seller ships from. Allocation prefers warehouses
+// with a lower Priority value.
+type Warehouse struct {
+	ID        int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	SellerID  int        `json:"seller_id" gorm:"type:int;not null;index"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+	Code      string     `json:"code" gorm:"type:varchar(30);not null"`
+	Address   string     `json:"address" gorm:"type:varchar(255)"`
+	Priority  int        `json:"priority" gorm:"type:int;not null;default:0"`
+	CreatedAt time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+	DeletedAt *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
+}
+
+// LocationStock is the stock of a product, or one of its variants, held at a
+// single warehouse. Once a product has location stock, its stock column is the
+// sum over all of its locations.
+type LocationStock struct {
+	ID          int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	WarehouseID int        `json:"warehouse_id" gorm:"type:int;not null;uniqueIndex:idx_location_stock"`
+	ProductID   int        `json:"product_id" gorm:"type:int;not null;uniqueIndex:idx_location_stock;index"`
+	VariantID   int        `json:"variant_id" gorm:"type:int;not null;default:0;uniqueIndex:idx_location_stock"`
+	Stock       int        `json:"stock" gorm:"type:int;not null;default:0"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+	UpdatedAt   *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
+}
+
+// StockAllocation records which warehouse stock for a sale was taken from, so
+// a released reservation can put it back where it came from.
+type StockAllocation struct {
+	ID                int       `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	ReservationItemID *int      `json:"reservation_item_id" gorm:"type:int;null;index"`
+	OrderID           *int      `json:"order_id" gorm:"type:int;null"`
+	ProductID         int       `json:"product_id" gorm:"type:int;not null"`
+	VariantID         int       `json:"variant_id" gorm:"type:int;not null;default:0"`
+	WarehouseID       int       `json:"warehouse_id" gorm:"type:int;not null"`
+	Quantity          int       `json:"quantity" gorm:"type:int;not null"`
+	CreatedAt         time.Time `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// LocationAvailability is the stock of a product at one warehouse, as shown
+// to sellers and returned over grpc.
+type LocationAvailability struct {
+	WarehouseID   int    `json:"warehouse_id"`
+	WarehouseName string `json:"warehouse_name"`
+	Priority      int    `json:"priority"`
+	VariantID     int    `json:"variant_id"`
+	Stock         int    `json:"stock"`
+}
+
+type StockAvailability struct {
+	ProductID int                     `json:"product_id"`
+	VariantID int                     `json:"variant_id"`
+	Stock     int                     `json:"stock"`
+	Locations []*LocationAvailability `json:"locations"`
+}
+
+type NewWarehouse struct {
+	Name     string `json:"name"`
+	Code     string `json:"code"`
+	Address  string `json:"address"`
+	Priority int    `json:"priority"`
+}
+
+type UpdateWarehouse struct {
+	ID       int     `json:"-"`
+	Name     *string `json:"name"`
+	Address  *string `json:"address"`
+	Priority *int    `json:"priority"`
+}
+
+type SetLocationStock struct {
+	VariantID int    `json:"variant_id"`
+	Stock     int    `json:"stock"`
+	Note      string `json:"note"`
+}
+
+type WarehouseResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message"`
+	Data    *Warehouse `json:"data"`
+}
+
+type WarehouseListResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    []*Warehouse `json:"data"`
+}
+
+type LocationAvailabilityListResponse struct {
+	Success bool                    `json:"success"`
+	Message string                  `json:"message"`
+	Data    []*LocationAvailability `json:"data"`
+}
@@ -0,0 +1,45 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"products/model"
+	"sync"
+)
+
+// FileNotifier appends every alert as a JSON line to a file.
+type FileNotifier struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{
+		Path: path,
+	}
+}
+
+func (f *FileNotifier) Notify(ctx context.Context, alert *model.StockAlert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(f.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,20 @@
+package notifier
+
+import (
+	"context"
+	"log"
+	"products/model"
+)
+
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (l *LogNotifier) Notify(ctx context.Context, alert *model.StockAlert) error {
+	log.Printf("stock alert %d: seller %d product %d (%s) has %d left, reorder threshold %d",
+		alert.ID, alert.SellerID, alert.ProductID, alert.ProductName, alert.Stock, alert.Threshold)
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package notifier
+
+import (
+	"context"
+	"os"
+	"products/model"
+	"sync"
+)
+
+// Notifier is implemented by every channel that can deliver stock alerts to
+// sellers.
+type Notifier interface {
+	Notify(ctx context.Context, alert *model.StockAlert) error
+}
+
+const (
+	defaultNotifierFile = "stock_alerts.log"
+)
+
+var (
+	notifier Notifier
+	once     sync.Once
+)
+
+func GetNotifier() Notifier {
+	once.Do(func() {
+		switch os.Getenv("ALERT_NOTIFIER") {
+		case "file":
+			path := os.Getenv("ALERT_NOTIFIER_FILE")
+			if path == "" {
+				path = defaultNotifierFile
+			}
+
+			notifier = NewFileNotifier(path)
+		default:
+			notifier = NewLogNotifier()
+		}
+	})
+
+	return notifier
+}
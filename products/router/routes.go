@@ -1,19 +1,101 @@
 package router
 
 import (
+	"products/config"
 	"products/controller"
+	"products/storage"
+	"strings"
 	"utils/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 func ApiRouter(r *gin.Engine) {
-	r.POST("/product/create", controller.CreateProduct)
+	r.POST("/product/create", middleware.Idempotency(config.GetDB()), controller.CreateProduct)
 	r.GET("/product/:id", controller.ProductDetail)
+	r.GET("/products", controller.SearchProducts)
+	r.GET("/product/:id/images", controller.ProductImageList)
+	r.GET("/product/:id/variants", controller.ProductVariantList)
+	r.GET("/product/:id/reviews", controller.ProductReviewList)
+	r.GET("/product/:id/price", controller.ProductPrice)
+	r.GET("/products/:id/related", controller.RelatedProductList)
+	r.GET("/categories", controller.CategoryList)
+	r.GET("/categories/:id/attributes", controller.CategoryAttributeList)
+	r.GET("/exchange-rates", controller.ExchangeRateList)
+
+	// images kept on local disk are served by the products service itself
+	if local, ok := storage.GetStorage().(*storage.LocalStorage); ok && strings.HasPrefix(local.BaseURL, "/") {
+		r.Static(local.BaseURL, local.Dir)
+	}
+
+	buyer := r.Group("")
+	buyer.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin())
+	{
+		buyer.POST("/product/:id/reviews", controller.CreateReview)
+		buyer.PUT("/reviews/:id", controller.UpdateReview)
+		buyer.DELETE("/reviews/:id", controller.DeleteReview)
+		buyer.POST("/reviews/:id/flag", controller.FlagReview)
+	}
 
 	seller := r.Group("")
 	seller.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin(), middleware.IsSeller())
 	{
-		seller.GET("/products/:seller_id}", controller.ProductList)
+		seller.GET("/products/:id", controller.ProductList)
+		seller.PUT("/products/:id", controller.UpdateProduct)
+		seller.PATCH("/products/:id", controller.PatchProduct)
+		seller.DELETE("/products/:id", controller.DeleteProduct)
+		seller.PUT("/product/:id/categories", controller.AssignProductCategories)
+		seller.PUT("/product/:id/attributes", controller.SetProductAttributes)
+		seller.PUT("/product/:id/components", controller.SetBundleComponents)
+		seller.POST("/product/:id/images", controller.UploadProductImage)
+		seller.PUT("/product/:id/images", controller.ReorderProductImages)
+		seller.PUT("/product/:id/images/:image_id/primary", controller.SetPrimaryProductImage)
+		seller.DELETE("/product/:id/images/:image_id", controller.DeleteProductImage)
+		seller.POST("/product/:id/options", controller.CreateProductOption)
+		seller.POST("/product/:id/variants", controller.CreateProductVariant)
+		seller.PUT("/product/:id/variants/:variant_id", controller.UpdateProductVariant)
+		seller.DELETE("/product/:id/variants/:variant_id", controller.DeleteProductVariant)
+		seller.GET("/product/:id/inventory", controller.ProductInventoryMovements)
+		seller.POST("/product/:id/inventory", controller.AdjustProductInventory)
+		seller.POST("/products/import", controller.ImportCatalog)
+		seller.GET("/products/import/:job_id", controller.CatalogImportStatus)
+		seller.GET("/products/export", controller.ExportCatalog)
+		seller.PUT("/product/:id/threshold", controller.UpdateReorderThreshold)
+		seller.GET("/alerts", controller.StockAlertList)
+		seller.PUT("/alerts/:id/acknowledge", controller.AcknowledgeStockAlert)
+		seller.PUT("/alerts/:id/snooze", controller.SnoozeStockAlert)
+		seller.PUT("/reviews/:id/reply", controller.ReplyToReview)
+		seller.GET("/product/:id/prices", controller.PriceScheduleList)
+		seller.POST("/product/:id/prices", controller.CreatePriceSchedule)
+		seller.DELETE("/product/:id/prices/:schedule_id", controller.CancelPriceSchedule)
+		seller.GET("/product/:id/price-history", controller.PriceHistoryList)
+		seller.GET("/warehouses", controller.WarehouseList)
+		seller.POST("/warehouses", controller.CreateWarehouse)
+		seller.PUT("/warehouses/:id", controller.UpdateWarehouse)
+		seller.DELETE("/warehouses/:id", controller.DeleteWarehouse)
+		seller.GET("/product/:id/locations", controller.ProductLocationList)
+		seller.PUT("/product/:id/locations/:warehouse_id", controller.SetProductLocationStock)
+		seller.POST("/product/:id/submit", controller.SubmitProduct)
+		seller.POST("/product/:id/archive", controller.ArchiveProduct)
+		seller.GET("/product/:id/status-history", controller.ProductStatusHistoryList)
+		seller.GET("/product/:id/forecast", controller.ProductDemandForecast)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin(), middleware.IsAdmin())
+	{
+		admin.POST("/categories", controller.CreateCategory)
+		admin.PUT("/categories/:id", controller.UpdateCategory)
+		admin.DELETE("/categories/:id", controller.DeleteCategory)
+		admin.POST("/categories/:id/attributes", controller.CreateCategoryAttribute)
+		admin.PUT("/categories/:id/attributes/:attribute_id", controller.UpdateCategoryAttribute)
+		admin.DELETE("/categories/:id/attributes/:attribute_id", controller.DeleteCategoryAttribute)
+		admin.GET("/reviews/flagged", controller.FlaggedReviewList)
+		admin.PUT("/reviews/:id/moderate", controller.ModerateReview)
+		admin.GET("/products/pending", controller.PendingProductList)
+		admin.PUT("/products/:id/moderate", controller.ModerateProduct)
+		admin.POST("/exchange-rates/import", controller.ImportExchangeRates)
+		admin.PUT("/exchange-rates/:currency", controller.SetExchangeRate)
+		admin.DELETE("/exchange-rates/:currency", controller.DeleteExchangeRate)
 	}
 }
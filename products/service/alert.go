@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"products/model"
+	"products/notifier"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxSnoozeMinutes = 30 * 24 * 60
+
+func (s *Service) ProductSetReorderThreshold(ctx context.Context, productID int, threshold *int) (*model.Product, error) {
+	if threshold != nil && *threshold < 0 {
+		return nil, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	if err := s.DB.Model(&model.Product{}).Where("id = ?", productID).Update("reorder_threshold", threshold).Error; err != nil {
+		return nil, err
+	}
+
+	return s.ProductGetByID(ctx, productID)
+}
+
+func (s *Service) StockAlertGetBySeller(ctx context.Context, sellerID int, status model.StockAlertStatus) ([]*model.StockAlert, error) {
+	var alerts []*model.StockAlert
+
+	if status == "" {
+		status = model.STOCK_ALERT_STATUS_ACTIVE
+	}
+
+	if err := s.DB.Model(&alerts).Where("seller_id = ? AND status = ?", sellerID, status).Order("id DESC").Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	return alerts, nil
+}
+
+func (s *Service) StockAlertGetByID(ctx context.Context, id int, sellerID int) (*model.StockAlert, error) {
+	var alert *model.StockAlert
+
+	if err := s.DB.Model(&alert).Where("id = ? AND seller_id = ?", id, sellerID).First(&alert).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("stock alert not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+// StockAlertAcknowledge silences an alert until the product is restocked. A
+// later drop below the threshold raises a new alert.
+func (s *Service) StockAlertAcknowledge(ctx context.Context, id int, sellerID int) (*model.StockAlert, error) {
+	alert, err := s.StockAlertGetByID(ctx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Status == string(model.STOCK_ALERT_STATUS_RESOLVED) {
+		return nil, fmt.Errorf("stock alert is already resolved")
+	}
+
+	if err := s.DB.Model(&model.StockAlert{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          model.STOCK_ALERT_STATUS_ACKNOWLEDGED,
+		"acknowledged_at": time.Now(),
+		"snoozed_until":   nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.StockAlertGetByID(ctx, id, sellerID)
+}
+
+// StockAlertSnooze hides an alert for the given number of minutes, after which
+// the scanner raises it again if stock is still low.
+func (s *Service) StockAlertSnooze(ctx context.Context, id int, sellerID int, minutes int) (*model.StockAlert, error) {
+	if minutes <= 0 || minutes > maxSnoozeMinutes {
+		return nil, fmt.Errorf("invalid input: snooze must be between 1 and %d minutes", maxSnoozeMinutes)
+	}
+
+	alert, err := s.StockAlertGetByID(ctx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if alert.Status == string(model.STOCK_ALERT_STATUS_RESOLVED) {
+		return nil, fmt.Errorf("stock alert is already resolved")
+	}
+
+	if err := s.DB.Model(&model.StockAlert{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":        model.STOCK_ALERT_STATUS_SNOOZED,
+		"snoozed_until": time.Now().Add(time.Duration(minutes) * time.Minute),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.StockAlertGetByID(ctx, id, sellerID)
+}
+
+// StartStockAlertScanner checks stock against reorder thresholds on every
+// tick. It blocks, so run it in its own goroutine.
+func StartStockAlertScanner(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ScanStockAlerts(context.Background())
+	}
+}
+
+func ScanStockAlerts(ctx context.Context) {
+	var (
+		s   = GetService()
+		now = time.Now()
+	)
+
+	if err := s.stockAlertResolveRestocked(now); err != nil {
+		log.Println("failed to resolve stock alerts:", err)
+	}
+
+	woken, err := s.stockAlertWakeSnoozed(now)
+	if err != nil {
+		log.Println("failed to wake snoozed stock alerts:", err)
+	}
+
+	raised, err := s.stockAlertRaiseLow()
+	if err != nil {
+		log.Println("failed to raise stock alerts:", err)
+	}
+
+	for _, alert := range append(woken, raised...) {
+		if err := notifier.GetNotifier().Notify(ctx, alert); err != nil {
+			log.Printf("failed to send stock alert %d: %v", alert.ID, err)
+		}
+	}
+}
+
+// stockAlertResolveRestocked closes open alerts for products that are back
+// above their threshold, no longer track one, or were deleted.
+func (s *Service) stockAlertResolveRestocked(now time.Time) error {
+	var ids []int
+
+	if err := s.DB.Table("stock_alert").
+		Joins("JOIN product ON product.id = stock_alert.product_id").
+		Where("stock_alert.status <> ?", model.STOCK_ALERT_STATUS_RESOLVED).
+		Where("product.reorder_threshold IS NULL OR product.stock > product.reorder_threshold OR product.deleted_at IS NOT NULL").
+		Pluck("stock_alert.id", &ids).Error; err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return s.DB.Model(&model.StockAlert{}).Where("id IN (?)", ids).Updates(map[string]interface{}{
+		"status":        model.STOCK_ALERT_STATUS_RESOLVED,
+		"resolved_at":   now,
+		"snoozed_until": nil,
+	}).Error
+}
+
+func (s *Service) stockAlertWakeSnoozed(now time.Time) ([]*model.StockAlert, error) {
+	var alerts []*model.StockAlert
+
+	if err := s.DB.Model(&alerts).Where("status = ? AND snoozed_until <= ?", model.STOCK_ALERT_STATUS_SNOOZED, now).Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+
+	for _, alert := range alerts {
+		var product *model.Product
+		if err := s.DB.Model(&product).Where("id = ?", alert.ProductID).First(&product).Error; err != nil {
+			return nil, err
+		}
+
+		alert.Status = string(model.STOCK_ALERT_STATUS_ACTIVE)
+		alert.Stock = product.Stock
+		alert.SnoozedUntil = nil
+
+		if err := s.DB.Model(&model.StockAlert{}).Where("id = ?", alert.ID).Updates(map[string]interface{}{
+			"status":        alert.Status,
+			"stock":         alert.Stock,
+			"snoozed_until": nil,
+		}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return alerts, nil
+}
+
+// stockAlertRaiseLow opens an alert for every product at or below its
+// threshold that does not already have one open.
+func (s *Service) stockAlertRaiseLow() ([]*model.StockAlert, error) {
+	var (
+		products []*model.Product
+		alerts   []*model.StockAlert
+	)
+
+	if err := s.DB.Model(&products).
+		Where("deleted_at IS NULL AND reorder_threshold IS NOT NULL AND stock <= reorder_threshold").
+		Where("NOT EXISTS (SELECT 1 FROM stock_alert WHERE stock_alert.product_id = product.id AND stock_alert.status <> ?)", model.STOCK_ALERT_STATUS_RESOLVED).
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	for _, product := range products {
+		alert := model.StockAlert{
+			ProductID:   product.ID,
+			SellerID:    product.SellerID,
+			ProductName: product.Name,
+			Stock:       product.Stock,
+			Threshold:   *product.ReorderThreshold,
+			Status:      string(model.STOCK_ALERT_STATUS_ACTIVE),
+		}
+
+		if err := s.DB.Model(&alert).Create(&alert).Error; err != nil {
+			return nil, err
+		}
+
+		alerts = append(alerts, &alert)
+	}
+
+	return alerts, nil
+}
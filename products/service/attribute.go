@@ -0,0 +1,488 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const maxAttributeValueLength = 255
+
+func (s *Service) CategoryAttributeCreate(ctx context.Context, categoryID int, input model.NewCategoryAttribute) (*model.CategoryAttribute, error) {
+	valid, err := s.CategoryAttributeOnCreate(ctx, categoryID, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating category attribute")
+	}
+
+	attribute := model.CategoryAttribute{
+		CategoryID: categoryID,
+		Code:       input.Code,
+		Name:       input.Name,
+		Type:       string(input.Type),
+		Unit:       input.Unit,
+		Required:   input.Required,
+		Filterable: input.Filterable == nil || *input.Filterable,
+		Position:   input.Position,
+	}
+
+	if err := s.DB.Model(&attribute).Create(&attribute).Error; err != nil {
+		return nil, err
+	}
+
+	// gorm skips zero values that have a column default on create
+	if !attribute.Filterable {
+		if err := s.DB.Model(&attribute).Update("filterable", false).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.categoryAttributeSetOptions(ctx, attribute.ID, input.Options); err != nil {
+		return nil, err
+	}
+
+	return s.CategoryAttributeGetByID(ctx, categoryID, attribute.ID)
+}
+
+func (s *Service) CategoryAttributeOnCreate(ctx context.Context, categoryID int, input *model.NewCategoryAttribute) (bool, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return false, fmt.Errorf("invalid input: attribute name cannot be empty")
+	}
+
+	if input.Code == "" {
+		input.Code = input.Name
+	}
+	input.Code = tools.Slugify(input.Code)
+	if input.Code == "" {
+		return false, fmt.Errorf("invalid input: attribute code cannot be empty")
+	}
+
+	input.Unit = strings.TrimSpace(input.Unit)
+
+	switch input.Type {
+	case model.ATTRIBUTE_TYPE_NUMBER:
+	case model.ATTRIBUTE_TYPE_STRING, model.ATTRIBUTE_TYPE_BOOL, model.ATTRIBUTE_TYPE_ENUM:
+		if input.Unit != "" {
+			return false, fmt.Errorf("invalid input: only number attributes can have a unit")
+		}
+	default:
+		return false, fmt.Errorf("invalid input: unsupported attribute type %s", input.Type)
+	}
+
+	options, err := normalizeAttributeOptions(input.Type, input.Options)
+	if err != nil {
+		return false, err
+	}
+	input.Options = options
+
+	category, err := s.CategoryGetByID(ctx, categoryID)
+	if err != nil {
+		return false, err
+	}
+
+	// a product sees the attributes of its categories and all their ancestors,
+	// so a code may only be used once along any branch of the tree
+	var count int64
+	if err := s.DB.Model(&model.CategoryAttribute{}).
+		Joins("JOIN category ON category.id = category_attribute.category_id").
+		Where("category_attribute.code = ? AND category_attribute.deleted_at IS NULL", input.Code).
+		Where("(category.path IN (?) OR category.path LIKE ?)", categoryPathPrefixes(category.Path), escapeLike(category.Path)+"/%").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, fmt.Errorf("attribute code %s is already used by this category, a parent or a child category", input.Code)
+	}
+
+	return true, nil
+}
+
+func (s *Service) CategoryAttributeUpdate(ctx context.Context, categoryID int, id int, input model.UpdateCategoryAttribute) (*model.CategoryAttribute, error) {
+	attribute, err := s.CategoryAttributeGetByID(ctx, categoryID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+
+	if name := strings.TrimSpace(input.Name); name != "" {
+		updates["name"] = name
+	}
+	if input.Unit != nil {
+		unit := strings.TrimSpace(*input.Unit)
+		if unit != "" && attribute.Type != string(model.ATTRIBUTE_TYPE_NUMBER) {
+			return nil, fmt.Errorf("invalid input: only number attributes can have a unit")
+		}
+		updates["unit"] = unit
+	}
+	if input.Required != nil {
+		updates["required"] = *input.Required
+	}
+	if input.Filterable != nil {
+		updates["filterable"] = *input.Filterable
+	}
+	if input.Position != nil {
+		updates["position"] = *input.Position
+	}
+
+	if len(updates) > 0 {
+		if err := s.DB.Model(&model.CategoryAttribute{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Options != nil {
+		options, err := normalizeAttributeOptions(model.AttributeType(attribute.Type), input.Options)
+		if err != nil {
+			return nil, err
+		}
+
+		var count int64
+		if err := s.DB.Model(&model.ProductAttribute{}).Where("attribute_id = ? AND value NOT IN (?)", id, options).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, fmt.Errorf("cannot remove options that are still used by %d products", count)
+		}
+
+		if err := s.DB.Where("attribute_id = ?", id).Delete(&model.CategoryAttributeOption{}).Error; err != nil {
+			return nil, err
+		}
+		if err := s.categoryAttributeSetOptions(ctx, id, options); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.CategoryAttributeGetByID(ctx, categoryID, id)
+}
+
+// CategoryAttributeDelete removes an attribute along with every value products
+// have for it.
+func (s *Service) CategoryAttributeDelete(ctx context.Context, categoryID int, id int) (string, error) {
+	if _, err := s.CategoryAttributeGetByID(ctx, categoryID, id); err != nil {
+		return "failed", err
+	}
+
+	if err := s.DB.Model(&model.CategoryAttribute{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error; err != nil {
+		return "failed", err
+	}
+
+	if err := s.DB.Where("attribute_id = ?", id).Delete(&model.ProductAttribute{}).Error; err != nil {
+		return "failed", err
+	}
+
+	return "success", nil
+}
+
+func (s *Service) CategoryAttributeGetByID(ctx context.Context, categoryID int, id int) (*model.CategoryAttribute, error) {
+	var attribute *model.CategoryAttribute
+
+	if err := s.DB.Model(&attribute).Scopes(tools.IsDeletedAtNull).Where("id = ? AND category_id = ?", id, categoryID).First(&attribute).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("category attribute not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryAttributeLoadOptions(ctx, []*model.CategoryAttribute{attribute}); err != nil {
+		return nil, err
+	}
+
+	return attribute, nil
+}
+
+// CategoryAttributeGetByCategoryID returns the attributes products in the
+// category can have, including those inherited from its parents.
+func (s *Service) CategoryAttributeGetByCategoryID(ctx context.Context, categoryID int) ([]*model.CategoryAttribute, error) {
+	category, err := s.CategoryGetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.categoryAttributeGetByPaths(ctx, categoryPathPrefixes(category.Path))
+}
+
+// ProductGetApplicableAttributes returns the attributes of every category the
+// product is assigned to and of their parents.
+func (s *Service) ProductGetApplicableAttributes(ctx context.Context, productID int) ([]*model.CategoryAttribute, error) {
+	categories, err := s.ProductGetCategories(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, category := range categories {
+		paths = append(paths, categoryPathPrefixes(category.Path)...)
+	}
+
+	if len(paths) == 0 {
+		return []*model.CategoryAttribute{}, nil
+	}
+
+	return s.categoryAttributeGetByPaths(ctx, paths)
+}
+
+// ProductSetAttributes replaces the attribute values of a product. Every
+// required attribute must be given, and every value must match the type of
+// its attribute.
+func (s *Service) ProductSetAttributes(ctx context.Context, productID int, input model.SetProductAttributes) ([]*model.ProductAttributeValue, error) {
+	attributes, err := s.ProductGetApplicableAttributes(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := make(map[string]*model.CategoryAttribute, len(attributes))
+	// unrelated categories of the same product may share a code; the value is
+	// stored against the first of them
+	for _, attribute := range attributes {
+		if _, ok := byCode[attribute.Code]; !ok {
+			byCode[attribute.Code] = attribute
+		}
+	}
+
+	var values []*model.ProductAttribute
+	for code, raw := range input.Attributes {
+		attribute, ok := byCode[code]
+		if !ok {
+			return nil, fmt.Errorf("attribute %s does not apply to this product", code)
+		}
+		if raw == nil {
+			continue
+		}
+
+		value, number, err := normalizeAttributeValue(attribute, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, &model.ProductAttribute{
+			ProductID:   productID,
+			AttributeID: attribute.ID,
+			Value:       value,
+			NumberValue: number,
+		})
+	}
+
+	for _, attribute := range attributes {
+		if attribute.Required && input.Attributes[attribute.Code] == nil {
+			return nil, fmt.Errorf("attribute %s is required", attribute.Code)
+		}
+	}
+
+	if err := s.DB.Where("product_id = ?", productID).Delete(&model.ProductAttribute{}).Error; err != nil {
+		return nil, err
+	}
+
+	if len(values) > 0 {
+		if err := s.DB.Create(&values).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.ProductGetAttributes(ctx, productID)
+}
+
+func (s *Service) ProductGetAttributes(ctx context.Context, productID int) ([]*model.ProductAttributeValue, error) {
+	values := []*model.ProductAttributeValue{}
+
+	if err := s.DB.Model(&model.ProductAttribute{}).
+		Select("category_attribute.id AS attribute_id, category_attribute.code, category_attribute.name, category_attribute.type, category_attribute.unit, product_attribute.value").
+		Joins("JOIN category_attribute ON category_attribute.id = product_attribute.attribute_id").
+		Where("product_attribute.product_id = ? AND category_attribute.deleted_at IS NULL", productID).
+		Order("category_attribute.position ASC").Order("category_attribute.id ASC").
+		Scan(&values).Error; err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// productAttributePrune drops the values of attributes that no longer apply
+// to a product after its categories change.
+func (s *Service) productAttributePrune(ctx context.Context, productID int) error {
+	attributes, err := s.ProductGetApplicableAttributes(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	query := s.DB.Where("product_id = ?", productID)
+	if len(attributes) > 0 {
+		ids := make([]int, 0, len(attributes))
+		for _, attribute := range attributes {
+			ids = append(ids, attribute.ID)
+		}
+		query = query.Where("attribute_id NOT IN (?)", ids)
+	}
+
+	return query.Delete(&model.ProductAttribute{}).Error
+}
+
+func (s *Service) categoryAttributeGetByPaths(ctx context.Context, paths []string) ([]*model.CategoryAttribute, error) {
+	var attributes []*model.CategoryAttribute
+
+	if err := s.DB.Model(&attributes).
+		Select("category_attribute.*").
+		Joins("JOIN category ON category.id = category_attribute.category_id").
+		Where("category.path IN (?) AND category.deleted_at IS NULL AND category_attribute.deleted_at IS NULL", paths).
+		Order("LENGTH(category.path) ASC").Order("category_attribute.position ASC").Order("category_attribute.id ASC").
+		Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.categoryAttributeLoadOptions(ctx, attributes); err != nil {
+		return nil, err
+	}
+
+	return attributes, nil
+}
+
+func (s *Service) categoryAttributeLoadOptions(ctx context.Context, attributes []*model.CategoryAttribute) error {
+	var options []*model.CategoryAttributeOption
+
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	attributeIDs := make([]int, 0, len(attributes))
+	attributeByID := make(map[int]*model.CategoryAttribute, len(attributes))
+	for _, attribute := range attributes {
+		attributeIDs = append(attributeIDs, attribute.ID)
+		attributeByID[attribute.ID] = attribute
+		attribute.Options = []string{}
+	}
+
+	if err := s.DB.Model(&options).Where("attribute_id IN (?)", attributeIDs).Order("position ASC").Order("id ASC").Find(&options).Error; err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		attributeByID[option.AttributeID].Options = append(attributeByID[option.AttributeID].Options, option.Value)
+	}
+
+	return nil
+}
+
+func (s *Service) categoryAttributeSetOptions(ctx context.Context, attributeID int, options []string) error {
+	for i, value := range options {
+		option := model.CategoryAttributeOption{
+			AttributeID: attributeID,
+			Value:       value,
+			Position:    i,
+		}
+
+		if err := s.DB.Model(&option).Create(&option).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func normalizeAttributeOptions(attributeType model.AttributeType, options []string) ([]string, error) {
+	if attributeType != model.ATTRIBUTE_TYPE_ENUM {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("invalid input: only enum attributes can have options")
+		}
+		return nil, nil
+	}
+
+	var result []string
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, fmt.Errorf("invalid input: option cannot be empty")
+		}
+		if slices.ContainsFunc(result, func(existing string) bool { return strings.EqualFold(existing, option) }) {
+			return nil, fmt.Errorf("invalid input: duplicate option %s", option)
+		}
+		result = append(result, option)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("invalid input: enum attributes need at least one option")
+	}
+
+	return result, nil
+}
+
+// normalizeAttributeValue checks a value against the attribute type and
+// returns its canonical text, plus the number for number attributes.
+func normalizeAttributeValue(attribute *model.CategoryAttribute, raw interface{}) (string, *float64, error) {
+	switch model.AttributeType(attribute.Type) {
+	case model.ATTRIBUTE_TYPE_NUMBER:
+		var number float64
+		switch value := raw.(type) {
+		case float64:
+			number = value
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return "", nil, fmt.Errorf("attribute %s must be a number", attribute.Code)
+			}
+			number = parsed
+		default:
+			return "", nil, fmt.Errorf("attribute %s must be a number", attribute.Code)
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), &number, nil
+
+	case model.ATTRIBUTE_TYPE_BOOL:
+		switch value := raw.(type) {
+		case bool:
+			return strconv.FormatBool(value), nil, nil
+		case string:
+			parsed, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return "", nil, fmt.Errorf("attribute %s must be true or false", attribute.Code)
+			}
+			return strconv.FormatBool(parsed), nil, nil
+		default:
+			return "", nil, fmt.Errorf("attribute %s must be true or false", attribute.Code)
+		}
+
+	case model.ATTRIBUTE_TYPE_ENUM:
+		value, ok := raw.(string)
+		if ok {
+			for _, option := range attribute.Options {
+				if strings.EqualFold(option, strings.TrimSpace(value)) {
+					return option, nil, nil
+				}
+			}
+		}
+		return "", nil, fmt.Errorf("attribute %s must be one of %s", attribute.Code, strings.Join(attribute.Options, ", "))
+
+	default:
+		value, ok := raw.(string)
+		if !ok {
+			return "", nil, fmt.Errorf("attribute %s must be text", attribute.Code)
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || len(value) > maxAttributeValueLength {
+			return "", nil, fmt.Errorf("attribute %s must be between 1 and %d characters", attribute.Code, maxAttributeValueLength)
+		}
+		return value, nil, nil
+	}
+}
+
+// categoryPathPrefixes returns the path of a category and of each of its
+// parents.
+func categoryPathPrefixes(path string) []string {
+	var (
+		segments = strings.Split(path, "/")
+		prefixes = make([]string, 0, len(segments))
+	)
+
+	for i := range segments {
+		prefixes = append(prefixes, strings.Join(segments[:i+1], "/"))
+	}
+
+	return prefixes
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+)
+
+// BundleSetComponents replaces the components of a bundle. Components must be
+// products of the same seller that are not bundles themselves, and must name
+// a variant when the product has variants.
+func (s *Service) BundleSetComponents(ctx context.Context, bundleID int, input []model.NewBundleComponent) ([]*model.BundleComponent, error) {
+	bundle, err := s.ProductGetByID(ctx, bundleID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Type != string(model.PRODUCT_TYPE_BUNDLE) {
+		return nil, fmt.Errorf("product is not a bundle")
+	}
+
+	if len(input) == 0 {
+		return nil, fmt.Errorf("invalid input: a bundle needs at least one component")
+	}
+
+	var (
+		components []*model.BundleComponent
+		seen       = make(map[string]bool, len(input))
+	)
+
+	for _, item := range input {
+		if item.ProductID <= 0 || item.VariantID < 0 || item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid input: invalid component for product %d", item.ProductID)
+		}
+		if item.ProductID == bundleID {
+			return nil, fmt.Errorf("invalid input: a bundle cannot contain itself")
+		}
+
+		key := fmt.Sprintf("%d:%d", item.ProductID, item.VariantID)
+		if seen[key] {
+			return nil, fmt.Errorf("invalid input: product %d is listed more than once", item.ProductID)
+		}
+		seen[key] = true
+
+		component, err := s.ProductGetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("component %d: %w", item.ProductID, err)
+		}
+		if component.SellerID != bundle.SellerID {
+			return nil, fmt.Errorf("component %d does not belong to seller", item.ProductID)
+		}
+		if component.Type == string(model.PRODUCT_TYPE_BUNDLE) {
+			return nil, fmt.Errorf("component %d is a bundle; bundles cannot be nested", item.ProductID)
+		}
+
+		hasVariants, err := s.productResolveVariant(ctx, item.ProductID, item.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("component %d: %w", item.ProductID, err)
+		}
+		if hasVariants {
+			if _, err := s.ProductVariantGetByID(ctx, item.ProductID, item.VariantID); err != nil {
+				return nil, fmt.Errorf("component %d: %w", item.ProductID, err)
+			}
+		} else if item.VariantID > 0 {
+			return nil, fmt.Errorf("component %d has no variants", item.ProductID)
+		}
+
+		components = append(components, &model.BundleComponent{
+			BundleID:    bundleID,
+			ComponentID: item.ProductID,
+			VariantID:   item.VariantID,
+			Quantity:    item.Quantity,
+		})
+	}
+
+	if err := s.DB.Where("bundle_id = ?", bundleID).Delete(&model.BundleComponent{}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Create(&components).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.bundleRefreshStock(ctx, bundleID); err != nil {
+		return nil, err
+	}
+
+	return s.BundleComponentGetByBundleID(ctx, bundleID)
+}
+
+// BundleComponentGetByBundleID returns the components of a bundle with their
+// names and skus. Deleted components are still listed so past orders can be
+// broken down.
+func (s *Service) BundleComponentGetByBundleID(ctx context.Context, bundleID int) ([]*model.BundleComponent, error) {
+	components := []*model.BundleComponent{}
+
+	if err := s.DB.Model(&components).Where("bundle_id = ?", bundleID).Order("id ASC").Find(&components).Error; err != nil {
+		return nil, err
+	}
+
+	for _, component := range components {
+		product, err := s.ProductGetByIDWithDeleted(ctx, component.ComponentID)
+		if err != nil {
+			return nil, err
+		}
+
+		component.Name = product.Name
+		if product.SKU != nil {
+			component.SKU = *product.SKU
+		}
+
+		if component.VariantID > 0 {
+			var sku string
+			if err := s.DB.Model(&model.ProductVariant{}).Where("id = ?", component.VariantID).Select("sku").Scan(&sku).Error; err != nil {
+				return nil, err
+			}
+			component.SKU = sku
+		}
+	}
+
+	return components, nil
+}
+
+func (s *Service) productIsBundle(productID int) (bool, error) {
+	var productType string
+
+	if err := s.DB.Model(&model.Product{}).Where("id = ?", productID).Select("type").Scan(&productType).Error; err != nil {
+		return false, err
+	}
+
+	return productType == string(model.PRODUCT_TYPE_BUNDLE), nil
+}
+
+// bundleDecrementStock takes the stock of every component for the bundles
+// sold. It relies on the surrounding transaction to undo earlier components
+// when a later one runs out.
+func (s *Service) bundleDecrementStock(ctx context.Context, change model.StockChange) ([]*model.StockAllocation, error) {
+	components, err := s.bundleComponentChanges(change)
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []*model.StockAllocation
+	for _, componentChange := range components {
+		componentAllocations, err := s.productDecrementStock(ctx, componentChange)
+		if err != nil {
+			return nil, fmt.Errorf("component %d of bundle %d: %w", componentChange.ProductID, change.ProductID, err)
+		}
+		allocations = append(allocations, componentAllocations...)
+	}
+
+	return allocations, nil
+}
+
+func (s *Service) bundleIncrementStock(ctx context.Context, change model.StockChange) error {
+	components, err := s.bundleComponentChanges(change)
+	if err != nil {
+		return err
+	}
+
+	for _, componentChange := range components {
+		if err := s.productIncrementStock(ctx, componentChange); err != nil {
+			return fmt.Errorf("component %d of bundle %d: %w", componentChange.ProductID, change.ProductID, err)
+		}
+	}
+
+	return nil
+}
+
+// bundleComponentChanges splits a stock change on a bundle into one change per
+// component, scaled by the component quantity.
+func (s *Service) bundleComponentChanges(change model.StockChange) ([]model.StockChange, error) {
+	var components []*model.BundleComponent
+
+	if change.VariantID > 0 || change.WarehouseID > 0 {
+		return nil, fmt.Errorf("bundle stock is derived from its components")
+	}
+
+	if err := s.DB.Model(&components).Where("bundle_id = ?", change.ProductID).Order("id ASC").Find(&components).Error; err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return nil, fmt.Errorf("bundle %d has no components", change.ProductID)
+	}
+
+	changes := make([]model.StockChange, 0, len(components))
+	for _, component := range components {
+		componentChange := change
+		componentChange.ProductID = component.ComponentID
+		componentChange.VariantID = component.VariantID
+		componentChange.Quantity = change.Quantity * component.Quantity
+		componentChange.Note = fmt.Sprintf("bundle %d", change.ProductID)
+		if change.Note != "" {
+			componentChange.Note += ": " + change.Note
+		}
+		changes = append(changes, componentChange)
+	}
+
+	return changes, nil
+}
+
+// bundleSyncStock refreshes the stock of every bundle that contains the
+// product, after the product's own stock or status changed.
+func (s *Service) bundleSyncStock(ctx context.Context, componentID int) error {
+	var bundleIDs []int
+
+	if err := s.DB.Model(&model.BundleComponent{}).Where("component_id = ?", componentID).Distinct().Pluck("bundle_id", &bundleIDs).Error; err != nil {
+		return err
+	}
+
+	for _, bundleID := range bundleIDs {
+		if err := s.bundleRefreshStock(ctx, bundleID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bundleRefreshStock stores how many complete bundles the component stock can
+// make, so catalog listings can keep filtering on the stock column. A
+// component that is deleted or not on sale makes the bundle unavailable.
+func (s *Service) bundleRefreshStock(ctx context.Context, bundleID int) error {
+	var (
+		components []*model.BundleComponent
+		available  int
+	)
+
+	if err := s.DB.Model(&components).Where("bundle_id = ?", bundleID).Find(&components).Error; err != nil {
+		return err
+	}
+
+	for i, component := range components {
+		var onSale int64
+		if err := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).
+			Where("id = ? AND status = ?", component.ComponentID, model.PRODUCT_STATUS_ACTIVE).
+			Count(&onSale).Error; err != nil {
+			return err
+		}
+
+		stock := 0
+		if onSale > 0 {
+			current, err := s.productCurrentStock(component.ComponentID, component.VariantID)
+			if err != nil {
+				return err
+			}
+			stock = current
+		}
+
+		if count := stock / component.Quantity; i == 0 || count < available {
+			available = count
+		}
+	}
+
+	return s.DB.Model(&model.Product{}).Where("id = ?", bundleID).Update("stock", available).Error
+}
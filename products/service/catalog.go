@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"products/model"
+	"products/tools"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxImportRows   = 5000
+	exportBatchSize = 200
+)
+
+// CatalogParseCSV reads a catalog file into rows. Only the header is checked
+// here; each row is validated when the import job reaches it so a bad line
+// does not stop the rest of the file.
+func CatalogParseCSV(r io.Reader) ([]model.CatalogRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	// a line with the wrong number of columns fails on its own rather than
+	// stopping the file
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csv file is empty")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	if len(header) != len(model.CatalogCSVHeader) {
+		return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(model.CatalogCSVHeader, ","))
+	}
+	for i, column := range model.CatalogCSVHeader {
+		if !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")), column) {
+			return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(model.CatalogCSVHeader, ","))
+		}
+	}
+
+	var rows []model.CatalogRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+
+		if len(rows) >= maxImportRows {
+			return nil, fmt.Errorf("csv file exceeds the limit of %d rows", maxImportRows)
+		}
+
+		if len(record) != len(model.CatalogCSVHeader) {
+			row := model.CatalogRow{
+				Line:  line,
+				Error: fmt.Sprintf("invalid input: expected %d columns, got %d", len(model.CatalogCSVHeader), len(record)),
+			}
+			if len(record) > 4 {
+				row.SKU = strings.TrimSpace(record[4])
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		rows = append(rows, model.CatalogRow{
+			Line:        line,
+			Name:        strings.TrimSpace(record[0]),
+			Description: strings.TrimSpace(record[1]),
+			Price:       strings.TrimSpace(record[2]),
+			Stock:       strings.TrimSpace(record[3]),
+			SKU:         strings.TrimSpace(record[4]),
+			Category:    strings.TrimSpace(record[5]),
+		})
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("csv file has no rows")
+	}
+
+	return rows, nil
+}
+
+func (s *Service) ImportJobCreate(ctx context.Context, sellerID int, fileName string, totalRows int) (*model.ImportJob, error) {
+	job := model.ImportJob{
+		SellerID:  sellerID,
+		FileName:  fileName,
+		Status:    string(model.IMPORT_JOB_STATUS_PENDING),
+		TotalRows: totalRows,
+	}
+
+	if err := s.DB.Model(&job).Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (s *Service) ImportJobGetByID(ctx context.Context, id int, sellerID int) (*model.ImportJob, error) {
+	var job *model.ImportJob
+
+	if err := s.DB.Model(&job).Where("id = ? AND seller_id = ?", id, sellerID).First(&job).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("import job not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.ImportJobError{}).Where("job_id = ?", id).Order("line ASC").Find(&job.Errors).Error; err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// RunCatalogImport works through the rows of an import job, committing each
+// row on its own so one invalid line only fails that line. Progress is written
+// back to the job after every row. It blocks, so run it in its own goroutine.
+func RunCatalogImport(ctx context.Context, jobID int, sellerID int, rows []model.CatalogRow) {
+	s := GetService()
+
+	if err := s.importJobUpdate(jobID, map[string]interface{}{"status": model.IMPORT_JOB_STATUS_RUNNING}); err != nil {
+		log.Printf("failed to start import job %d: %v", jobID, err)
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("import job %d panicked: %v", jobID, r)
+			s.importJobFinish(jobID, model.IMPORT_JOB_STATUS_FAILED, fmt.Sprint(r))
+		}
+	}()
+
+	for _, row := range rows {
+		updates := map[string]interface{}{"processed_rows": gorm.Expr("processed_rows + 1")}
+
+		created, err := catalogImportRow(ctx, sellerID, row)
+		switch {
+		case err != nil:
+			updates["failed_rows"] = gorm.Expr("failed_rows + 1")
+			rowError := model.ImportJobError{
+				JobID:   jobID,
+				Line:    row.Line,
+				SKU:     row.SKU,
+				Message: err.Error(),
+			}
+			if err := s.DB.Model(&rowError).Create(&rowError).Error; err != nil {
+				log.Printf("failed to record error for import job %d: %v", jobID, err)
+			}
+		case created:
+			updates["created_rows"] = gorm.Expr("created_rows + 1")
+		default:
+			updates["updated_rows"] = gorm.Expr("updated_rows + 1")
+		}
+
+		if err := s.importJobUpdate(jobID, updates); err != nil {
+			log.Printf("failed to update import job %d: %v", jobID, err)
+		}
+	}
+
+	s.importJobFinish(jobID, model.IMPORT_JOB_STATUS_COMPLETED, "")
+}
+
+func catalogImportRow(ctx context.Context, sellerID int, row model.CatalogRow) (created bool, err error) {
+	if row.Error != "" {
+		return false, fmt.Errorf("%s", row.Error)
+	}
+
+	tx := GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.DB.Rollback()
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	created, err = tx.CatalogUpsertRow(ctx, sellerID, row)
+	if err != nil {
+		tx.DB.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return created, nil
+}
+
+// CatalogUpsertRow validates a row with the same rules as a single product
+// create, then updates the seller's product with that sku or creates a new one.
+// It reports whether a product was created.
+func (s *Service) CatalogUpsertRow(ctx context.Context, sellerID int, row model.CatalogRow) (bool, error) {
+	price, err := strconv.ParseFloat(row.Price, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid input: price must be a number")
+	}
+
+	stock, err := strconv.Atoi(row.Stock)
+	if err != nil {
+		return false, fmt.Errorf("invalid input: stock must be a whole number")
+	}
+
+	newProd := model.NewProduct{
+		Name:        row.Name,
+		Description: row.Description,
+		Price:       price,
+		Stock:       stock,
+		SKU:         row.SKU,
+		Submit:      true,
+		SellerID:    sellerID,
+	}
+
+	if _, err := s.ProductOnCreate(ctx, newProd); err != nil {
+		return false, err
+	}
+
+	if row.Category != "" {
+		category, err := s.CategoryGetBySlug(ctx, row.Category)
+		if err != nil {
+			return false, err
+		}
+		newProd.CategoryIDs = []int{category.ID}
+	}
+
+	var existing *model.Product
+	if row.SKU != "" {
+		if existing, err = s.ProductGetBySKU(ctx, row.SKU); err != nil {
+			return false, err
+		}
+	}
+
+	if existing == nil {
+		if _, err := s.ProductCreate(ctx, newProd); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if existing.SellerID != sellerID {
+		return false, fmt.Errorf("sku %s belongs to another seller", row.SKU)
+	}
+
+	if existing.Stock != stock {
+		hasVariants, err := s.ProductHasVariants(ctx, existing.ID)
+		if err != nil {
+			return false, err
+		}
+		if hasVariants {
+			return false, fmt.Errorf("stock is tracked on the product's variants")
+		}
+	}
+
+	referencePrice, err := s.productReferencePrice(newProd.Price, existing.Currency)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.DB.Model(&model.Product{}).Where("id = ?", existing.ID).Updates(map[string]interface{}{
+		"name":            newProd.Name,
+		"description":     newProd.Description,
+		"price":           newProd.Price,
+		"reference_price": referencePrice,
+		"version":         gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return false, err
+	}
+
+	if existing.Price != newProd.Price {
+		if err := s.productRecordPriceChange(ctx, model.PriceHistory{
+			ProductID:     existing.ID,
+			ListPrice:     newProd.Price,
+			EffectiveFrom: time.Now(),
+			Reason:        string(model.PRICE_CHANGE_REASON_MANUAL),
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.productSetStock(ctx, model.StockChange{
+		ProductID: existing.ID,
+		Reason:    model.MOVEMENT_REASON_MANUAL_ADJUSTMENT,
+		Note:      "catalog import",
+	}, stock); err != nil {
+		return false, err
+	}
+
+	if len(newProd.CategoryIDs) > 0 {
+		if _, err := s.ProductAssignCategories(ctx, existing.ID, newProd.CategoryIDs); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.productResubmitEdited(ctx, existing, newProd.Name, newProd.Description, newProd.Price); err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// CatalogExport writes the seller's live products to w in the import format,
+// loading them in batches so large catalogs are never held in memory at once.
+func (s *Service) CatalogExport(ctx context.Context, sellerID int, w io.Writer) error {
+	var (
+		writer   = csv.NewWriter(w)
+		products []*model.Product
+	)
+
+	if err := writer.Write(model.CatalogCSVHeader); err != nil {
+		return err
+	}
+
+	result := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).Where("seller_id = ?", sellerID).
+		FindInBatches(&products, exportBatchSize, func(batch *gorm.DB, _ int) error {
+			categories, err := s.catalogFirstCategorySlugs(products)
+			if err != nil {
+				return err
+			}
+
+			for _, product := range products {
+				var sku string
+				if product.SKU != nil {
+					sku = *product.SKU
+				}
+
+				if err := writer.Write([]string{
+					product.Name,
+					product.Description,
+					strconv.FormatFloat(product.Price, 'f', 2, 64),
+					strconv.Itoa(product.Stock),
+					sku,
+					categories[product.ID],
+				}); err != nil {
+					return err
+				}
+			}
+
+			writer.Flush()
+			return writer.Error()
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *Service) catalogFirstCategorySlugs(products []*model.Product) (map[int]string, error) {
+	var rows []struct {
+		ProductID int
+		Slug      string
+	}
+
+	slugs := make(map[int]string, len(products))
+	if len(products) == 0 {
+		return slugs, nil
+	}
+
+	productIDs := make([]int, 0, len(products))
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+	}
+
+	if err := s.DB.Table("product_category").
+		Select("product_category.product_id, category.slug").
+		Joins("JOIN category ON category.id = product_category.category_id").
+		Where("product_category.product_id IN (?) AND category.deleted_at IS NULL", productIDs).
+		Order("product_category.id ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if _, ok := slugs[row.ProductID]; !ok {
+			slugs[row.ProductID] = row.Slug
+		}
+	}
+
+	return slugs, nil
+}
+
+func (s *Service) importJobUpdate(id int, updates map[string]interface{}) error {
+	return s.DB.Model(&model.ImportJob{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (s *Service) importJobFinish(id int, status model.ImportJobStatus, message string) {
+	now := time.Now()
+
+	if err := s.importJobUpdate(id, map[string]interface{}{
+		"status":      status,
+		"message":     message,
+		"finished_at": &now,
+	}); err != nil {
+		log.Printf("failed to finish import job %d: %v", id, err)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCatalogParseCSVFieldCount(t *testing.T) {
+	file := strings.Join([]string{
+		"name,description,price,stock,sku,category",
+		"Mug,Blue mug,9.99,10,MUG-1,kitchen",
+		"Plate,White plate,4.50,20",
+		"Bowl,Deep bowl,6.00,5,BOWL-1,kitchen,extra",
+		"Cup,Small cup,3.00,8,CUP-1,",
+	}, "\n")
+
+	rows, err := CatalogParseCSV(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("CatalogParseCSV() error = %v", err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("got %d rows, want 4", len(rows))
+	}
+
+	tests := []struct {
+		line    int
+		sku     string
+		wantErr bool
+	}{
+		{2, "MUG-1", false},
+		{3, "", true},
+		{4, "BOWL-1", true},
+		{5, "CUP-1", false},
+	}
+
+	for i, tt := range tests {
+		row := rows[i]
+		if row.Line != tt.line || row.SKU != tt.sku || (row.Error != "") != tt.wantErr {
+			t.Errorf("row %d = line %d sku %q error %q, want line %d sku %q error %v", i, row.Line, row.SKU, row.Error, tt.line, tt.sku, tt.wantErr)
+		}
+	}
+}
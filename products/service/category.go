@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) CategoryCreate(ctx context.Context, newCategory model.NewCategory) (*model.Category, error) {
+	valid, err := s.CategoryOnCreate(ctx, &newCategory)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating category")
+	}
+
+	path := newCategory.Slug
+	if newCategory.ParentID != nil {
+		parent, err := s.CategoryGetByID(ctx, *newCategory.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent category not found")
+		}
+		path = parent.Path + "/" + newCategory.Slug
+	}
+
+	category := model.Category{
+		ParentID: newCategory.ParentID,
+		Name:     newCategory.Name,
+		Slug:     newCategory.Slug,
+		Path:     path,
+	}
+
+	if err := s.DB.Model(&category).Create(&category).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (s *Service) CategoryOnCreate(ctx context.Context, newCategory *model.NewCategory) (bool, error) {
+	newCategory.Name = strings.TrimSpace(newCategory.Name)
+	if newCategory.Name == "" {
+		return false, fmt.Errorf("invalid input: category name cannot be empty")
+	}
+
+	if newCategory.Slug == "" {
+		newCategory.Slug = newCategory.Name
+	}
+	newCategory.Slug = tools.Slugify(newCategory.Slug)
+	if newCategory.Slug == "" {
+		return false, fmt.Errorf("invalid input: category slug cannot be empty")
+	}
+
+	exists, err := s.CategoryCheckSlugExists(ctx, newCategory.Slug, 0)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, fmt.Errorf("category slug already exists")
+	}
+
+	return true, nil
+}
+
+func (s *Service) CategoryUpdate(ctx context.Context, input model.UpdateCategory) (*model.Category, error) {
+	category, err := s.CategoryGetByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if name := strings.TrimSpace(input.Name); name != "" {
+		category.Name = name
+	}
+
+	if input.Slug != "" {
+		slug := tools.Slugify(input.Slug)
+		exists, err := s.CategoryCheckSlugExists(ctx, slug, category.ID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("category slug already exists")
+		}
+		category.Slug = slug
+	}
+
+	if input.MoveToRoot && input.ParentID != nil {
+		return nil, fmt.Errorf("invalid input: parent_id cannot be set with move_to_root")
+	}
+
+	// the category stays where it is unless it is explicitly moved
+	parentPath := ""
+	if i := strings.LastIndex(category.Path, "/"); i >= 0 {
+		parentPath = category.Path[:i+1]
+	}
+
+	switch {
+	case input.MoveToRoot:
+		parentPath = ""
+		category.ParentID = nil
+	case input.ParentID != nil:
+		if *input.ParentID == category.ID {
+			return nil, fmt.Errorf("category cannot be its own parent")
+		}
+
+		parent, err := s.CategoryGetByID(ctx, *input.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("parent category not found")
+		}
+
+		if parent.Path == category.Path || strings.HasPrefix(parent.Path, category.Path+"/") {
+			return nil, fmt.Errorf("category cannot be moved under its own descendant")
+		}
+
+		parentPath = parent.Path + "/"
+		category.ParentID = input.ParentID
+	}
+
+	oldPath := category.Path
+	category.Path = parentPath + category.Slug
+
+	if err := s.DB.Model(&model.Category{}).Where("id = ?", category.ID).Updates(map[string]interface{}{
+		"name":      category.Name,
+		"slug":      category.Slug,
+		"parent_id": category.ParentID,
+		"path":      category.Path,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	// rewrite the path prefix of every descendant when the category moves or is renamed
+	if oldPath != category.Path {
+		if err := s.DB.Model(&model.Category{}).Where("path LIKE ?", escapeLike(oldPath)+"/%").
+			Update("path", gorm.Expr("CONCAT(?, SUBSTRING(path, ?))", category.Path, len(oldPath)+1)).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.CategoryGetByID(ctx, category.ID)
+}
+
+func (s *Service) CategoryDelete(ctx context.Context, id int) (string, error) {
+	var (
+		count   int64
+		timeNow = time.Now()
+	)
+
+	if id <= 0 {
+		return "failed", fmt.Errorf("id cannot be empty or negative")
+	}
+
+	if _, err := s.CategoryGetByID(ctx, id); err != nil {
+		return "failed", err
+	}
+
+	if err := s.DB.Model(&model.Category{}).Scopes(tools.IsDeletedAtNull).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return "failed", err
+	}
+	if count > 0 {
+		return "failed", fmt.Errorf("category still has child categories")
+	}
+
+	if err := s.DB.Model(&model.Category{}).Where("id = ?", id).Update("deleted_at", timeNow).Error; err != nil {
+		return "failed", err
+	}
+
+	if err := s.DB.Where("category_id = ?", id).Delete(&model.ProductCategory{}).Error; err != nil {
+		return "failed", err
+	}
+
+	return "success", nil
+}
+
+var ErrCategoryNotFound = fmt.Errorf("category not found")
+
+func (s *Service) CategoryGetByID(ctx context.Context, id int) (*model.Category, error) {
+	var category *model.Category
+
+	if err := s.DB.Model(&category).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).First(&category).Error; err == gorm.ErrRecordNotFound {
+		return nil, ErrCategoryNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *Service) CategoryGetBySlug(ctx context.Context, slug string) (*model.Category, error) {
+	var category *model.Category
+
+	if err := s.DB.Model(&category).Scopes(tools.IsDeletedAtNull).Where("slug = ?", slug).First(&category).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("category %s not found", slug)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+func (s *Service) CategoryGetAll(ctx context.Context) ([]*model.Category, error) {
+	var categories []*model.Category
+
+	if err := s.DB.Model(&categories).Scopes(tools.IsDeletedAtNull).Order("path ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (s *Service) CategoryCheckSlugExists(ctx context.Context, slug string, excludeID int) (bool, error) {
+	var count int64
+
+	if err := s.DB.Model(&model.Category{}).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CategoryGetDescendantIDs returns the id of the category together with the
+// ids of every category below it in the tree.
+func (s *Service) CategoryGetDescendantIDs(ctx context.Context, id int) ([]int, error) {
+	var ids []int
+
+	category, err := s.CategoryGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.Category{}).Scopes(tools.IsDeletedAtNull).
+		Where("path = ? OR path LIKE ?", category.Path, escapeLike(category.Path)+"/%").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (s *Service) ProductAssignCategories(ctx context.Context, productID int, categoryIDs []int) (bool, error) {
+	var count int64
+
+	if productID <= 0 {
+		return false, fmt.Errorf("product id cannot be empty")
+	}
+
+	if len(categoryIDs) > 0 {
+		if err := s.DB.Model(&model.Category{}).Scopes(tools.IsDeletedAtNull).Where("id IN (?)", categoryIDs).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if int(count) != len(uniqueInts(categoryIDs)) {
+			return false, fmt.Errorf("one or more categories do not exist")
+		}
+	}
+
+	if err := s.DB.Where("product_id = ?", productID).Delete(&model.ProductCategory{}).Error; err != nil {
+		return false, err
+	}
+
+	for _, categoryID := range uniqueInts(categoryIDs) {
+		productCategory := model.ProductCategory{
+			ProductID:  productID,
+			CategoryID: categoryID,
+		}
+
+		if err := s.DB.Model(&productCategory).Create(&productCategory).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.productAttributePrune(ctx, productID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) ProductGetCategories(ctx context.Context, productID int) ([]*model.Category, error) {
+	var categories []*model.Category
+
+	if err := s.DB.Model(&categories).
+		Joins("JOIN product_category ON product_category.category_id = category.id").
+		Where("product_category.product_id = ? AND category.deleted_at IS NULL", productID).
+		Order("product_category.id ASC").
+		Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// ProductGetCategoryPath returns the path of the first category assigned to
+// the product, or an empty string when the product is uncategorised.
+func (s *Service) ProductGetCategoryPath(ctx context.Context, productID int) (string, error) {
+	categories, err := s.ProductGetCategories(ctx, productID)
+	if err != nil {
+		return "", err
+	}
+
+	if len(categories) == 0 {
+		return "", nil
+	}
+
+	return categories[0].Path, nil
+}
+
+func uniqueInts(values []int) []int {
+	var (
+		seen   = make(map[int]bool, len(values))
+		result []int
+	)
+
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
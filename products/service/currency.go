@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"products/model"
+	"products/tools"
+	"strconv"
+	"strings"
+	"time"
+	"utils/currency"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+)
+
+// ExchangeRateGetAll returns every rate against the reference currency.
+func (s *Service) ExchangeRateGetAll(ctx context.Context) (*model.ExchangeRateTable, error) {
+	rates := []*model.ExchangeRate{}
+
+	if err := s.DB.Model(&rates).Order("currency ASC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+
+	return &model.ExchangeRateTable{Reference: currency.Default(), Rates: rates}, nil
+}
+
+// ExchangeRateCurrencies lists the currencies products can be priced in: the
+// reference currency and every currency with a rate.
+func (s *Service) ExchangeRateCurrencies(ctx context.Context) ([]string, error) {
+	table, err := s.ExchangeRateGetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := []string{table.Reference}
+	for _, rate := range table.Rates {
+		codes = append(codes, rate.Currency)
+	}
+
+	return codes, nil
+}
+
+// ExchangeRateSet adds or replaces the rate of a currency and reprices the
+// products listed in it for search.
+func (s *Service) ExchangeRateSet(ctx context.Context, code string, rate float64, source model.ExchangeRateSource) (*model.ExchangeRate, error) {
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+	if code == currency.Default() {
+		return nil, fmt.Errorf("%s is the reference currency; its rate is always 1", code)
+	}
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return nil, fmt.Errorf("invalid input: rate must be greater than 0")
+	}
+
+	exchangeRate := model.ExchangeRate{
+		Currency:  code,
+		Rate:      rate,
+		Source:    string(source),
+		UpdatedAt: time.Now(),
+	}
+	if user := middleware.AuthContext(ctx); user != nil && user.ID > 0 {
+		exchangeRate.UpdatedBy = &user.ID
+	}
+
+	if err := s.DB.Save(&exchangeRate).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.Product{}).Where("currency = ?", code).
+		Update("reference_price", gorm.Expr("ROUND(price / ?, 4)", rate)).Error; err != nil {
+		return nil, err
+	}
+
+	return &exchangeRate, nil
+}
+
+// ExchangeRateDelete removes the rate of a currency. A rate cannot be removed
+// while products are still priced in that currency.
+func (s *Service) ExchangeRateDelete(ctx context.Context, code string) error {
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return err
+	}
+
+	var inUse int64
+	if err := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).Where("currency = ?", code).Count(&inUse).Error; err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return fmt.Errorf("%d products are priced in %s", inUse, code)
+	}
+
+	result := s.DB.Where("currency = ?", code).Delete(&model.ExchangeRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("exchange rate not found")
+	}
+
+	return nil
+}
+
+// ExchangeRateParseCSV reads a rate file with a currency,rate header.
+func ExchangeRateParseCSV(r io.Reader) (map[string]float64, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("csv file is empty")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	if len(header) != len(model.ExchangeRateCSVHeader) {
+		return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(model.ExchangeRateCSVHeader, ","))
+	}
+	for i, column := range model.ExchangeRateCSVHeader {
+		if !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")), column) {
+			return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(model.ExchangeRateCSVHeader, ","))
+		}
+	}
+
+	rates := make(map[string]float64)
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+
+		code, err := currency.Normalize(record[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if _, ok := rates[code]; ok {
+			return nil, fmt.Errorf("line %d: %s is listed more than once", line, code)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("line %d: invalid rate %s", line, record[1])
+		}
+
+		rates[code] = rate
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("csv file has no rows")
+	}
+
+	return rates, nil
+}
+
+// ExchangeRateImport sets every rate read from a file. The reference currency
+// may be listed with a rate of 1 and is skipped.
+func (s *Service) ExchangeRateImport(ctx context.Context, rates map[string]float64) (*model.ExchangeRateTable, error) {
+	for code, rate := range rates {
+		if code == currency.Default() {
+			if rate != 1 {
+				return nil, fmt.Errorf("%s is the reference currency; its rate must be 1", code)
+			}
+			continue
+		}
+
+		if _, err := s.ExchangeRateSet(ctx, code, rate, model.EXCHANGE_RATE_SOURCE_FILE); err != nil {
+			return nil, fmt.Errorf("%s: %w", code, err)
+		}
+	}
+
+	return s.ExchangeRateGetAll(ctx)
+}
+
+var ErrNoExchangeRate = fmt.Errorf("no exchange rate")
+
+// exchangeRate returns how many units of the currency one unit of the
+// reference currency buys.
+func (s *Service) exchangeRate(code string) (float64, error) {
+	if code == currency.Default() {
+		return 1, nil
+	}
+
+	var exchangeRate model.ExchangeRate
+	if err := s.DB.Model(&exchangeRate).Where("currency = ?", code).First(&exchangeRate).Error; err == gorm.ErrRecordNotFound {
+		return 0, fmt.Errorf("%w for %s", ErrNoExchangeRate, code)
+	} else if err != nil {
+		return 0, err
+	}
+
+	return exchangeRate.Rate, nil
+}
+
+// ExchangeRateBetween returns how many units of one currency a unit of
+// another buys.
+func (s *Service) ExchangeRateBetween(from string, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := s.exchangeRate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := s.exchangeRate(to)
+	if err != nil {
+		return 0, err
+	}
+
+	return toRate / fromRate, nil
+}
+
+// productReferencePrice converts a price to the reference currency, which is
+// what search filters and sorts on.
+func (s *Service) productReferencePrice(price float64, code string) (float64, error) {
+	rate, err := s.exchangeRate(code)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Round(price/rate*10000) / 10000, nil
+}
+
+// ProductApplyDisplayCurrency fills in the display price of each product in
+// the given currency. Nothing is changed when no currency is asked for.
+func (s *Service) ProductApplyDisplayCurrency(ctx context.Context, products []*model.Product, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return err
+	}
+
+	rates := make(map[string]float64)
+	for _, product := range products {
+		if product == nil {
+			continue
+		}
+
+		rate, ok := rates[product.Currency]
+		if !ok {
+			if rate, err = s.ExchangeRateBetween(product.Currency, code); err != nil {
+				return err
+			}
+			rates[product.Currency] = rate
+		}
+
+		price := currency.Round(product.Price*rate, code)
+		product.DisplayPrice = &price
+		product.DisplayCurrency = code
+	}
+
+	return nil
+}
+
+// ProductDetailApplyDisplayCurrency converts the price and pricing of a
+// product detail to the given currency.
+func (s *Service) ProductDetailApplyDisplayCurrency(ctx context.Context, detail *model.ProductDetail, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	if err := s.ProductApplyDisplayCurrency(ctx, []*model.Product{detail.Product}, code); err != nil {
+		return err
+	}
+
+	pricing, err := s.ResolvedPriceConvert(ctx, detail.Pricing, code)
+	if err != nil {
+		return err
+	}
+	detail.DisplayPricing = pricing
+
+	return nil
+}
+
+// ResolvedPriceConvert returns a copy of a resolved price in another currency,
+// with the rate that was used.
+func (s *Service) ResolvedPriceConvert(ctx context.Context, price *model.ResolvedPrice, code string) (*model.ResolvedPrice, error) {
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := s.ExchangeRateBetween(price.Currency, code)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := *price
+	converted.ListPrice = currency.Round(price.ListPrice*rate, code)
+	converted.EffectivePrice = currency.Round(price.EffectivePrice*rate, code)
+	converted.Currency = code
+	converted.ExchangeRate = rate
+
+	return &converted, nil
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"products/forecast"
+	"products/model"
+	"products/tools"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// forecastHistoryDays is how far back sales are read to fit a model.
+	forecastHistoryDays = 180
+	// forecastHorizonDays is how many days ahead demand is projected.
+	forecastHorizonDays = 30
+	// forecastSeasonDays is the length of the seasonal cycle, a week.
+	forecastSeasonDays = 7
+	// forecastLeadTimeDays is how long a reorder is assumed to take to arrive.
+	forecastLeadTimeDays = 7
+	forecastBatchSize    = 100
+)
+
+func StartDemandForecaster(interval time.Duration) {
+	RecomputeDemandForecasts(context.Background())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		RecomputeDemandForecasts(context.Background())
+	}
+}
+
+// RecomputeDemandForecasts refits the forecast of every product on sale. Sales
+// are fetched from the orders service one batch of products at a time, and
+// each forecast is saved in its own transaction so one failure does not hold
+// back the rest. It returns the number of forecasts saved.
+func RecomputeDemandForecasts(ctx context.Context) int {
+	var (
+		s        = GetService()
+		products []*model.Product
+		saved    int
+	)
+
+	result := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).Where("status = ?", model.PRODUCT_STATUS_ACTIVE).
+		FindInBatches(&products, forecastBatchSize, func(batch *gorm.DB, _ int) error {
+			since := forecastToday().AddDate(0, 0, -forecastHistoryDays)
+
+			ids := make([]int, 0, len(products))
+			for _, product := range products {
+				ids = append(ids, product.ID)
+			}
+
+			sales, err := GetProductSales(ctx, ids, since)
+			if err != nil {
+				log.Println("failed to load product sales:", err)
+				return nil
+			}
+
+			for _, product := range products {
+				tx := GetTransaction()
+				if _, err := tx.demandForecastSave(ctx, product, sales[product.ID]); err != nil {
+					tx.DB.Rollback()
+					log.Printf("failed to forecast product %d: %v", product.ID, err)
+					continue
+				}
+				if err := tx.Commit(); err != nil {
+					log.Printf("failed to forecast product %d: %v", product.ID, err)
+					continue
+				}
+				saved++
+			}
+
+			return nil
+		})
+	if result.Error != nil {
+		log.Println("failed to load products to forecast:", result.Error)
+	}
+
+	return saved
+}
+
+// DemandForecastGetByProductID returns the stored forecast of a product with
+// its daily points. A forecast is computed on the spot when the product has
+// none yet or when a refresh is asked for.
+func (s *Service) DemandForecastGetByProductID(ctx context.Context, productID int, refresh bool) (*model.DemandForecast, error) {
+	if !refresh {
+		var demandForecast model.DemandForecast
+
+		err := s.DB.Model(&demandForecast).Where("product_id = ?", productID).First(&demandForecast).Error
+		if err == nil {
+			if err := s.DB.Model(&model.DemandForecastPoint{}).Where("forecast_id = ?", demandForecast.ID).
+				Order("date ASC").Find(&demandForecast.Points).Error; err != nil {
+				return nil, err
+			}
+			return &demandForecast, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, err
+		}
+	}
+
+	return s.DemandForecastCompute(ctx, productID)
+}
+
+// DemandForecastCompute fits and saves a fresh forecast for a single product.
+func (s *Service) DemandForecastCompute(ctx context.Context, productID int) (*model.DemandForecast, error) {
+	product, err := s.ProductGetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	sales, err := GetProductSales(ctx, []int{productID}, forecastToday().AddDate(0, 0, -forecastHistoryDays))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.demandForecastSave(ctx, product, sales[productID])
+}
+
+// demandForecastSave fits a forecast from the daily sales of a product and
+// replaces the one stored for it.
+func (s *Service) demandForecastSave(ctx context.Context, product *model.Product, sales map[string]int) (*model.DemandForecast, error) {
+	demandForecast, err := demandForecastBuild(product, sales, forecastToday())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Where("forecast_id IN (?)", s.DB.Model(&model.DemandForecast{}).Select("id").Where("product_id = ?", product.ID)).
+		Delete(&model.DemandForecastPoint{}).Error; err != nil {
+		return nil, err
+	}
+	if err := s.DB.Where("product_id = ?", product.ID).Delete(&model.DemandForecast{}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Create(demandForecast).Error; err != nil {
+		return nil, err
+	}
+
+	for _, point := range demandForecast.Points {
+		point.ForecastID = demandForecast.ID
+	}
+	if len(demandForecast.Points) > 0 {
+		if err := s.DB.Create(demandForecast.Points).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return demandForecast, nil
+}
+
+// demandForecastBuild fits a model to the daily sales of a product, from the
+// start of the history window or the day it was listed, up to yesterday. Days
+// without sales count as zero demand, and a product listed today gets an empty
+// forecast.
+//
+// The reorder point covers the upper bound of demand over the lead time, and
+// the reorder quantity tops stock up to that plus the expected demand for the
+// rest of the horizon. The stock-out date is the first day expected demand
+// uses up the current stock.
+func demandForecastBuild(product *model.Product, sales map[string]int, today time.Time) (*model.DemandForecast, error) {
+	start := today.AddDate(0, 0, -forecastHistoryDays)
+	if listed := forecastDay(product.CreatedAt); listed.After(start) {
+		start = listed
+	}
+
+	var series []float64
+	for day := start; day.Before(today); day = day.AddDate(0, 0, 1) {
+		series = append(series, float64(sales[day.Format(time.DateOnly)]))
+	}
+
+	demandForecast := &model.DemandForecast{
+		ProductID:    product.ID,
+		SellerID:     product.SellerID,
+		HistoryDays:  len(series),
+		Stock:        product.Stock,
+		LeadTimeDays: forecastLeadTimeDays,
+		GeneratedAt:  time.Now(),
+	}
+
+	// a product listed today has no full day of sales to fit yet
+	if len(series) == 0 {
+		return demandForecast, nil
+	}
+
+	fitted, err := forecast.Fit(series, forecastSeasonDays)
+	if err != nil {
+		return nil, fmt.Errorf("cannot forecast product %d: %w", product.ID, err)
+	}
+
+	demandForecast.Method = string(fitted.Method)
+	demandForecast.Alpha = fitted.Alpha
+	demandForecast.Beta = fitted.Beta
+	demandForecast.Gamma = fitted.Gamma
+	demandForecast.RMSE = fitted.RMSE
+
+	var (
+		leadTimeUpper float64
+		horizonDemand float64
+		cumulative    float64
+	)
+
+	for i, point := range fitted.Forecast(forecastHorizonDays, forecast.Z95) {
+		date := today.AddDate(0, 0, i)
+
+		demandForecast.Points = append(demandForecast.Points, &model.DemandForecastPoint{
+			Date:   date,
+			Demand: math.Round(point.Mean*100) / 100,
+			Lower:  math.Round(point.Lower*100) / 100,
+			Upper:  math.Round(point.Upper*100) / 100,
+		})
+
+		if i < forecastLeadTimeDays {
+			leadTimeUpper += point.Upper
+		} else {
+			horizonDemand += point.Mean
+		}
+
+		cumulative += point.Mean
+		if demandForecast.StockOutDate == nil && cumulative >= float64(product.Stock) && cumulative > 0 {
+			demandForecast.StockOutDate = &date
+		}
+	}
+
+	demandForecast.ReorderPoint = int(math.Ceil(leadTimeUpper))
+	demandForecast.ReorderQuantity = max(0, int(math.Ceil(leadTimeUpper+horizonDemand))-product.Stock)
+
+	return demandForecast, nil
+}
+
+func forecastToday() time.Time {
+	return forecastDay(time.Now())
+}
+
+func forecastDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
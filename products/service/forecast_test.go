@@ -0,0 +1,50 @@
+package service
+
+import (
+	"products/forecast"
+	"products/model"
+	"testing"
+	"time"
+)
+
+func TestDemandForecastBuildListedToday(t *testing.T) {
+	today := forecastToday()
+	product := &model.Product{ID: 1, SellerID: 2, Stock: 10, CreatedAt: today.Add(time.Hour)}
+
+	demandForecast, err := demandForecastBuild(product, nil, today)
+	if err != nil {
+		t.Fatalf("demandForecastBuild() error = %v", err)
+	}
+	if demandForecast.HistoryDays != 0 || len(demandForecast.Points) != 0 {
+		t.Errorf("got %d days of history and %d points, want an empty forecast", demandForecast.HistoryDays, len(demandForecast.Points))
+	}
+	if demandForecast.StockOutDate != nil || demandForecast.ReorderPoint != 0 || demandForecast.ReorderQuantity != 0 {
+		t.Errorf("got stock-out %v, reorder point %d, quantity %d, want none", demandForecast.StockOutDate, demandForecast.ReorderPoint, demandForecast.ReorderQuantity)
+	}
+}
+
+func TestDemandForecastBuildHistory(t *testing.T) {
+	today := forecastToday()
+	product := &model.Product{ID: 1, SellerID: 2, Stock: 10, CreatedAt: today.AddDate(0, 0, -3)}
+
+	sales := map[string]int{}
+	for i := 1; i <= 3; i++ {
+		sales[today.AddDate(0, 0, -i).Format(time.DateOnly)] = 2
+	}
+
+	demandForecast, err := demandForecastBuild(product, sales, today)
+	if err != nil {
+		t.Fatalf("demandForecastBuild() error = %v", err)
+	}
+	if demandForecast.HistoryDays != 3 || demandForecast.Method != string(forecast.METHOD_HOLT) {
+		t.Errorf("got %d days fitted with %s, want 3 with %s", demandForecast.HistoryDays, demandForecast.Method, forecast.METHOD_HOLT)
+	}
+	if len(demandForecast.Points) != forecastHorizonDays {
+		t.Fatalf("got %d points, want %d", len(demandForecast.Points), forecastHorizonDays)
+	}
+
+	// two a day uses up ten units on the fifth day
+	if want := today.AddDate(0, 0, 4); demandForecast.StockOutDate == nil || !demandForecast.StockOutDate.Equal(want) {
+		t.Errorf("stock-out date = %v, want %v", demandForecast.StockOutDate, want)
+	}
+}
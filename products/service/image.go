@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"products/model"
+	"products/storage"
+	"products/tools"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const defaultMaxImageSize = 5 << 20
+
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+func (s *Service) ProductImageUpload(ctx context.Context, productID int, fileHeader *multipart.FileHeader, isPrimary bool) (*model.ProductImage, error) {
+	var (
+		count int64
+		store = storage.GetStorage()
+	)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	contentType, err := s.ProductImageOnUpload(ctx, file, fileHeader.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.ProductImage{}).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	key, err := newImageKey(productID, allowedImageTypes[contentType])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Save(ctx, key, file, contentType); err != nil {
+		return nil, err
+	}
+
+	image := model.ProductImage{
+		ProductID:   productID,
+		StorageKey:  key,
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+		Position:    int(count),
+		IsPrimary:   isPrimary || count == 0,
+	}
+
+	if image.IsPrimary {
+		if err := s.productImageClearPrimary(productID); err != nil {
+			store.Delete(ctx, key)
+			return nil, err
+		}
+	}
+
+	if err := s.DB.Model(&image).Create(&image).Error; err != nil {
+		store.Delete(ctx, key)
+		return nil, err
+	}
+
+	image.URL = store.URL(image.StorageKey)
+
+	return &image, nil
+}
+
+// ProductImageOnUpload validates the size of the upload and sniffs its content
+// type from the first bytes of the file instead of trusting the client header.
+func (s *Service) ProductImageOnUpload(ctx context.Context, file multipart.File, size int64) (string, error) {
+	maxSize := int64(defaultMaxImageSize)
+	if v, err := strconv.ParseInt(os.Getenv("IMAGE_MAX_SIZE"), 10, 64); err == nil && v > 0 {
+		maxSize = v
+	}
+
+	if size <= 0 {
+		return "", fmt.Errorf("invalid input: image cannot be empty")
+	}
+	if size > maxSize {
+		return "", fmt.Errorf("invalid input: image exceeds maximum size of %d bytes", maxSize)
+	}
+
+	header := make([]byte, 512)
+	n, err := file.Read(header)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(header[:n])
+	if _, ok := allowedImageTypes[contentType]; !ok {
+		return "", fmt.Errorf("invalid input: unsupported image type %s", contentType)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return contentType, nil
+}
+
+func (s *Service) ProductImageGetByProductID(ctx context.Context, productID int) ([]*model.ProductImage, error) {
+	var (
+		images []*model.ProductImage
+		store  = storage.GetStorage()
+	)
+
+	if err := s.DB.Model(&images).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Order("position ASC").Order("id ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+
+	for _, image := range images {
+		image.URL = store.URL(image.StorageKey)
+	}
+
+	return images, nil
+}
+
+func (s *Service) ProductImageGetByID(ctx context.Context, productID int, imageID int) (*model.ProductImage, error) {
+	var image *model.ProductImage
+
+	if err := s.DB.Model(&image).Scopes(tools.IsDeletedAtNull).Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("image not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	image.URL = storage.GetStorage().URL(image.StorageKey)
+
+	return image, nil
+}
+
+// ProductGetPrimaryImage returns the url of the product's primary image, or
+// an empty string when the product has no images.
+func (s *Service) ProductGetPrimaryImage(ctx context.Context, productID int) (string, error) {
+	var image *model.ProductImage
+
+	err := s.DB.Model(&image).Scopes(tools.IsDeletedAtNull).Where("product_id = ? AND is_primary = ?", productID, true).First(&image).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return storage.GetStorage().URL(image.StorageKey), nil
+}
+
+func (s *Service) ProductImageSetPrimary(ctx context.Context, productID int, imageID int) (bool, error) {
+	if _, err := s.ProductImageGetByID(ctx, productID, imageID); err != nil {
+		return false, err
+	}
+
+	if err := s.productImageClearPrimary(productID); err != nil {
+		return false, err
+	}
+
+	if err := s.DB.Model(&model.ProductImage{}).Where("id = ?", imageID).Update("is_primary", true).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ProductImageReorder sets the position of every image of the product to its
+// index in imageIDs, which must list each of the product's images exactly once.
+func (s *Service) ProductImageReorder(ctx context.Context, productID int, imageIDs []int) (bool, error) {
+	images, err := s.ProductImageGetByProductID(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(imageIDs) != len(images) || len(uniqueInts(imageIDs)) != len(imageIDs) {
+		return false, fmt.Errorf("invalid input: image order must contain every product image once")
+	}
+
+	existing := make(map[int]bool, len(images))
+	for _, image := range images {
+		existing[image.ID] = true
+	}
+
+	for position, imageID := range imageIDs {
+		if !existing[imageID] {
+			return false, fmt.Errorf("invalid input: image %d does not belong to product", imageID)
+		}
+
+		if err := s.DB.Model(&model.ProductImage{}).Where("id = ?", imageID).Update("position", position).Error; err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func (s *Service) ProductImageDelete(ctx context.Context, productID int, imageID int) (bool, error) {
+	image, err := s.ProductImageGetByID(ctx, productID, imageID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.DB.Model(&model.ProductImage{}).Where("id = ?", imageID).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"is_primary": false,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	// promote the next image so the product keeps a primary image
+	if image.IsPrimary {
+		var next *model.ProductImage
+		err := s.DB.Model(&next).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Order("position ASC").Order("id ASC").First(&next).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return false, err
+		}
+		if err == nil {
+			if err := s.DB.Model(&model.ProductImage{}).Where("id = ?", next.ID).Update("is_primary", true).Error; err != nil {
+				return false, err
+			}
+		}
+	}
+
+	// files are kept in storage since order snapshots may still reference them
+	return true, nil
+}
+
+func (s *Service) productImageClearPrimary(productID int) error {
+	return s.DB.Model(&model.ProductImage{}).Where("product_id = ? AND is_primary = ?", productID, true).Update("is_primary", false).Error
+}
+
+func newImageKey(productID int, ext string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("products/%d/%s%s", productID, hex.EncodeToString(b), ext), nil
+}
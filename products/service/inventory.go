@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+)
+
+func (s *Service) InventoryGetMovements(ctx context.Context, productID int) ([]*model.InventoryMovement, error) {
+	var (
+		movements      []*model.InventoryMovement
+		reservationIDs []int
+	)
+
+	if err := s.DB.Model(&movements).Where("product_id = ?", productID).Order("id DESC").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+
+	// sales taken through a reservation only learn their order once the
+	// reservation is committed, so the order id is read from the reservation
+	for _, movement := range movements {
+		if movement.OrderID == nil && movement.ReservationID != nil {
+			reservationIDs = append(reservationIDs, *movement.ReservationID)
+		}
+	}
+
+	if len(reservationIDs) == 0 {
+		return movements, nil
+	}
+
+	var reservations []*model.StockReservation
+	if err := s.DB.Model(&reservations).Where("id IN (?) AND order_id IS NOT NULL", uniqueInts(reservationIDs)).Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	orderByReservation := make(map[int]*int, len(reservations))
+	for _, reservation := range reservations {
+		orderByReservation[reservation.ID] = reservation.OrderID
+	}
+
+	for _, movement := range movements {
+		if movement.OrderID == nil && movement.ReservationID != nil {
+			movement.OrderID = orderByReservation[*movement.ReservationID]
+		}
+	}
+
+	return movements, nil
+}
+
+func (s *Service) InventoryAdjust(ctx context.Context, productID int, input model.NewStockAdjustment) (bool, error) {
+	if input.Quantity == 0 {
+		return false, fmt.Errorf("invalid input: quantity cannot be zero")
+	}
+
+	switch input.Reason {
+	case model.MOVEMENT_REASON_RESTOCK, model.MOVEMENT_REASON_RETURN:
+		if input.Quantity < 0 {
+			return false, fmt.Errorf("invalid input: %s must add stock", input.Reason)
+		}
+	case model.MOVEMENT_REASON_MANUAL_ADJUSTMENT:
+	default:
+		return false, fmt.Errorf("invalid input: unsupported adjustment reason %s", input.Reason)
+	}
+
+	bundle, err := s.productIsBundle(productID)
+	if err != nil {
+		return false, err
+	}
+	if bundle {
+		return false, fmt.Errorf("bundle stock is derived from its components; adjust the components instead")
+	}
+
+	if input.WarehouseID > 0 {
+		product, err := s.ProductGetByID(ctx, productID)
+		if err != nil {
+			return false, err
+		}
+		if _, err := s.WarehouseGetByID(ctx, input.WarehouseID, product.SellerID); err != nil {
+			return false, err
+		}
+	}
+
+	change := model.StockChange{
+		ProductID:   productID,
+		VariantID:   input.VariantID,
+		Quantity:    input.Quantity,
+		Reason:      input.Reason,
+		Note:        input.Note,
+		WarehouseID: input.WarehouseID,
+	}
+
+	if input.Quantity < 0 {
+		change.Quantity = -input.Quantity
+		if _, err := s.productDecrementStock(ctx, change); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if err := s.productIncrementStock(ctx, change); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// InventoryReconcile recomputes stock from the ledger for every product and
+// variant that holds its own stock, and returns those whose stock column does
+// not match.
+func (s *Service) InventoryReconcile(ctx context.Context) ([]*model.InventoryDrift, error) {
+	var (
+		productDrift []*model.InventoryDrift
+		variantDrift []*model.InventoryDrift
+	)
+
+	if err := s.DB.Table("product").
+		Select("product.id AS product_id, 0 AS variant_id, product.stock AS stock, COALESCE(SUM(inventory_movement.delta), 0) AS ledger_stock").
+		Joins("LEFT JOIN inventory_movement ON inventory_movement.product_id = product.id AND inventory_movement.variant_id = 0").
+		Where("product.deleted_at IS NULL AND product.type <> ?", model.PRODUCT_TYPE_BUNDLE).
+		Where("NOT EXISTS (SELECT 1 FROM product_variant WHERE product_variant.product_id = product.id AND product_variant.deleted_at IS NULL)").
+		Group("product.id, product.stock").
+		Having("product.stock <> COALESCE(SUM(inventory_movement.delta), 0)").
+		Scan(&productDrift).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Table("product_variant").
+		Select("product_variant.product_id AS product_id, product_variant.id AS variant_id, product_variant.stock AS stock, COALESCE(SUM(inventory_movement.delta), 0) AS ledger_stock").
+		Joins("LEFT JOIN inventory_movement ON inventory_movement.variant_id = product_variant.id").
+		Where("product_variant.deleted_at IS NULL").
+		Group("product_variant.product_id, product_variant.id, product_variant.stock").
+		Having("product_variant.stock <> COALESCE(SUM(inventory_movement.delta), 0)").
+		Scan(&variantDrift).Error; err != nil {
+		return nil, err
+	}
+
+	drifts := append(productDrift, variantDrift...)
+	for _, drift := range drifts {
+		drift.Drift = drift.Stock - drift.LedgerStock
+	}
+
+	return drifts, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"slices"
+	"strings"
+	"utils/middleware"
+)
+
+// productTransitions lists the statuses a product may move to from each
+// status. Only active products are shown in the catalog or can be bought. An
+// active product goes back to review when its seller edits it.
+var productTransitions = map[model.ProductStatus][]model.ProductStatus{
+	model.PRODUCT_STATUS_DRAFT:          {model.PRODUCT_STATUS_PENDING_REVIEW, model.PRODUCT_STATUS_ARCHIVED},
+	model.PRODUCT_STATUS_PENDING_REVIEW: {model.PRODUCT_STATUS_ACTIVE, model.PRODUCT_STATUS_REJECTED, model.PRODUCT_STATUS_ARCHIVED},
+	model.PRODUCT_STATUS_ACTIVE:         {model.PRODUCT_STATUS_PENDING_REVIEW, model.PRODUCT_STATUS_ARCHIVED},
+	model.PRODUCT_STATUS_REJECTED:       {model.PRODUCT_STATUS_PENDING_REVIEW, model.PRODUCT_STATUS_ARCHIVED},
+	model.PRODUCT_STATUS_ARCHIVED:       {model.PRODUCT_STATUS_PENDING_REVIEW},
+}
+
+// ProductSubmit sends a draft, rejected or archived product to the moderation
+// queue.
+func (s *Service) ProductSubmit(ctx context.Context, id int) (*model.Product, error) {
+	product, err := s.ProductGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status == string(model.PRODUCT_STATUS_ACTIVE) {
+		return nil, fmt.Errorf("product is already active")
+	}
+
+	return s.productTransition(ctx, id, model.PRODUCT_STATUS_PENDING_REVIEW, "")
+}
+
+// productResubmitEdited sends an active product back to the moderation queue
+// when its name, description or price no longer match what was approved.
+func (s *Service) productResubmitEdited(ctx context.Context, product *model.Product, name string, description string, price float64) error {
+	if product.Status != string(model.PRODUCT_STATUS_ACTIVE) {
+		return nil
+	}
+	if name == product.Name && description == product.Description && price == product.Price {
+		return nil
+	}
+
+	_, err := s.productTransition(ctx, product.ID, model.PRODUCT_STATUS_PENDING_REVIEW, "edited after approval")
+
+	return err
+}
+
+// ProductArchive takes a product off sale without deleting it. It has to be
+// reviewed again before it goes back on sale.
+func (s *Service) ProductArchive(ctx context.Context, id int) (*model.Product, error) {
+	return s.productTransition(ctx, id, model.PRODUCT_STATUS_ARCHIVED, "")
+}
+
+func (s *Service) ProductModerate(ctx context.Context, id int, input model.ModerateProduct) (*model.Product, error) {
+	var (
+		reason = strings.TrimSpace(input.Reason)
+		to     model.ProductStatus
+	)
+
+	switch input.Action {
+	case model.PRODUCT_MODERATION_APPROVE:
+		to = model.PRODUCT_STATUS_ACTIVE
+	case model.PRODUCT_MODERATION_REJECT:
+		if reason == "" {
+			return nil, fmt.Errorf("invalid input: a reason is required to reject a product")
+		}
+		to = model.PRODUCT_STATUS_REJECTED
+	default:
+		return nil, fmt.Errorf("invalid input: unsupported moderation action %s", input.Action)
+	}
+
+	product, err := s.ProductGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != string(model.PRODUCT_STATUS_PENDING_REVIEW) {
+		return nil, fmt.Errorf("product is not pending review")
+	}
+
+	return s.productTransition(ctx, id, to, reason)
+}
+
+// ProductGetPendingReview returns the moderation queue, oldest submission
+// first.
+func (s *Service) ProductGetPendingReview(ctx context.Context) ([]*model.Product, error) {
+	var products []*model.Product
+
+	if err := s.DB.Model(&products).Scopes(tools.IsDeletedAtNull).
+		Where("status = ?", model.PRODUCT_STATUS_PENDING_REVIEW).
+		Order("updated_at ASC").Order("id ASC").
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
+
+func (s *Service) ProductStatusHistoryGetByProductID(ctx context.Context, productID int) ([]*model.ProductStatusHistory, error) {
+	var history []*model.ProductStatusHistory
+
+	if err := s.DB.Model(&history).Where("product_id = ?", productID).Order("id ASC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// productTransition moves a product to a new status. The update only matches
+// the status the product was read in, so two concurrent transitions cannot
+// both apply.
+func (s *Service) productTransition(ctx context.Context, id int, to model.ProductStatus, reason string) (*model.Product, error) {
+	product, err := s.ProductGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	from := model.ProductStatus(product.Status)
+	if !slices.Contains(productTransitions[from], to) {
+		return nil, fmt.Errorf("product cannot move from %s to %s", from, to)
+	}
+
+	result := s.DB.Model(&model.Product{}).Where("id = ? AND status = ?", id, from).Update("status", to)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("product status has changed, please try again")
+	}
+
+	if err := s.productRecordStatusChange(ctx, id, from, to, reason); err != nil {
+		return nil, err
+	}
+
+	if err := s.bundleSyncStock(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.ProductGetByID(ctx, id)
+}
+
+func (s *Service) productRecordStatusChange(ctx context.Context, productID int, from model.ProductStatus, to model.ProductStatus, reason string) error {
+	history := model.ProductStatusHistory{
+		ProductID:  productID,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		Reason:     reason,
+	}
+
+	if user := middleware.AuthContext(ctx); user != nil {
+		history.ActorID = &user.ID
+	}
+
+	return s.DB.Model(&history).Create(&history).Error
+}
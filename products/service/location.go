@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productTracksLocations reports whether stock for the product, or the given
+// variant, is held per warehouse rather than in the stock column alone.
+func (s *Service) productTracksLocations(productID int, variantID int) (bool, error) {
+	var count int64
+
+	if err := s.DB.Model(&model.LocationStock{}).Where("product_id = ? AND variant_id = ?", productID, variantID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// locationAllocate picks the warehouses a quantity is taken from. Warehouses
+// are tried in priority order; the first one that can cover the whole quantity
+// is used on its own, otherwise the quantity is split across warehouses in the
+// same order. The location rows are locked until the transaction ends.
+func (s *Service) locationAllocate(change model.StockChange) ([]*model.StockAllocation, error) {
+	var locations []*model.LocationStock
+
+	query := s.DB.Model(&model.LocationStock{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Joins("JOIN warehouse ON warehouse.id = location_stock.warehouse_id").
+		Where("location_stock.product_id = ? AND location_stock.variant_id = ? AND location_stock.stock > 0", change.ProductID, change.VariantID).
+		Where("warehouse.deleted_at IS NULL").
+		Order("warehouse.priority ASC").Order("warehouse.id ASC")
+	if change.WarehouseID > 0 {
+		query = query.Where("location_stock.warehouse_id = ?", change.WarehouseID)
+	}
+
+	if err := query.Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	return locationPlan(locations, change)
+}
+
+// locationPlan splits a quantity across locations given in priority order.
+func locationPlan(locations []*model.LocationStock, change model.StockChange) ([]*model.StockAllocation, error) {
+	for _, location := range locations {
+		if location.Stock >= change.Quantity {
+			return []*model.StockAllocation{newStockAllocation(change, location.WarehouseID, change.Quantity)}, nil
+		}
+	}
+
+	var (
+		allocations []*model.StockAllocation
+		remaining   = change.Quantity
+	)
+
+	for _, location := range locations {
+		if remaining == 0 {
+			break
+		}
+
+		qty := min(location.Stock, remaining)
+		allocations = append(allocations, newStockAllocation(change, location.WarehouseID, qty))
+		remaining -= qty
+	}
+
+	if remaining > 0 {
+		return nil, fmt.Errorf("insufficient stock or stock not found")
+	}
+
+	return allocations, nil
+}
+
+// locationDecrementStock takes stock from the warehouses chosen by
+// locationAllocate and keeps the stock column equal to the sum of locations.
+func (s *Service) locationDecrementStock(ctx context.Context, hasVariants bool, change model.StockChange) ([]*model.StockAllocation, error) {
+	allocations, err := s.locationAllocate(change)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, allocation := range allocations {
+		if err := s.locationApply(ctx, hasVariants, change, allocation.WarehouseID, -allocation.Quantity); err != nil {
+			return nil, err
+		}
+
+		if err := s.DB.Model(allocation).Create(allocation).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return allocations, nil
+}
+
+// locationIncrementStock puts stock back into a warehouse. Stock released from
+// a reservation goes back to the warehouses it was taken from; anything else
+// goes to the named warehouse or, failing that, the highest priority one.
+func (s *Service) locationIncrementStock(ctx context.Context, hasVariants bool, change model.StockChange) error {
+	var allocations []*model.StockAllocation
+
+	if change.WarehouseID == 0 && change.ReservationItemID != nil {
+		if err := s.DB.Model(&model.StockAllocation{}).Where("reservation_item_id = ?", *change.ReservationItemID).Find(&allocations).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(allocations) == 0 {
+		warehouseID := change.WarehouseID
+		if warehouseID == 0 {
+			var err error
+			if warehouseID, err = s.locationDefaultWarehouse(change.ProductID, change.VariantID); err != nil {
+				return err
+			}
+		}
+
+		allocations = []*model.StockAllocation{newStockAllocation(change, warehouseID, change.Quantity)}
+	}
+
+	for _, allocation := range allocations {
+		if err := s.locationApply(ctx, hasVariants, change, allocation.WarehouseID, allocation.Quantity); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// locationSetStock overwrites the stock held at one warehouse. Setting stock
+// at a first warehouse switches the product to per-location tracking, so the
+// stock column becomes that warehouse's stock.
+func (s *Service) locationSetStock(ctx context.Context, hasVariants bool, change model.StockChange, stock int) error {
+	tracked, err := s.productTracksLocations(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.productCurrentStock(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+
+	location, err := s.locationGetOrCreate(change.ProductID, change.VariantID, change.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	delta := locationSetDelta(tracked, current, location.Stock, stock)
+
+	if err := s.DB.Model(&model.LocationStock{}).Where("id = ?", location.ID).Update("stock", stock).Error; err != nil {
+		return err
+	}
+
+	if delta == 0 {
+		return nil
+	}
+
+	if err := s.productAdjustStockColumn(change.ProductID, change.VariantID, delta); err != nil {
+		return err
+	}
+
+	change.WarehouseID = location.WarehouseID
+	return s.productRecordMovement(ctx, hasVariants, delta, change)
+}
+
+// locationSetDelta is how far setting a warehouse to stock moves the stock
+// column. Once stock is tracked per location only that warehouse's share
+// changes; the first warehouse set replaces the untracked stock outright.
+func locationSetDelta(tracked bool, current int, locationStock int, stock int) int {
+	if !tracked {
+		return stock - current
+	}
+
+	return stock - locationStock
+}
+
+// locationApply moves delta units in or out of one warehouse together with
+// the stock column, and records the movement against that warehouse.
+func (s *Service) locationApply(ctx context.Context, hasVariants bool, change model.StockChange, warehouseID int, delta int) error {
+	location, err := s.locationGetOrCreate(change.ProductID, change.VariantID, warehouseID)
+	if err != nil {
+		return err
+	}
+
+	result := s.DB.Model(&model.LocationStock{}).Where("id = ? AND stock + ? >= 0", location.ID, delta).
+		Update("stock", gorm.Expr("stock + ?", delta))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("insufficient stock at warehouse %d", warehouseID)
+	}
+
+	if err := s.productAdjustStockColumn(change.ProductID, change.VariantID, delta); err != nil {
+		return err
+	}
+
+	change.WarehouseID = warehouseID
+	return s.productRecordMovement(ctx, hasVariants, delta, change)
+}
+
+func (s *Service) locationGetOrCreate(productID int, variantID int, warehouseID int) (*model.LocationStock, error) {
+	var locations []*model.LocationStock
+
+	if err := s.DB.Model(&locations).Where("product_id = ? AND variant_id = ? AND warehouse_id = ?", productID, variantID, warehouseID).
+		Limit(1).Find(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	if len(locations) > 0 {
+		return locations[0], nil
+	}
+
+	location := model.LocationStock{
+		WarehouseID: warehouseID,
+		ProductID:   productID,
+		VariantID:   variantID,
+	}
+
+	if err := s.DB.Model(&location).Create(&location).Error; err != nil {
+		return nil, err
+	}
+
+	return &location, nil
+}
+
+func (s *Service) locationDefaultWarehouse(productID int, variantID int) (int, error) {
+	var ids []int
+
+	if err := s.DB.Model(&model.LocationStock{}).
+		Joins("JOIN warehouse ON warehouse.id = location_stock.warehouse_id").
+		Where("location_stock.product_id = ? AND location_stock.variant_id = ? AND warehouse.deleted_at IS NULL", productID, variantID).
+		Order("warehouse.priority ASC").Order("warehouse.id ASC").
+		Limit(1).Pluck("location_stock.warehouse_id", &ids).Error; err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("no warehouse holds stock for product %d", productID)
+	}
+
+	return ids[0], nil
+}
+
+func (s *Service) productAdjustStockColumn(productID int, variantID int, delta int) error {
+	var result *gorm.DB
+
+	if variantID > 0 {
+		result = s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ? AND stock + ? >= 0", variantID, productID, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+	} else {
+		result = s.DB.Model(&model.Product{}).Where("id = ? AND stock + ? >= 0", productID, delta).
+			Update("stock", gorm.Expr("stock + ?", delta))
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("insufficient stock or stock not found")
+	}
+
+	return nil
+}
+
+// StockGetAvailability returns the aggregate stock of a product, or one of its
+// variants, together with the stock held at each warehouse.
+func (s *Service) StockGetAvailability(ctx context.Context, productID int, variantID int) (*model.StockAvailability, error) {
+	if _, err := s.productResolveVariant(ctx, productID, variantID); err != nil {
+		return nil, err
+	}
+
+	stock, err := s.productCurrentStock(productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.locationAvailability(productID, &variantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.StockAvailability{
+		ProductID: productID,
+		VariantID: variantID,
+		Stock:     stock,
+		Locations: locations,
+	}, nil
+}
+
+func (s *Service) LocationStockGetByProductID(ctx context.Context, productID int) ([]*model.LocationAvailability, error) {
+	return s.locationAvailability(productID, nil)
+}
+
+func (s *Service) locationAvailability(productID int, variantID *int) ([]*model.LocationAvailability, error) {
+	locations := []*model.LocationAvailability{}
+
+	query := s.DB.Table("location_stock").
+		Select("location_stock.warehouse_id, warehouse.name AS warehouse_name, warehouse.priority, location_stock.variant_id, location_stock.stock").
+		Joins("JOIN warehouse ON warehouse.id = location_stock.warehouse_id").
+		Where("location_stock.product_id = ? AND warehouse.deleted_at IS NULL", productID).
+		Order("location_stock.variant_id ASC").Order("warehouse.priority ASC").Order("warehouse.id ASC")
+	if variantID != nil {
+		query = query.Where("location_stock.variant_id = ?", *variantID)
+	}
+
+	if err := query.Scan(&locations).Error; err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+func newStockAllocation(change model.StockChange, warehouseID int, qty int) *model.StockAllocation {
+	return &model.StockAllocation{
+		ReservationItemID: change.ReservationItemID,
+		OrderID:           change.OrderID,
+		ProductID:         change.ProductID,
+		VariantID:         change.VariantID,
+		WarehouseID:       warehouseID,
+		Quantity:          qty,
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"products/model"
+	"testing"
+)
+
+func TestLocationPlan(t *testing.T) {
+	// warehouses in priority order, as locationAllocate loads them
+	locations := []*model.LocationStock{
+		{WarehouseID: 3, Stock: 4},
+		{WarehouseID: 1, Stock: 10},
+		{WarehouseID: 2, Stock: 5},
+	}
+
+	tests := []struct {
+		name     string
+		quantity int
+		want     map[int]int
+		order    []int
+		wantErr  bool
+	}{
+		{"first warehouse covers it", 3, map[int]int{3: 3}, []int{3}, false},
+		{"first warehouse that covers it alone", 8, map[int]int{1: 8}, []int{1}, false},
+		{"split in priority order", 16, map[int]int{3: 4, 1: 10, 2: 2}, []int{3, 1, 2}, false},
+		{"everything", 19, map[int]int{3: 4, 1: 10, 2: 5}, []int{3, 1, 2}, false},
+		{"more than held", 20, nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		allocations, err := locationPlan(locations, model.StockChange{ProductID: 7, Quantity: tt.quantity})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if len(allocations) != len(tt.order) {
+			t.Errorf("%s: got %d allocations, want %d", tt.name, len(allocations), len(tt.order))
+			continue
+		}
+
+		total := 0
+		for i, allocation := range allocations {
+			if allocation.WarehouseID != tt.order[i] || allocation.Quantity != tt.want[allocation.WarehouseID] {
+				t.Errorf("%s: allocation %d = %d from warehouse %d, want %d from warehouse %d", tt.name, i, allocation.Quantity, allocation.WarehouseID, tt.want[tt.order[i]], tt.order[i])
+			}
+			if allocation.ProductID != 7 {
+				t.Errorf("%s: allocation %d is for product %d, want 7", tt.name, i, allocation.ProductID)
+			}
+			total += allocation.Quantity
+		}
+		if total != tt.quantity {
+			t.Errorf("%s: allocated %d, want %d", tt.name, total, tt.quantity)
+		}
+	}
+}
+
+func TestLocationPlanNoLocations(t *testing.T) {
+	if _, err := locationPlan(nil, model.StockChange{ProductID: 7, Quantity: 1}); err == nil {
+		t.Error("locationPlan() with no locations succeeded, want an error")
+	}
+}
+
+// TestLocationStockColumn walks a product from untracked stock through
+// per-warehouse sets and sales, checking that the stock column moves with the
+// sum of its locations.
+func TestLocationStockColumn(t *testing.T) {
+	var (
+		column    = 10
+		locations = map[int]*model.LocationStock{}
+		priority  = []int{}
+	)
+
+	set := func(warehouseID int, stock int) {
+		tracked := len(locations) > 0
+
+		location, ok := locations[warehouseID]
+		if !ok {
+			location = &model.LocationStock{WarehouseID: warehouseID}
+			locations[warehouseID] = location
+			priority = append(priority, warehouseID)
+		}
+
+		column += locationSetDelta(tracked, column, location.Stock, stock)
+		location.Stock = stock
+	}
+
+	sell := func(quantity int) {
+		ordered := make([]*model.LocationStock, 0, len(priority))
+		for _, warehouseID := range priority {
+			ordered = append(ordered, locations[warehouseID])
+		}
+
+		allocations, err := locationPlan(ordered, model.StockChange{ProductID: 7, Quantity: quantity})
+		if err != nil {
+			t.Fatalf("selling %d: %v", quantity, err)
+		}
+		for _, allocation := range allocations {
+			locations[allocation.WarehouseID].Stock -= allocation.Quantity
+			column -= allocation.Quantity
+		}
+	}
+
+	check := func(step string) {
+		sum := 0
+		for _, location := range locations {
+			sum += location.Stock
+		}
+		if column != sum {
+			t.Errorf("after %s: stock column = %d, locations hold %d", step, column, sum)
+		}
+	}
+
+	// the first warehouse replaces the untracked stock rather than adding to it
+	set(1, 4)
+	if column != 4 {
+		t.Errorf("switching to per-location stock: column = %d, want 4", column)
+	}
+	check("the first warehouse")
+
+	set(2, 6)
+	check("a second warehouse")
+
+	set(1, 3)
+	check("lowering a warehouse")
+
+	sell(5)
+	check("a sale split across warehouses")
+
+	sell(2)
+	check("a sale from one warehouse")
+}
+
+func TestLocationSetDelta(t *testing.T) {
+	tests := []struct {
+		name          string
+		tracked       bool
+		current       int
+		locationStock int
+		stock         int
+		want          int
+	}{
+		{"first warehouse below untracked stock", false, 10, 0, 4, -6},
+		{"first warehouse above untracked stock", false, 10, 0, 12, 2},
+		{"first warehouse at untracked stock", false, 10, 0, 10, 0},
+		{"new warehouse once tracked", true, 10, 0, 5, 5},
+		{"existing warehouse once tracked", true, 10, 6, 2, -4},
+	}
+
+	for _, tt := range tests {
+		if got := locationSetDelta(tt.tracked, tt.current, tt.locationStock, tt.stock); got != tt.want {
+			t.Errorf("%s: delta = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	grpcclient "products/grpc_client"
+	"time"
+	"utils/orders"
+)
+
+// VerifyPurchase asks the orders service for a completed order in which the
+// user bought the product, returning its id or 0 when there is none.
+func VerifyPurchase(ctx context.Context, userID int, productID int) (int, error) {
+	purchase, err := grpcclient.VerifyPurchase(ctx, &orders.VerifyPurchaseRequest{UserId: int64(userID), ProductId: int64(productID)})
+	if err != nil {
+		return 0, err
+	}
+
+	if !purchase.Verified {
+		return 0, nil
+	}
+
+	return int(purchase.OrderId), nil
+}
+
+// GetProductSales asks the orders service for the quantity of each product
+// sold per day since the given time, keyed by product id and then by day.
+func GetProductSales(ctx context.Context, productIDs []int, since time.Time) (map[int]map[string]int, error) {
+	req := &orders.ProductSalesRequest{Since: since.Format(time.RFC3339)}
+	for _, id := range productIDs {
+		req.ProductIds = append(req.ProductIds, int64(id))
+	}
+
+	resp, err := grpcclient.GetProductSales(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sales := make(map[int]map[string]int, len(productIDs))
+	for _, sale := range resp.Sales {
+		productID := int(sale.ProductId)
+		if sales[productID] == nil {
+			sales[productID] = make(map[string]int)
+		}
+		sales[productID][sale.Date] = int(sale.Quantity)
+	}
+
+	return sales, nil
+}
+
+// GetCoPurchases asks the orders service how often products were bought
+// together in completed orders since the given time. It returns the pairs and
+// the number of orders each product appeared in.
+func GetCoPurchases(ctx context.Context, since time.Time) ([]*orders.CoPurchase, map[int]int, error) {
+	resp, err := grpcclient.GetCoPurchases(ctx, &orders.CoPurchaseRequest{Since: since.Format(time.RFC3339)})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productOrders := make(map[int]int, len(resp.Products))
+	for _, product := range resp.Products {
+		productOrders[int(product.ProductId)] = int(product.Orders)
+	}
+
+	return resp.Pairs, productOrders, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"time"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+)
+
+// ProductResolvePrice returns the list and effective price of a product, or
+// of one of its variants, at the given instant. A schedule for the variant
+// wins over one for the product; product schedules only reach variants that
+// have no price of their own. Deleted products still resolve, so the price
+// of something already sold can be looked up.
+func (s *Service) ProductResolvePrice(ctx context.Context, productID int, variantID int, at time.Time) (*model.ResolvedPrice, error) {
+	product, err := s.ProductGetByIDWithDeleted(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		basePrice    = product.Price
+		inheritsBase = true
+	)
+
+	if variantID > 0 {
+		variant, err := s.ProductVariantGetByID(ctx, productID, variantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.Price != nil {
+			basePrice = *variant.Price
+			inheritsBase = false
+		}
+	}
+
+	schedule, err := s.priceScheduleActive(productID, variantID, at)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil && variantID > 0 && inheritsBase {
+		if schedule, err = s.priceScheduleActive(productID, 0, at); err != nil {
+			return nil, err
+		}
+	}
+
+	resolved := model.ResolvedPrice{
+		ProductID:      productID,
+		VariantID:      variantID,
+		ListPrice:      basePrice,
+		EffectivePrice: basePrice,
+		Currency:       product.Currency,
+		At:             at,
+	}
+
+	if schedule != nil {
+		resolved.ListPrice = schedule.ListPrice
+		resolved.EffectivePrice = schedule.ListPrice
+		resolved.ScheduleID = &schedule.ID
+		resolved.EndsAt = schedule.EndsAt
+
+		if schedule.SalePrice != nil && *schedule.SalePrice < schedule.ListPrice {
+			resolved.EffectivePrice = *schedule.SalePrice
+			resolved.OnSale = true
+		}
+	}
+
+	return &resolved, nil
+}
+
+func (s *Service) PriceScheduleCreate(ctx context.Context, productID int, input model.NewPriceSchedule) (*model.PriceSchedule, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	valid, err := s.PriceScheduleOnCreate(ctx, productID, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating price schedule")
+	}
+
+	schedule := model.PriceSchedule{
+		ProductID: productID,
+		VariantID: input.VariantID,
+		ListPrice: input.ListPrice,
+		SalePrice: input.SalePrice,
+		StartsAt:  input.StartsAt,
+		EndsAt:    input.EndsAt,
+	}
+	if ctxData != nil {
+		schedule.CreatedBy = &ctxData.ID
+	}
+
+	if err := s.DB.Model(&schedule).Create(&schedule).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.productRecordPriceChange(ctx, model.PriceHistory{
+		ProductID:      productID,
+		VariantID:      schedule.VariantID,
+		ListPrice:      schedule.ListPrice,
+		SalePrice:      schedule.SalePrice,
+		EffectiveFrom:  schedule.StartsAt,
+		EffectiveUntil: schedule.EndsAt,
+		Reason:         string(model.PRICE_CHANGE_REASON_SCHEDULE_CREATED),
+		ScheduleID:     &schedule.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+func (s *Service) PriceScheduleOnCreate(ctx context.Context, productID int, input *model.NewPriceSchedule) (bool, error) {
+	var (
+		now   = time.Now()
+		count int64
+	)
+
+	if input.ListPrice < 0 || (input.SalePrice != nil && *input.SalePrice < 0) {
+		return false, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+	if input.SalePrice != nil && *input.SalePrice > input.ListPrice {
+		return false, fmt.Errorf("invalid input: sale price cannot be higher than list price")
+	}
+
+	if input.StartsAt.IsZero() || input.StartsAt.Before(now) {
+		input.StartsAt = now
+	}
+	if input.EndsAt != nil && !input.EndsAt.After(input.StartsAt) {
+		return false, fmt.Errorf("invalid input: schedule must end after it starts")
+	}
+
+	if input.VariantID > 0 {
+		if _, err := s.ProductVariantGetByID(ctx, productID, input.VariantID); err != nil {
+			return false, err
+		}
+	}
+
+	query := s.DB.Model(&model.PriceSchedule{}).Scopes(tools.IsDeletedAtNull).
+		Where("product_id = ? AND variant_id = ?", productID, input.VariantID).
+		Where("ends_at IS NULL OR ends_at > ?", input.StartsAt)
+	if input.EndsAt != nil {
+		query = query.Where("starts_at < ?", *input.EndsAt)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, fmt.Errorf("price schedule overlaps an existing schedule")
+	}
+
+	return true, nil
+}
+
+// PriceScheduleCancel removes a schedule that has not started yet, or ends a
+// running one immediately. Schedules that already ended are kept as they are.
+func (s *Service) PriceScheduleCancel(ctx context.Context, productID int, scheduleID int) (bool, error) {
+	var (
+		schedule *model.PriceSchedule
+		now      = time.Now()
+	)
+
+	if err := s.DB.Model(&schedule).Scopes(tools.IsDeletedAtNull).Where("id = ? AND product_id = ?", scheduleID, productID).First(&schedule).Error; err == gorm.ErrRecordNotFound {
+		return false, fmt.Errorf("price schedule not found")
+	} else if err != nil {
+		return false, err
+	}
+
+	if schedule.EndsAt != nil && !schedule.EndsAt.After(now) {
+		return false, fmt.Errorf("price schedule has already ended")
+	}
+
+	if schedule.StartsAt.After(now) {
+		if err := s.DB.Model(&model.PriceSchedule{}).Where("id = ?", scheduleID).Update("deleted_at", now).Error; err != nil {
+			return false, err
+		}
+	} else {
+		if err := s.DB.Model(&model.PriceSchedule{}).Where("id = ?", scheduleID).Update("ends_at", now).Error; err != nil {
+			return false, err
+		}
+	}
+
+	resolved, err := s.ProductResolvePrice(ctx, productID, schedule.VariantID, now)
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.productRecordPriceChange(ctx, model.PriceHistory{
+		ProductID:     productID,
+		VariantID:     schedule.VariantID,
+		ListPrice:     resolved.ListPrice,
+		EffectiveFrom: now,
+		Reason:        string(model.PRICE_CHANGE_REASON_SCHEDULE_CANCELLED),
+		ScheduleID:    &schedule.ID,
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) PriceScheduleGetByProductID(ctx context.Context, productID int) ([]*model.PriceSchedule, error) {
+	var schedules []*model.PriceSchedule
+
+	if err := s.DB.Model(&schedules).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Order("starts_at ASC").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	return schedules, nil
+}
+
+func (s *Service) PriceHistoryGetByProductID(ctx context.Context, productID int) ([]*model.PriceHistory, error) {
+	var history []*model.PriceHistory
+
+	if err := s.DB.Model(&history).Where("product_id = ?", productID).Order("id DESC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (s *Service) priceScheduleActive(productID int, variantID int, at time.Time) (*model.PriceSchedule, error) {
+	var schedules []*model.PriceSchedule
+
+	if err := s.DB.Model(&schedules).Scopes(tools.IsDeletedAtNull).
+		Where("product_id = ? AND variant_id = ? AND starts_at <= ?", productID, variantID, at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Order("starts_at DESC").Limit(1).
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	return schedules[0], nil
+}
+
+func (s *Service) productRecordPriceChange(ctx context.Context, history model.PriceHistory) error {
+	if user := middleware.AuthContext(ctx); user != nil {
+		history.ActorID = &user.ID
+	}
+
+	return s.DB.Model(&history).Create(&history).Error
+}
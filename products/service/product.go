@@ -7,6 +7,7 @@ import (
 	"utils/middleware"
 
 	"products/tools"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -21,28 +22,96 @@ func (s *Service) ProductCreate(ctx context.Context, newProd model.NewProduct) (
 		return nil, fmt.Errorf("error creating product")
 	}
 
+	if sku := strings.TrimSpace(newProd.SKU); sku != "" {
+		existing, err := s.ProductGetBySKU(ctx, sku)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("sku %s is already in use", sku)
+		}
+	}
+
 	seller, err := s.GetSellerDetails(newProd.SellerID)
 	if err != nil {
 		return nil, err
 	}
 
+	// products are priced in the seller's base currency at the time they are
+	// listed
+	referencePrice, err := s.productReferencePrice(newProd.Price, seller.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// new products start as drafts and only go on sale once approved
+	status := model.PRODUCT_STATUS_DRAFT
+	if newProd.Submit {
+		status = model.PRODUCT_STATUS_PENDING_REVIEW
+	}
+
 	product := model.Product{
-		Name:        newProd.Name,
-		Description: newProd.Description,
-		Price:       newProd.Price,
-		Stock:       newProd.Stock,
-		SellerID:    newProd.SellerID,
-		ShopName:    seller.BusinessName,
+		Name:             newProd.Name,
+		Description:      newProd.Description,
+		Price:            newProd.Price,
+		Currency:         seller.Currency,
+		ReferencePrice:   referencePrice,
+		Stock:            newProd.Stock,
+		SellerID:         newProd.SellerID,
+		ShopName:         seller.BusinessName,
+		ReorderThreshold: newProd.ReorderThreshold,
+		Status:           string(status),
+		Type:             string(model.PRODUCT_TYPE_SIMPLE),
+	}
+
+	if len(newProd.Components) > 0 {
+		product.Type = string(model.PRODUCT_TYPE_BUNDLE)
+	}
+
+	product.TaxCategory = model.DEFAULT_TAX_CATEGORY
+	if newProd.TaxCategory != "" {
+		if product.TaxCategory, err = normalizeTaxCategory(newProd.TaxCategory); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.DB.Model(&product).Create(&product).Error; err != nil {
 		return nil, err
 	}
 
-	sku := tools.GenerateSKU(&product)
+	if err := s.productRecordStatusChange(ctx, product.ID, "", status, ""); err != nil {
+		return nil, err
+	}
+
+	sku := strings.TrimSpace(newProd.SKU)
+	if sku == "" {
+		sku = tools.GenerateSKU(&product)
+	}
 	product.SKU = &sku
 	s.DB.Save(&product)
 
+	if product.Stock > 0 {
+		if err := s.productRecordMovement(ctx, false, product.Stock, model.StockChange{
+			ProductID: product.ID,
+			Reason:    model.MOVEMENT_REASON_RESTOCK,
+			Note:      "initial stock",
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(newProd.CategoryIDs) > 0 {
+		if _, err := s.ProductAssignCategories(ctx, product.ID, newProd.CategoryIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(newProd.Components) > 0 {
+		if _, err := s.BundleSetComponents(ctx, product.ID, newProd.Components); err != nil {
+			return nil, err
+		}
+	}
+
 	return &product, nil
 }
 
@@ -51,19 +120,62 @@ func (s *Service) ProductOnCreate(ctx context.Context, newProd model.NewProduct)
 		return false, fmt.Errorf("invalid input: fields cannot be empty")
 	}
 
-	if newProd.Price < 0 || newProd.Stock < 0 || newProd.SellerID <= 0 {
+	if newProd.Price < 0 || newProd.Stock < 0 || newProd.SellerID <= 0 || (newProd.ReorderThreshold != nil && *newProd.ReorderThreshold < 0) {
 		return false, fmt.Errorf("invalid input: numerical inputs cannot be negative")
 	}
 
+	if len(newProd.Components) > 0 && newProd.Stock > 0 {
+		return false, fmt.Errorf("invalid input: bundle stock is derived from its components")
+	}
+
+	if newProd.TaxCategory != "" {
+		if _, err := normalizeTaxCategory(newProd.TaxCategory); err != nil {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
 
+// normalizeTaxCategory lower-cases a tax category code. Codes are matched
+// against the tax rules of the orders service, so they are kept to letters,
+// digits and underscores.
+func normalizeTaxCategory(code string) (string, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || len(code) > 30 {
+		return "", fmt.Errorf("invalid input: invalid tax category")
+	}
+
+	for _, r := range code {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return "", fmt.Errorf("invalid input: invalid tax category %s", code)
+		}
+	}
+
+	return code, nil
+}
+
+// ErrProductVersionConflict is returned when a product was changed after the
+// caller read it.
+var ErrProductVersionConflict = fmt.Errorf("product has been modified since it was last read")
+
+// ProductUpdate applies the given fields to a product. The update only goes
+// through while the product is still at prodUpdates.Version, so two sellers
+// editing the same product cannot overwrite each other's changes.
 func (s *Service) ProductUpdate(ctx context.Context, prodUpdates model.UpdateProduct) (*model.Product, error) {
 	var (
 		ctxData = middleware.AuthContext(ctx)
 	)
 
-	valid, err := s.ProductCheckBelongToSeller(ctx, prodUpdates.ID, ctxData.ID)
+	valid, err := s.ProductOnUpdate(ctx, &prodUpdates)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error updating product")
+	}
+
+	valid, err = s.ProductCheckBelongToSeller(ctx, prodUpdates.ID, ctxData.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -71,18 +183,134 @@ func (s *Service) ProductUpdate(ctx context.Context, prodUpdates model.UpdatePro
 		return nil, fmt.Errorf("failed to update product: product does not belong to seller")
 	}
 
-	if err := s.DB.Table("product").Scopes(tools.IsDeletedAtNull).Where("id = ?", prodUpdates.ID).Updates(map[string]interface{}{
-		"name":        prodUpdates.Name,
-		"description": prodUpdates.Description,
-		"price":       prodUpdates.Price,
-		"stock":       prodUpdates.Stock,
-	}).Error; err != nil {
+	if prodUpdates.Stock != nil {
+		hasVariants, err := s.ProductHasVariants(ctx, prodUpdates.ID)
+		if err != nil {
+			return nil, err
+		}
+		if hasVariants {
+			return nil, fmt.Errorf("failed to update product: stock is tracked on the product's variants")
+		}
+	}
+
+	current, err := s.ProductGetByID(ctx, prodUpdates.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"version": gorm.Expr("version + 1"),
+	}
+	if prodUpdates.Name != nil {
+		updates["name"] = *prodUpdates.Name
+	}
+	if prodUpdates.Description != nil {
+		updates["description"] = *prodUpdates.Description
+	}
+	if prodUpdates.Price != nil {
+		referencePrice, err := s.productReferencePrice(*prodUpdates.Price, current.Currency)
+		if err != nil {
+			return nil, err
+		}
+		updates["price"] = *prodUpdates.Price
+		updates["reference_price"] = referencePrice
+	}
+	if prodUpdates.ReorderThreshold != nil {
+		updates["reorder_threshold"] = *prodUpdates.ReorderThreshold
+	}
+	if prodUpdates.TaxCategory != nil {
+		updates["tax_category"] = *prodUpdates.TaxCategory
+	}
+
+	result := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).
+		Where("id = ? AND version = ?", prodUpdates.ID, prodUpdates.Version).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrProductVersionConflict
+	}
+
+	if prodUpdates.Price != nil && *prodUpdates.Price != current.Price {
+		if err := s.productRecordPriceChange(ctx, model.PriceHistory{
+			ProductID:     prodUpdates.ID,
+			ListPrice:     *prodUpdates.Price,
+			EffectiveFrom: time.Now(),
+			Reason:        string(model.PRICE_CHANGE_REASON_MANUAL),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if prodUpdates.Stock != nil {
+		if err := s.productSetStock(ctx, model.StockChange{
+			ProductID: prodUpdates.ID,
+			Reason:    model.MOVEMENT_REASON_MANUAL_ADJUSTMENT,
+		}, *prodUpdates.Stock); err != nil {
+			return nil, err
+		}
+	}
+
+	name, description, price := current.Name, current.Description, current.Price
+	if prodUpdates.Name != nil {
+		name = *prodUpdates.Name
+	}
+	if prodUpdates.Description != nil {
+		description = *prodUpdates.Description
+	}
+	if prodUpdates.Price != nil {
+		price = *prodUpdates.Price
+	}
+
+	if err := s.productResubmitEdited(ctx, current, name, description, price); err != nil {
 		return nil, err
 	}
 
 	return s.ProductGetByID(ctx, prodUpdates.ID)
 }
 
+// ProductReplace is ProductUpdate for callers that must send every editable
+// field rather than only the ones that changed.
+func (s *Service) ProductReplace(ctx context.Context, prodUpdates model.UpdateProduct) (*model.Product, error) {
+	if prodUpdates.Name == nil || prodUpdates.Description == nil || prodUpdates.Price == nil {
+		return nil, fmt.Errorf("invalid input: name, description and price are required")
+	}
+
+	return s.ProductUpdate(ctx, prodUpdates)
+}
+
+func (s *Service) ProductOnUpdate(ctx context.Context, prodUpdates *model.UpdateProduct) (bool, error) {
+	if prodUpdates.Version <= 0 {
+		return false, fmt.Errorf("invalid input: version is required")
+	}
+
+	if prodUpdates.Name != nil {
+		name := strings.TrimSpace(*prodUpdates.Name)
+		prodUpdates.Name = &name
+	}
+	if (prodUpdates.Name != nil && *prodUpdates.Name == "") || (prodUpdates.Description != nil && *prodUpdates.Description == "") {
+		return false, fmt.Errorf("invalid input: fields cannot be empty")
+	}
+
+	if (prodUpdates.Price != nil && *prodUpdates.Price < 0) || (prodUpdates.Stock != nil && *prodUpdates.Stock < 0) ||
+		(prodUpdates.ReorderThreshold != nil && *prodUpdates.ReorderThreshold < 0) {
+		return false, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	if prodUpdates.TaxCategory != nil {
+		taxCategory, err := normalizeTaxCategory(*prodUpdates.TaxCategory)
+		if err != nil {
+			return false, err
+		}
+		prodUpdates.TaxCategory = &taxCategory
+	}
+
+	return true, nil
+}
+
+// ProductDelete hides a product from the catalog. The row itself is kept so
+// orders placed earlier can still resolve it.
 func (s *Service) ProductDelete(ctx context.Context, id int) (string, error) {
 	var (
 		ctxData = middleware.AuthContext(ctx)
@@ -95,14 +323,21 @@ func (s *Service) ProductDelete(ctx context.Context, id int) (string, error) {
 
 	valid, err := s.ProductCheckBelongToSeller(ctx, id, ctxData.ID)
 	if err != nil {
-		return "", err
+		return "failed", err
 	}
 	if !valid {
-		return "", fmt.Errorf("failed to update product: product does not belong to seller")
+		return "failed", fmt.Errorf("failed to delete product: product does not belong to seller")
 	}
 
-	if err := s.DB.Table("product").Where(tools.IsDeletedAtNull).Update("deleted_at = ?", timeNow).Error; err != nil {
-		return "", err
+	if err := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": timeNow,
+		"version":    gorm.Expr("version + 1"),
+	}).Error; err != nil {
+		return "failed", err
+	}
+
+	if err := s.bundleSyncStock(ctx, id); err != nil {
+		return "failed", err
 	}
 
 	return "success", nil
@@ -124,43 +359,140 @@ func (s *Service) ProductCheckBelongToSeller(ctx context.Context, id int, seller
 func (s *Service) ProductGetByID(ctx context.Context, id int) (*model.Product, error) {
 	var product *model.Product
 
-	if err := s.DB.Model(&product).Where("id = ?", id).First(&product).Error; err != nil {
+	if err := s.DB.Model(&product).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).First(&product).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("product not found")
+	} else if err != nil {
 		return nil, err
 	}
 
 	return product, nil
 }
 
-func (s *Service) ProductGetProductsBySellerID(ctx context.Context, id int) ([]*model.Product, error) {
+// ProductGetByIDWithDeleted also returns deleted products, for callers that
+// need to describe what was sold rather than what is for sale.
+func (s *Service) ProductGetByIDWithDeleted(ctx context.Context, id int) (*model.Product, error) {
+	var product *model.Product
+
+	if err := s.DB.Model(&product).Where("id = ?", id).First(&product).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("product not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// ProductGetBySKU returns the live product with the given sku, or nil when
+// there is none.
+func (s *Service) ProductGetBySKU(ctx context.Context, sku string) (*model.Product, error) {
 	var products []*model.Product
 
-	if err := s.DB.Model(&products).Where("id = ?", id).Find(&products).Error; err != nil {
+	if err := s.DB.Model(&products).Scopes(tools.IsDeletedAtNull).Where("sku = ?", sku).Limit(1).Find(&products).Error; err != nil {
 		return nil, err
 	}
 
-	return products, nil
+	if len(products) == 0 {
+		return nil, nil
+	}
+
+	return products[0], nil
 }
 
-func (s *Service) ProductGetWithFilter(ctx context.Context, filter string) ([]*model.Product, error) {
+func (s *Service) ProductGetProductsBySellerID(ctx context.Context, id int) ([]*model.Product, error) {
 	var products []*model.Product
 
-	if err := s.DB.Model(&products).Where("name LIKE %?%", filter).Find(&products).Error; err != nil {
+	if err := s.DB.Model(&products).Scopes(tools.IsDeletedAtNull).Where("seller_id = ?", id).Find(&products).Error; err != nil {
 		return nil, err
 	}
 
 	return products, nil
 }
 
-func (s *Service) ProductUpdateStock(ctx context.Context, id int, qty int) (bool, error) {
-	var product *model.Product
+func (s *Service) ProductUpdateStock(ctx context.Context, id int, variantID int, qty int, orderID int) ([]*model.StockAllocation, error) {
+	if qty <= 0 {
+		return nil, fmt.Errorf("invalid input: quantity must be positive")
+	}
 
-	result := s.DB.Model(&product).Where("id = ?", id).Update("stock", gorm.Expr("stock - ?", qty))
-	if result.Error != nil {
-		return false, result.Error
+	change := model.StockChange{
+		ProductID: id,
+		VariantID: variantID,
+		Quantity:  qty,
+		Reason:    model.MOVEMENT_REASON_SALE,
 	}
-	if result.RowsAffected == 0 {
-		return false, fmt.Errorf("insufficient stock or stock not found")
+	if orderID > 0 {
+		change.OrderID = &orderID
 	}
 
-	return true, nil
+	allocations, err := s.productDecrementStock(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.productAddSoldCount(ctx, id, variantID, qty); err != nil {
+		return nil, err
+	}
+
+	return allocations, nil
+}
+
+func (s *Service) ProductGetDetail(ctx context.Context, id int) (*model.ProductDetail, error) {
+	product, err := s.ProductGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// products that are not on sale are only shown to their seller
+	if product.Status != string(model.PRODUCT_STATUS_ACTIVE) {
+		if user := middleware.AuthContext(ctx); user == nil || user.ID != product.SellerID {
+			return nil, fmt.Errorf("product not found")
+		}
+	}
+
+	categories, err := s.ProductGetCategories(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := s.ProductImageGetByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.ProductOptionGetByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.ProductVariantGetByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, err := s.ProductResolvePrice(ctx, id, 0, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := s.ProductGetAttributes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var components []*model.BundleComponent
+	if product.Type == string(model.PRODUCT_TYPE_BUNDLE) {
+		if components, err = s.BundleComponentGetByBundleID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	return &model.ProductDetail{
+		Product:    product,
+		Categories: categories,
+		Images:     images,
+		Options:    options,
+		Variants:   variants,
+		Pricing:    pricing,
+		Attributes: attributes,
+		Components: components,
+	}, nil
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"products/model"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// affinityHistoryDays is how far back completed orders are read.
+	affinityHistoryDays = 365
+	// minCoPurchaseOrders is how many orders must contain both products before
+	// the pair is trusted.
+	minCoPurchaseOrders = 2
+	// maxAffinitiesPerProduct caps how many related products are kept for each
+	// product.
+	maxAffinitiesPerProduct = 20
+
+	defaultRecommendationLimit = 8
+	maxRecommendationLimit     = 20
+)
+
+func StartAffinityBuilder(interval time.Duration) {
+	RebuildProductAffinities(context.Background())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		RebuildProductAffinities(context.Background())
+	}
+}
+
+// RebuildProductAffinities replaces every co-purchase score with ones computed
+// from the completed orders of the history window.
+func RebuildProductAffinities(ctx context.Context) {
+	pairs, productOrders, err := GetCoPurchases(ctx, time.Now().AddDate(0, 0, -affinityHistoryDays))
+	if err != nil {
+		log.Println("failed to load co-purchases:", err)
+		return
+	}
+
+	var (
+		now        = time.Now()
+		byProduct  = make(map[int][]*model.ProductAffinity)
+		affinities []*model.ProductAffinity
+	)
+
+	for _, pair := range pairs {
+		if pair.Orders < minCoPurchaseOrders {
+			continue
+		}
+
+		var (
+			productID = int(pair.ProductId)
+			relatedID = int(pair.RelatedProductId)
+			total     = float64(productOrders[productID]) * float64(productOrders[relatedID])
+		)
+		if total == 0 {
+			continue
+		}
+
+		byProduct[productID] = append(byProduct[productID], &model.ProductAffinity{
+			ProductID:        productID,
+			RelatedProductID: relatedID,
+			Orders:           int(pair.Orders),
+			Score:            math.Round(float64(pair.Orders)/math.Sqrt(total)*10000) / 10000,
+			ComputedAt:       now,
+		})
+	}
+
+	for _, related := range byProduct {
+		slices.SortFunc(related, func(a, b *model.ProductAffinity) int {
+			if a.Score != b.Score {
+				if a.Score > b.Score {
+					return -1
+				}
+				return 1
+			}
+			return b.Orders - a.Orders
+		})
+		affinities = append(affinities, related[:min(len(related), maxAffinitiesPerProduct)]...)
+	}
+
+	tx := GetTransaction()
+	if err := tx.productAffinityReplace(affinities); err != nil {
+		tx.DB.Rollback()
+		log.Println("failed to save product affinities:", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Println("failed to save product affinities:", err)
+	}
+}
+
+func (s *Service) productAffinityReplace(affinities []*model.ProductAffinity) error {
+	if err := s.DB.Where("1 = 1").Delete(&model.ProductAffinity{}).Error; err != nil {
+		return err
+	}
+
+	if len(affinities) == 0 {
+		return nil
+	}
+
+	return s.DB.CreateInBatches(affinities, 500).Error
+}
+
+// ProductGetRelated returns the products most often bought together with a
+// product. When there are not enough of those yet, the list is filled with
+// popular products from the same categories and then from the same seller.
+// Only products that are on sale and in stock are recommended.
+func (s *Service) ProductGetRelated(ctx context.Context, productID int, limit int) ([]*model.RelatedProduct, error) {
+	if limit <= 0 {
+		limit = defaultRecommendationLimit
+	} else if limit > maxRecommendationLimit {
+		limit = maxRecommendationLimit
+	}
+
+	product, err := s.ProductGetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		scores []struct {
+			ID    int
+			Score float64
+		}
+		related = []*model.RelatedProduct{}
+		exclude = []int{productID}
+	)
+
+	if err := s.DB.Model(&model.ProductAffinity{}).Select("product.id, product_affinity.score").
+		Joins("JOIN product ON product.id = product_affinity.related_product_id").
+		Where("product_affinity.product_id = ?", productID).
+		Scopes(recommendable).
+		Order("product_affinity.score DESC").Order("product.id ASC").
+		Limit(limit).
+		Scan(&scores).Error; err != nil {
+		return nil, err
+	}
+
+	if len(scores) > 0 {
+		ids := make([]int, 0, len(scores))
+		for _, score := range scores {
+			ids = append(ids, score.ID)
+		}
+
+		var products []*model.Product
+		if err := s.DB.Model(&products).Where("id IN (?)", ids).Find(&products).Error; err != nil {
+			return nil, err
+		}
+
+		byID := make(map[int]*model.Product, len(products))
+		for _, product := range products {
+			byID[product.ID] = product
+		}
+
+		for _, score := range scores {
+			related = append(related, &model.RelatedProduct{
+				Product: byID[score.ID],
+				Source:  model.RECOMMENDATION_SOURCE_CO_PURCHASE,
+				Score:   score.Score,
+			})
+			exclude = append(exclude, score.ID)
+		}
+	}
+
+	fallbacks := []struct {
+		source model.RecommendationSource
+		scope  func(*gorm.DB) *gorm.DB
+	}{
+		{model.RECOMMENDATION_SOURCE_CATEGORY, func(query *gorm.DB) *gorm.DB {
+			return query.Where("product.id IN (?)", s.DB.Model(&model.ProductCategory{}).Select("product_id").
+				Where("category_id IN (?)", s.DB.Model(&model.ProductCategory{}).Select("category_id").Where("product_id = ?", productID)))
+		}},
+		{model.RECOMMENDATION_SOURCE_SELLER, func(query *gorm.DB) *gorm.DB {
+			return query.Where("product.seller_id = ?", product.SellerID)
+		}},
+	}
+
+	for _, fallback := range fallbacks {
+		if len(related) >= limit {
+			break
+		}
+
+		var products []*model.Product
+		if err := s.DB.Model(&products).Scopes(recommendable, fallback.scope).
+			Where("product.id NOT IN (?)", exclude).
+			Order("product.sold_count DESC").Order("product.rating_average DESC").Order("product.id DESC").
+			Limit(limit - len(related)).
+			Find(&products).Error; err != nil {
+			return nil, err
+		}
+
+		for _, product := range products {
+			related = append(related, &model.RelatedProduct{Product: product, Source: fallback.source})
+			exclude = append(exclude, product.ID)
+		}
+	}
+
+	return related, nil
+}
+
+// recommendable keeps the products that can be recommended: on sale, not
+// deleted and in stock.
+func recommendable(query *gorm.DB) *gorm.DB {
+	return query.Where("product.deleted_at IS NULL AND product.status = ? AND product.stock > 0", model.PRODUCT_STATUS_ACTIVE)
+}
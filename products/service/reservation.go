@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"products/model"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultReservationTTL = 15 * time.Minute
+	maxReservationTTL     = 2 * time.Hour
+)
+
+// ReservationCreate holds stock for every item or for none of them. It must be
+// run inside a transaction so a failure on a later item undoes earlier ones.
+func (s *Service) ReservationCreate(ctx context.Context, items []model.ReserveItem, ttl time.Duration) (*model.StockReservation, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to reserve")
+	}
+
+	if ttl <= 0 {
+		ttl = defaultReservationTTL
+	} else if ttl > maxReservationTTL {
+		ttl = maxReservationTTL
+	}
+
+	reservation := model.StockReservation{
+		Status:    string(model.RESERVATION_STATUS_ACTIVE),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	if err := s.DB.Model(&reservation).Create(&reservation).Error; err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ProductID <= 0 || item.VariantID < 0 || item.Quantity <= 0 {
+			return nil, fmt.Errorf("invalid reservation item for product %d", item.ProductID)
+		}
+
+		reservationItem := model.StockReservationItem{
+			ReservationID: reservation.ID,
+			ProductID:     item.ProductID,
+			VariantID:     item.VariantID,
+			Quantity:      item.Quantity,
+		}
+
+		if err := s.DB.Model(&reservationItem).Create(&reservationItem).Error; err != nil {
+			return nil, err
+		}
+
+		allocations, err := s.productDecrementStock(ctx, model.StockChange{
+			ProductID:         item.ProductID,
+			VariantID:         item.VariantID,
+			Quantity:          item.Quantity,
+			Reason:            model.MOVEMENT_REASON_SALE,
+			ReservationID:     &reservation.ID,
+			ReservationItemID: &reservationItem.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve product %d: %w", item.ProductID, err)
+		}
+
+		reservationItem.Allocations = allocations
+		reservation.Items = append(reservation.Items, &reservationItem)
+	}
+
+	return &reservation, nil
+}
+
+// ReservationCommit turns held stock into a sale for the given order. The
+// stock itself was already taken, and recorded in the ledger, when the
+// reservation was made.
+func (s *Service) ReservationCommit(ctx context.Context, id int, orderID int) (bool, error) {
+	reservation, err := s.reservationLock(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	// a retried commit for the same order has nothing left to do
+	if reservation.Status == string(model.RESERVATION_STATUS_COMMITTED) && reservation.OrderID != nil && *reservation.OrderID == orderID {
+		return true, nil
+	}
+	if reservation.Status != string(model.RESERVATION_STATUS_ACTIVE) {
+		return false, fmt.Errorf("reservation is already %s", reservation.Status)
+	}
+
+	if orderID > 0 {
+		if err := s.DB.Model(&model.StockReservation{}).Where("id = ?", reservation.ID).Update("order_id", orderID).Error; err != nil {
+			return false, err
+		}
+
+		if err := s.DB.Model(&model.StockAllocation{}).
+			Where("reservation_item_id IN (SELECT id FROM stock_reservation_item WHERE reservation_id = ?)", reservation.ID).
+			Update("order_id", orderID).Error; err != nil {
+			return false, err
+		}
+	}
+
+	for _, item := range reservation.Items {
+		if err := s.productAddSoldCount(ctx, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.reservationSetStatus(reservation.ID, model.RESERVATION_STATUS_COMMITTED); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) ReservationRelease(ctx context.Context, id int) (bool, error) {
+	return s.reservationRestore(ctx, id, model.RESERVATION_STATUS_RELEASED, model.MOVEMENT_REASON_RESERVATION_RELEASE)
+}
+
+func (s *Service) ReservationExpire(ctx context.Context, id int) (bool, error) {
+	return s.reservationRestore(ctx, id, model.RESERVATION_STATUS_EXPIRED, model.MOVEMENT_REASON_RESERVATION_EXPIRY)
+}
+
+func (s *Service) ReservationGetExpiredIDs(ctx context.Context, now time.Time) ([]int, error) {
+	var ids []int
+
+	if err := s.DB.Model(&model.StockReservation{}).
+		Where("status = ? AND expires_at <= ?", model.RESERVATION_STATUS_ACTIVE, now).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// reservationRestore gives the held stock back. A reservation that was
+// already released or expired has given its stock back, so it is left as is.
+func (s *Service) reservationRestore(ctx context.Context, id int, status model.ReservationStatus, reason model.MovementReason) (bool, error) {
+	reservation, err := s.reservationLock(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	switch model.ReservationStatus(reservation.Status) {
+	case model.RESERVATION_STATUS_RELEASED, model.RESERVATION_STATUS_EXPIRED:
+		return false, nil
+	case model.RESERVATION_STATUS_ACTIVE:
+	default:
+		return false, fmt.Errorf("reservation is already %s", reservation.Status)
+	}
+
+	for _, item := range reservation.Items {
+		if err := s.productIncrementStock(ctx, model.StockChange{
+			ProductID:         item.ProductID,
+			VariantID:         item.VariantID,
+			Quantity:          item.Quantity,
+			Reason:            reason,
+			ReservationID:     &reservation.ID,
+			ReservationItemID: &item.ID,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.reservationSetStatus(reservation.ID, status); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// reservationLock loads a reservation and locks its row so that a commit
+// racing with the sweeper can only be applied once.
+func (s *Service) reservationLock(ctx context.Context, id int) (*model.StockReservation, error) {
+	var reservation *model.StockReservation
+
+	if err := s.DB.Model(&reservation).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("reservation not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.StockReservationItem{}).Where("reservation_id = ?", reservation.ID).Find(&reservation.Items).Error; err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+func (s *Service) reservationSetStatus(id int, status model.ReservationStatus) error {
+	return s.DB.Model(&model.StockReservation{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// StartReservationSweeper releases reservations that were never committed
+// once their ttl has passed. It blocks, so run it in its own goroutine.
+func StartReservationSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		SweepExpiredReservations(context.Background())
+	}
+}
+
+func SweepExpiredReservations(ctx context.Context) {
+	ids, err := GetService().ReservationGetExpiredIDs(ctx, time.Now())
+	if err != nil {
+		log.Println("failed to load expired reservations:", err)
+		return
+	}
+
+	for _, id := range ids {
+		tx := GetTransaction()
+		if _, err := tx.ReservationExpire(ctx, id); err != nil {
+			tx.DB.Rollback()
+			log.Printf("failed to expire reservation %d: %v", id, err)
+			continue
+		}
+		if err := tx.Commit(); err != nil {
+			log.Printf("failed to expire reservation %d: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"strings"
+	"time"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) ReviewCreate(ctx context.Context, productID int, input model.NewReview) (*model.Review, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	valid, err := s.ReviewOnCreate(ctx, productID, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating review")
+	}
+
+	orderID, err := VerifyPurchase(ctx, ctxData.ID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if orderID == 0 {
+		return nil, fmt.Errorf("only buyers with a completed order for this product can review it")
+	}
+
+	live := true
+	review := model.Review{
+		ProductID: productID,
+		UserID:    ctxData.ID,
+		OrderID:   orderID,
+		Rating:    input.Rating,
+		Title:     input.Title,
+		Body:      input.Body,
+		Live:      &live,
+	}
+
+	if err := s.DB.Model(&review).Create(&review).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.productRefreshRating(productID); err != nil {
+		return nil, err
+	}
+
+	return &review, nil
+}
+
+func (s *Service) ReviewOnCreate(ctx context.Context, productID int, input *model.NewReview) (bool, error) {
+	var (
+		ctxData = middleware.AuthContext(ctx)
+		count   int64
+	)
+
+	input.Title = strings.TrimSpace(input.Title)
+	input.Body = strings.TrimSpace(input.Body)
+
+	if err := validateReview(input.Rating, input.Title); err != nil {
+		return false, err
+	}
+
+	if _, err := s.ProductGetByID(ctx, productID); err != nil {
+		return false, fmt.Errorf("product not found")
+	}
+
+	if err := s.DB.Model(&model.Review{}).Scopes(tools.IsDeletedAtNull).Where("product_id = ? AND user_id = ?", productID, ctxData.ID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, fmt.Errorf("product has already been reviewed by this user")
+	}
+
+	return true, nil
+}
+
+func (s *Service) ReviewUpdate(ctx context.Context, input model.UpdateReview) (*model.Review, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	review, err := s.ReviewGetByID(ctx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != ctxData.ID {
+		return nil, fmt.Errorf("failed to update review: review does not belong to user")
+	}
+
+	updates := map[string]interface{}{}
+	if input.Rating != nil {
+		review.Rating = *input.Rating
+		updates["rating"] = *input.Rating
+	}
+	if input.Title != nil {
+		review.Title = strings.TrimSpace(*input.Title)
+		updates["title"] = review.Title
+	}
+	if input.Body != nil {
+		review.Body = strings.TrimSpace(*input.Body)
+		updates["body"] = review.Body
+	}
+
+	if err := validateReview(review.Rating, review.Title); err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := s.DB.Model(&model.Review{}).Where("id = ?", review.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.productRefreshRating(review.ProductID); err != nil {
+		return nil, err
+	}
+
+	return s.ReviewGetByID(ctx, review.ID)
+}
+
+func (s *Service) ReviewDelete(ctx context.Context, id int) (string, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	review, err := s.ReviewGetByID(ctx, id)
+	if err != nil {
+		return "failed", err
+	}
+	if review.UserID != ctxData.ID {
+		return "failed", fmt.Errorf("failed to delete review: review does not belong to user")
+	}
+
+	// clearing live lets the buyer review the product again
+	if err := s.DB.Model(&model.Review{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"live":       nil,
+	}).Error; err != nil {
+		return "failed", err
+	}
+
+	if err := s.productRefreshRating(review.ProductID); err != nil {
+		return "failed", err
+	}
+
+	return "success", nil
+}
+
+func (s *Service) ReviewReply(ctx context.Context, id int, sellerID int, reply string) (*model.Review, error) {
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return nil, fmt.Errorf("invalid input: reply cannot be empty")
+	}
+
+	review, err := s.ReviewGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	valid, err := s.ProductCheckBelongToSeller(ctx, review.ProductID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("failed to reply to review: product does not belong to seller")
+	}
+
+	if err := s.DB.Model(&model.Review{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"seller_reply":      reply,
+		"seller_replied_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.ReviewGetByID(ctx, id)
+}
+
+func (s *Service) ReviewFlag(ctx context.Context, id int, reason string) (bool, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return false, fmt.Errorf("invalid input: a reason is required to flag a review")
+	}
+
+	if _, err := s.ReviewGetByID(ctx, id); err != nil {
+		return false, err
+	}
+
+	if err := s.DB.Model(&model.Review{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_flagged":  true,
+		"flag_reason": reason,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ReviewModerate resolves a flagged review. Approving keeps it visible, hiding
+// removes it from listings and from the product's rating.
+func (s *Service) ReviewModerate(ctx context.Context, id int, action model.ReviewModerationAction) (*model.Review, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	var hidden bool
+	switch action {
+	case model.REVIEW_MODERATION_APPROVE:
+		hidden = false
+	case model.REVIEW_MODERATION_HIDE:
+		hidden = true
+	default:
+		return nil, fmt.Errorf("invalid input: unsupported moderation action %s", action)
+	}
+
+	review, err := s.ReviewGetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(&model.Review{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_flagged":   false,
+		"is_hidden":    hidden,
+		"moderated_by": ctxData.ID,
+		"moderated_at": time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.productRefreshRating(review.ProductID); err != nil {
+		return nil, err
+	}
+
+	return s.ReviewGetByID(ctx, id)
+}
+
+func (s *Service) ReviewGetByID(ctx context.Context, id int) (*model.Review, error) {
+	var review *model.Review
+
+	if err := s.DB.Model(&review).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).First(&review).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("review not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (s *Service) ReviewGetByProductID(ctx context.Context, productID int) ([]*model.Review, error) {
+	var reviews []*model.Review
+
+	if err := s.DB.Model(&reviews).Scopes(tools.IsDeletedAtNull).Where("product_id = ? AND is_hidden = ?", productID, false).Order("id DESC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+func (s *Service) ReviewGetFlagged(ctx context.Context) ([]*model.Review, error) {
+	var reviews []*model.Review
+
+	if err := s.DB.Model(&reviews).Scopes(tools.IsDeletedAtNull).Where("is_flagged = ?", true).Order("id ASC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// productRefreshRating recomputes the cached rating columns on the product
+// from its visible reviews.
+func (s *Service) productRefreshRating(productID int) error {
+	var aggregate struct {
+		Average float64
+		Count   int
+	}
+
+	if err := s.DB.Model(&model.Review{}).Scopes(tools.IsDeletedAtNull).
+		Where("product_id = ? AND is_hidden = ?", productID, false).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Scan(&aggregate).Error; err != nil {
+		return err
+	}
+
+	return s.DB.Model(&model.Product{}).Where("id = ?", productID).Updates(map[string]interface{}{
+		"rating_average": aggregate.Average,
+		"rating_count":   aggregate.Count,
+	}).Error
+}
+
+func validateReview(rating int, title string) error {
+	if rating < 1 || rating > 5 {
+		return fmt.Errorf("invalid input: rating must be between 1 and 5")
+	}
+
+	if len(title) > 150 {
+		return fmt.Errorf("invalid input: title cannot be longer than 150 characters")
+	}
+
+	return nil
+}
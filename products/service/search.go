@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"utils/currency"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	// maxFacetValues caps how many values are counted for each attribute
+	maxFacetValues = 50
+)
+
+// ErrInvalidSearch is wrapped by every error a search returns for a filter
+// the caller got wrong, as opposed to one the search failed on.
+var ErrInvalidSearch = fmt.Errorf("invalid search")
+
+// searchCursor holds the sort key and id of the last product on a page so the
+// next page can continue from it without using offsets. Sort is the order the
+// page was listed in, since the key means nothing under another one.
+type searchCursor struct {
+	Sort      model.ProductSort `json:"o"`
+	Price     float64           `json:"p,omitempty"`
+	SoldCount int               `json:"s,omitempty"`
+	CreatedAt time.Time         `json:"c,omitempty"`
+	ID        int               `json:"id"`
+}
+
+func (s *Service) ProductSearch(ctx context.Context, filter model.ProductSearchFilter) ([]*model.Product, string, error) {
+	var products []*model.Product
+
+	valid, err := s.ProductOnSearch(ctx, &filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if !valid {
+		return nil, "", ErrInvalidSearch
+	}
+
+	query, err := s.productSearchQuery(ctx, filter, "")
+	if err != nil {
+		return nil, "", err
+	}
+
+	if filter.Cursor != "" {
+		cursor, err := decodeSearchCursor(filter.Cursor, filter.Sort)
+		if err != nil {
+			return nil, "", err
+		}
+		query = applySearchCursor(query, filter.Sort, cursor)
+	}
+
+	query = applySearchSort(query, filter.Sort)
+
+	// fetch one extra row to know whether there is a next page
+	if err := query.Limit(filter.Limit + 1).Find(&products).Error; err != nil {
+		return nil, "", err
+	}
+
+	if len(products) <= filter.Limit {
+		return products, "", nil
+	}
+
+	products = products[:filter.Limit]
+	last := products[len(products)-1]
+
+	nextCursor, err := encodeSearchCursor(searchCursor{
+		Sort:      filter.Sort,
+		Price:     last.ReferencePrice,
+		SoldCount: last.SoldCount,
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return products, nextCursor, nil
+}
+
+// ProductSearchFacets counts the matching products for each value of every
+// filterable attribute. The count for an attribute that is being filtered on
+// ignores its own filter, so the other values it could be widened to are
+// still counted.
+func (s *Service) ProductSearchFacets(ctx context.Context, filter model.ProductSearchFilter) ([]*model.AttributeFacet, error) {
+	valid, err := s.ProductOnSearch(ctx, &filter)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidSearch
+	}
+
+	var (
+		facets   = []*model.AttributeFacet{}
+		filtered = make([]string, 0, len(filter.Attributes))
+	)
+
+	for code := range filter.Attributes {
+		filtered = append(filtered, code)
+	}
+
+	query, err := s.productSearchQuery(ctx, filter, "")
+	if err != nil {
+		return nil, err
+	}
+
+	facetQuery := s.attributeFacetQuery(query)
+	if len(filtered) > 0 {
+		facetQuery = facetQuery.Where("category_attribute.code NOT IN (?)", filtered)
+	}
+
+	rows, err := scanAttributeFacets(facetQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code := range filtered {
+		query, err := s.productSearchQuery(ctx, filter, code)
+		if err != nil {
+			return nil, err
+		}
+
+		codeRows, err := scanAttributeFacets(s.attributeFacetQuery(query).Where("category_attribute.code = ?", code))
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, codeRows...)
+	}
+
+	for _, row := range rows {
+		if len(facets) == 0 || facets[len(facets)-1].Code != row.Code {
+			facets = append(facets, &model.AttributeFacet{Code: row.Code, Name: row.Name, Unit: row.Unit})
+		}
+
+		facet := facets[len(facets)-1]
+		if len(facet.Values) < maxFacetValues {
+			facet.Values = append(facet.Values, &model.AttributeFacetValue{Value: row.Value, Count: row.Count})
+		}
+	}
+
+	slices.SortFunc(facets, func(a, b *model.AttributeFacet) int {
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	return facets, nil
+}
+
+func (s *Service) ProductOnSearch(ctx context.Context, filter *model.ProductSearchFilter) (bool, error) {
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0) {
+		return false, fmt.Errorf("%w: price range cannot be negative", ErrInvalidSearch)
+	}
+
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return false, fmt.Errorf("%w: min price cannot be greater than max price", ErrInvalidSearch)
+	}
+
+	switch filter.Sort {
+	case "":
+		filter.Sort = model.PRODUCT_SORT_NEWEST
+	case model.PRODUCT_SORT_PRICE_ASC, model.PRODUCT_SORT_PRICE_DESC, model.PRODUCT_SORT_NEWEST, model.PRODUCT_SORT_BEST_SELLING:
+	default:
+		return false, fmt.Errorf("%w: unknown sort option %s", ErrInvalidSearch, filter.Sort)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	} else if filter.Limit > maxSearchLimit {
+		filter.Limit = maxSearchLimit
+	}
+
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	if filter.Currency != "" {
+		code, err := currency.Normalize(filter.Currency)
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+		}
+		filter.Currency = code
+	}
+
+	for code, value := range filter.Attributes {
+		if value = strings.TrimSpace(value); value == "" {
+			delete(filter.Attributes, code)
+			continue
+		}
+		filter.Attributes[code] = value
+	}
+
+	return true, nil
+}
+
+// productSearchQuery applies every search filter except the cursor. The
+// attribute filter named by skipAttribute is left out, for facet counts.
+func (s *Service) productSearchQuery(ctx context.Context, filter model.ProductSearchFilter, skipAttribute string) (*gorm.DB, error) {
+	query := s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).Where("status = ?", model.PRODUCT_STATUS_ACTIVE)
+
+	if filter.Query != "" {
+		keyword := "%" + escapeLike(filter.Query) + "%"
+		query = query.Where("(name LIKE ? OR description LIKE ?)", keyword, keyword)
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		// products are priced in many currencies, so the bounds are compared
+		// with prices converted to the reference currency
+		rate := 1.0
+		if filter.Currency != "" {
+			var err error
+			if rate, err = s.exchangeRate(filter.Currency); errors.Is(err, ErrNoExchangeRate) {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+			} else if err != nil {
+				return nil, err
+			}
+		}
+
+		if filter.MinPrice != nil {
+			query = query.Where("reference_price >= ?", *filter.MinPrice/rate)
+		}
+		if filter.MaxPrice != nil {
+			query = query.Where("reference_price <= ?", *filter.MaxPrice/rate)
+		}
+	}
+	if filter.SellerID != nil {
+		query = query.Where("seller_id = ?", *filter.SellerID)
+	}
+	if filter.CategoryID != nil {
+		categoryIDs, err := s.CategoryGetDescendantIDs(ctx, *filter.CategoryID)
+		if errors.Is(err, ErrCategoryNotFound) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSearch, err)
+		} else if err != nil {
+			return nil, err
+		}
+		query = query.Where("id IN (?)", s.DB.Model(&model.ProductCategory{}).Select("product_id").Where("category_id IN (?)", categoryIDs))
+	}
+	if filter.InStockOnly {
+		query = query.Where("stock > 0")
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where("created_at > ?", *filter.CreatedAfter)
+	}
+
+	for code, raw := range filter.Attributes {
+		if code == skipAttribute {
+			continue
+		}
+
+		matching := s.DB.Model(&model.ProductAttribute{}).Select("product_attribute.product_id").
+			Joins("JOIN category_attribute ON category_attribute.id = product_attribute.attribute_id").
+			Where("category_attribute.code = ? AND category_attribute.deleted_at IS NULL", code)
+
+		if min, max, ok, err := parseAttributeRange(raw); err != nil {
+			return nil, err
+		} else if ok {
+			if min != nil {
+				matching = matching.Where("product_attribute.number_value >= ?", *min)
+			}
+			if max != nil {
+				matching = matching.Where("product_attribute.number_value <= ?", *max)
+			}
+		} else {
+			matching = matching.Where("product_attribute.value IN (?)", strings.Split(raw, ","))
+		}
+
+		query = query.Where("id IN (?)", matching)
+	}
+
+	return query, nil
+}
+
+type attributeFacetRow struct {
+	Code  string
+	Name  string
+	Unit  string
+	Value string
+	Count int
+}
+
+func (s *Service) attributeFacetQuery(products *gorm.DB) *gorm.DB {
+	return s.DB.Model(&model.ProductAttribute{}).
+		Select("category_attribute.code, MIN(category_attribute.name) AS name, MIN(category_attribute.unit) AS unit, product_attribute.value, COUNT(DISTINCT product_attribute.product_id) AS count").
+		Joins("JOIN category_attribute ON category_attribute.id = product_attribute.attribute_id").
+		Where("category_attribute.deleted_at IS NULL AND category_attribute.filterable = ?", true).
+		Where("product_attribute.product_id IN (?)", products.Select("id")).
+		Group("category_attribute.code").Group("product_attribute.value").
+		Order("category_attribute.code ASC").Order("count DESC").Order("product_attribute.value ASC")
+}
+
+func scanAttributeFacets(query *gorm.DB) ([]*attributeFacetRow, error) {
+	var rows []*attributeFacetRow
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// parseAttributeRange reads a number range filter written as min..max, where
+// either bound may be left out. ok is false when the filter is not a range.
+func parseAttributeRange(raw string) (min *float64, max *float64, ok bool, err error) {
+	lower, upper, found := strings.Cut(raw, "..")
+	if !found {
+		return nil, nil, false, nil
+	}
+
+	if lower = strings.TrimSpace(lower); lower != "" {
+		value, err := strconv.ParseFloat(lower, 64)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("%w: bad attribute range %s", ErrInvalidSearch, raw)
+		}
+		min = &value
+	}
+	if upper = strings.TrimSpace(upper); upper != "" {
+		value, err := strconv.ParseFloat(upper, 64)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("%w: bad attribute range %s", ErrInvalidSearch, raw)
+		}
+		max = &value
+	}
+
+	return min, max, true, nil
+}
+
+func applySearchSort(query *gorm.DB, sort model.ProductSort) *gorm.DB {
+	switch sort {
+	case model.PRODUCT_SORT_PRICE_ASC:
+		return query.Order("reference_price ASC").Order("id ASC")
+	case model.PRODUCT_SORT_PRICE_DESC:
+		return query.Order("reference_price DESC").Order("id DESC")
+	case model.PRODUCT_SORT_BEST_SELLING:
+		return query.Order("sold_count DESC").Order("id DESC")
+	default:
+		return query.Order("created_at DESC").Order("id DESC")
+	}
+}
+
+func applySearchCursor(query *gorm.DB, sort model.ProductSort, cursor *searchCursor) *gorm.DB {
+	switch sort {
+	case model.PRODUCT_SORT_PRICE_ASC:
+		return query.Where("(reference_price > ? OR (reference_price = ? AND id > ?))", cursor.Price, cursor.Price, cursor.ID)
+	case model.PRODUCT_SORT_PRICE_DESC:
+		return query.Where("(reference_price < ? OR (reference_price = ? AND id < ?))", cursor.Price, cursor.Price, cursor.ID)
+	case model.PRODUCT_SORT_BEST_SELLING:
+		return query.Where("(sold_count < ? OR (sold_count = ? AND id < ?))", cursor.SoldCount, cursor.SoldCount, cursor.ID)
+	default:
+		return query.Where("(created_at < ? OR (created_at = ? AND id < ?))", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+}
+
+func encodeSearchCursor(cursor searchCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// decodeSearchCursor reads a cursor back, rejecting one from a search listed
+// in another order.
+func decodeSearchCursor(raw string, sort model.ProductSort) (*searchCursor, error) {
+	var cursor searchCursor
+
+	data, err := base64.URLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", ErrInvalidSearch)
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: bad cursor", ErrInvalidSearch)
+	}
+
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("%w: the cursor was issued for sort %s, not %s", ErrInvalidSearch, cursor.Sort, sort)
+	}
+
+	return &cursor, nil
+}
+
+func escapeLike(keyword string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(keyword)
+}
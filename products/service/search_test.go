@@ -0,0 +1,37 @@
+package service
+
+import (
+	"products/model"
+	"testing"
+)
+
+func TestSearchCursorSort(t *testing.T) {
+	raw, err := encodeSearchCursor(searchCursor{Sort: model.PRODUCT_SORT_PRICE_ASC, Price: 9.99, ID: 42})
+	if err != nil {
+		t.Fatalf("encodeSearchCursor() error = %v", err)
+	}
+
+	tests := []struct {
+		sort    model.ProductSort
+		wantErr bool
+	}{
+		{model.PRODUCT_SORT_PRICE_ASC, false},
+		{model.PRODUCT_SORT_PRICE_DESC, true},
+		{model.PRODUCT_SORT_NEWEST, true},
+	}
+
+	for _, tt := range tests {
+		cursor, err := decodeSearchCursor(raw, tt.sort)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("decodeSearchCursor(%s) error = %v, wantErr %v", tt.sort, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (cursor.Price != 9.99 || cursor.ID != 42) {
+			t.Errorf("decodeSearchCursor(%s) = %+v, want price 9.99 and id 42", tt.sort, cursor)
+		}
+	}
+
+	if _, err := decodeSearchCursor("not a cursor", model.PRODUCT_SORT_PRICE_ASC); err == nil {
+		t.Error("decodeSearchCursor() of garbage succeeded, want an error")
+	}
+}
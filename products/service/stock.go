@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"utils/middleware"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productDecrementStock removes change.Quantity units from the product, or
+// from the variant when the product has variants. The update only matches rows
+// that still hold enough stock, so concurrent callers can never drive stock
+// below zero. For stock held per warehouse it returns where the units were
+// taken from.
+func (s *Service) productDecrementStock(ctx context.Context, change model.StockChange) ([]*model.StockAllocation, error) {
+	bundle, err := s.productIsBundle(change.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle {
+		return s.bundleDecrementStock(ctx, change)
+	}
+
+	hasVariants, err := s.productResolveVariant(ctx, change.ProductID, change.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracked, err := s.productTracksLocations(change.ProductID, change.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if tracked {
+		return s.locationDecrementStock(ctx, hasVariants, change)
+	}
+	if change.WarehouseID > 0 {
+		return nil, fmt.Errorf("stock is not tracked per warehouse")
+	}
+
+	var result *gorm.DB
+	if hasVariants {
+		result = s.DB.Model(&model.ProductVariant{}).Scopes(tools.IsDeletedAtNull).
+			Where("id = ? AND product_id = ? AND stock >= ?", change.VariantID, change.ProductID, change.Quantity).
+			Update("stock", gorm.Expr("stock - ?", change.Quantity))
+	} else {
+		result = s.DB.Model(&model.Product{}).Scopes(tools.IsDeletedAtNull).
+			Where("id = ? AND stock >= ?", change.ProductID, change.Quantity).
+			Update("stock", gorm.Expr("stock - ?", change.Quantity))
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("insufficient stock or stock not found")
+	}
+
+	return nil, s.productRecordMovement(ctx, hasVariants, -change.Quantity, change)
+}
+
+func (s *Service) productIncrementStock(ctx context.Context, change model.StockChange) error {
+	bundle, err := s.productIsBundle(change.ProductID)
+	if err != nil {
+		return err
+	}
+	if bundle {
+		return s.bundleIncrementStock(ctx, change)
+	}
+
+	hasVariants, err := s.productResolveVariant(ctx, change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+
+	tracked, err := s.productTracksLocations(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return s.locationIncrementStock(ctx, hasVariants, change)
+	}
+	if change.WarehouseID > 0 {
+		return fmt.Errorf("stock is not tracked per warehouse")
+	}
+
+	var result *gorm.DB
+	if hasVariants {
+		result = s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", change.VariantID, change.ProductID).
+			Update("stock", gorm.Expr("stock + ?", change.Quantity))
+	} else {
+		result = s.DB.Model(&model.Product{}).Where("id = ?", change.ProductID).
+			Update("stock", gorm.Expr("stock + ?", change.Quantity))
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("stock not found")
+	}
+
+	return s.productRecordMovement(ctx, hasVariants, change.Quantity, change)
+}
+
+// productSetStock overwrites the stock level and records the difference as a
+// single ledger entry. Stock held per warehouse can only be set one warehouse
+// at a time.
+func (s *Service) productSetStock(ctx context.Context, change model.StockChange, stock int) error {
+	bundle, err := s.productIsBundle(change.ProductID)
+	if err != nil {
+		return err
+	}
+	if bundle {
+		// setting a bundle to the stock it already has, as a catalog import of
+		// an unchanged export does, is not an error
+		current, err := s.productCurrentStock(change.ProductID, 0)
+		if err != nil {
+			return err
+		}
+		if current == stock && change.WarehouseID == 0 && change.VariantID == 0 {
+			return nil
+		}
+		return fmt.Errorf("bundle stock is derived from its components")
+	}
+
+	if change.WarehouseID > 0 {
+		return s.locationSetStock(ctx, change.VariantID > 0, change, stock)
+	}
+
+	current, err := s.productCurrentStock(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+
+	if current == stock {
+		return nil
+	}
+
+	tracked, err := s.productTracksLocations(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return fmt.Errorf("stock is tracked per warehouse; set it on a warehouse instead")
+	}
+
+	if change.VariantID > 0 {
+		err = s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", change.VariantID, change.ProductID).Update("stock", stock).Error
+	} else {
+		err = s.DB.Model(&model.Product{}).Where("id = ?", change.ProductID).Update("stock", stock).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.productRecordMovement(ctx, change.VariantID > 0, stock-current, change)
+}
+
+func (s *Service) productRecordMovement(ctx context.Context, hasVariants bool, delta int, change model.StockChange) error {
+	if hasVariants {
+		if err := s.ProductSyncVariantStock(ctx, change.ProductID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.bundleSyncStock(ctx, change.ProductID); err != nil {
+		return err
+	}
+
+	stockAfter, err := s.productCurrentStock(change.ProductID, change.VariantID)
+	if err != nil {
+		return err
+	}
+
+	movement := model.InventoryMovement{
+		ProductID:     change.ProductID,
+		VariantID:     change.VariantID,
+		Delta:         delta,
+		StockAfter:    stockAfter,
+		Reason:        string(change.Reason),
+		OrderID:       change.OrderID,
+		ReservationID: change.ReservationID,
+		Note:          change.Note,
+		Reference:     change.Reference,
+	}
+
+	if change.WarehouseID > 0 {
+		movement.WarehouseID = &change.WarehouseID
+	}
+
+	if user := middleware.AuthContext(ctx); user != nil {
+		movement.ActorID = &user.ID
+	}
+
+	return s.DB.Model(&movement).Create(&movement).Error
+}
+
+func (s *Service) productCurrentStock(productID int, variantID int) (int, error) {
+	var stock int
+
+	query := s.DB.Model(&model.Product{}).Where("id = ?", productID)
+	if variantID > 0 {
+		query = s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", variantID, productID)
+	}
+
+	if err := query.Select("stock").Scan(&stock).Error; err != nil {
+		return 0, err
+	}
+
+	return stock, nil
+}
+
+func (s *Service) productAddSoldCount(ctx context.Context, productID int, variantID int, qty int) error {
+	if variantID > 0 {
+		if err := s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", variantID, productID).
+			Update("sold_count", gorm.Expr("sold_count + ?", qty)).Error; err != nil {
+			return err
+		}
+	}
+
+	return s.DB.Model(&model.Product{}).Where("id = ?", productID).
+		Update("sold_count", gorm.Expr("sold_count + ?", qty)).Error
+}
+
+func (s *Service) productRemoveSoldCount(ctx context.Context, productID int, variantID int, qty int) error {
+	if variantID > 0 {
+		if err := s.DB.Model(&model.ProductVariant{}).Where("id = ? AND product_id = ?", variantID, productID).
+			Update("sold_count", gorm.Expr("GREATEST(sold_count - ?, 0)", qty)).Error; err != nil {
+			return err
+		}
+	}
+
+	return s.DB.Model(&model.Product{}).Where("id = ?", productID).
+		Update("sold_count", gorm.Expr("GREATEST(sold_count - ?, 0)", qty)).Error
+}
+
+// StockRestore puts the stock of a cancelled or returned order back. Stock
+// goes back to the warehouses it was taken from when a whole order item comes
+// back, and to the default warehouse otherwise. A restore whose reference has
+// already been applied is skipped and reported as false.
+func (s *Service) StockRestore(ctx context.Context, restore model.StockRestore) (bool, error) {
+	if restore.OrderID <= 0 || restore.Reference == "" || len(restore.Items) == 0 {
+		return false, fmt.Errorf("invalid input to restore stock")
+	}
+	if restore.Reason != model.MOVEMENT_REASON_ORDER_CANCEL && restore.Reason != model.MOVEMENT_REASON_RETURN {
+		return false, fmt.Errorf("invalid reason to restore stock")
+	}
+
+	// claiming the reference first makes a concurrent restore with the same
+	// reference wait on the key, then find it taken
+	claim := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.AppliedStockRestore{
+		Reference: restore.Reference,
+		OrderID:   restore.OrderID,
+		Reason:    string(restore.Reason),
+	})
+	if claim.Error != nil {
+		return false, claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return false, nil
+	}
+
+	for _, item := range restore.Items {
+		if item.ProductID <= 0 || item.VariantID < 0 || item.Quantity <= 0 {
+			return false, fmt.Errorf("invalid restore item for product %d", item.ProductID)
+		}
+
+		change := model.StockChange{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+			Reason:    restore.Reason,
+			OrderID:   &restore.OrderID,
+			Reference: restore.Reference,
+		}
+
+		var reservationItemIDs []int
+		if err := s.DB.Model(&model.StockReservationItem{}).
+			Joins("JOIN stock_reservation ON stock_reservation.id = stock_reservation_item.reservation_id").
+			Where("stock_reservation.order_id = ? AND stock_reservation.status = ?", restore.OrderID, model.RESERVATION_STATUS_COMMITTED).
+			Where("stock_reservation_item.product_id = ? AND stock_reservation_item.variant_id = ? AND stock_reservation_item.quantity = ?", item.ProductID, item.VariantID, item.Quantity).
+			Pluck("stock_reservation_item.id", &reservationItemIDs).Error; err != nil {
+			return false, err
+		}
+		if len(reservationItemIDs) > 0 {
+			change.ReservationItemID = &reservationItemIDs[0]
+		}
+
+		if err := s.productIncrementStock(ctx, change); err != nil {
+			return false, fmt.Errorf("failed to restore product %d: %w", item.ProductID, err)
+		}
+
+		if err := s.productRemoveSoldCount(ctx, item.ProductID, item.VariantID, item.Quantity); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// productResolveVariant reports whether stock for the product lives on its
+// variants, rejecting calls that name no variant for such products.
+func (s *Service) productResolveVariant(ctx context.Context, productID int, variantID int) (bool, error) {
+	hasVariants, err := s.ProductHasVariants(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+
+	if hasVariants && variantID <= 0 {
+		return false, fmt.Errorf("variant must be specified for products with variants")
+	}
+
+	return hasVariants, nil
+}
@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	grpcclient "products/grpc_client"
+	"utils/currency"
 	"utils/user"
 )
 
 type SellerDetails struct {
 	BusinessName string
+	Currency     string
 }
 
 func CheckSellerExists(userID int) (bool, error) {
@@ -31,6 +33,10 @@ func (s *Service) GetSellerDetails(id int) (*SellerDetails, error) {
 
 	sellerDetails := SellerDetails{
 		BusinessName: seller.BusinessName,
+		Currency:     seller.Currency,
+	}
+	if sellerDetails.Currency == "" {
+		sellerDetails.Currency = currency.Default()
 	}
 
 	return &sellerDetails, nil
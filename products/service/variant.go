@@ -0,0 +1,456 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) ProductOptionCreate(ctx context.Context, productID int, input model.NewProductOption) (*model.ProductOption, error) {
+	var count int64
+
+	valid, err := s.ProductOptionOnCreate(ctx, productID, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating product option")
+	}
+
+	if err := s.DB.Model(&model.ProductOption{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	option := model.ProductOption{
+		ProductID: productID,
+		Name:      input.Name,
+		Position:  int(count),
+	}
+
+	if err := s.DB.Model(&option).Create(&option).Error; err != nil {
+		return nil, err
+	}
+
+	for i, value := range input.Values {
+		optionValue := model.ProductOptionValue{
+			OptionID: option.ID,
+			Value:    value,
+			Position: i,
+		}
+
+		if err := s.DB.Model(&optionValue).Create(&optionValue).Error; err != nil {
+			return nil, err
+		}
+
+		option.Values = append(option.Values, &optionValue)
+	}
+
+	return &option, nil
+}
+
+func (s *Service) ProductOptionOnCreate(ctx context.Context, productID int, input *model.NewProductOption) (bool, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || len(input.Values) == 0 {
+		return false, fmt.Errorf("invalid input: option name and values cannot be empty")
+	}
+
+	seen := make(map[string]bool, len(input.Values))
+	for i, value := range input.Values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return false, fmt.Errorf("invalid input: option values cannot be empty")
+		}
+		if seen[strings.ToLower(value)] {
+			return false, fmt.Errorf("invalid input: duplicate option value %s", value)
+		}
+		seen[strings.ToLower(value)] = true
+		input.Values[i] = value
+	}
+
+	hasVariants, err := s.ProductHasVariants(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+	if hasVariants {
+		return false, fmt.Errorf("options cannot be added once the product has variants")
+	}
+
+	options, err := s.ProductOptionGetByProductID(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+	for _, option := range options {
+		if strings.EqualFold(option.Name, input.Name) {
+			return false, fmt.Errorf("option %s already exists", input.Name)
+		}
+	}
+
+	return true, nil
+}
+
+func (s *Service) ProductOptionGetByProductID(ctx context.Context, productID int) ([]*model.ProductOption, error) {
+	var (
+		options []*model.ProductOption
+		values  []*model.ProductOptionValue
+	)
+
+	if err := s.DB.Model(&options).Where("product_id = ?", productID).Order("position ASC").Order("id ASC").Find(&options).Error; err != nil {
+		return nil, err
+	}
+
+	if len(options) == 0 {
+		return options, nil
+	}
+
+	optionIDs := make([]int, 0, len(options))
+	optionByID := make(map[int]*model.ProductOption, len(options))
+	for _, option := range options {
+		optionIDs = append(optionIDs, option.ID)
+		optionByID[option.ID] = option
+		option.Values = []*model.ProductOptionValue{}
+	}
+
+	if err := s.DB.Model(&values).Where("option_id IN (?)", optionIDs).Order("position ASC").Order("id ASC").Find(&values).Error; err != nil {
+		return nil, err
+	}
+
+	for _, value := range values {
+		optionByID[value.OptionID].Values = append(optionByID[value.OptionID].Values, value)
+	}
+
+	return options, nil
+}
+
+func (s *Service) ProductVariantCreate(ctx context.Context, productID int, input model.NewProductVariant) (*model.ProductVariant, error) {
+	if input.Stock < 0 || (input.Price != nil && *input.Price < 0) {
+		return nil, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	product, err := s.ProductGetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.Type == string(model.PRODUCT_TYPE_BUNDLE) {
+		return nil, fmt.Errorf("bundles cannot have variants")
+	}
+
+	options, err := s.ProductOptionGetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("product has no options to build variants from")
+	}
+	if len(input.Options) != len(options) {
+		return nil, fmt.Errorf("invalid input: a value must be chosen for every product option")
+	}
+
+	var (
+		valueIDs   []int
+		valueNames []string
+		keyParts   []string
+	)
+
+	// resolve the chosen values in option order so equal combinations share a key
+	for _, option := range options {
+		chosen, ok := lookupOption(input.Options, option.Name)
+		if !ok {
+			return nil, fmt.Errorf("invalid input: missing value for option %s", option.Name)
+		}
+
+		var match *model.ProductOptionValue
+		for _, value := range option.Values {
+			if strings.EqualFold(value.Value, strings.TrimSpace(chosen)) {
+				match = value
+				break
+			}
+		}
+		if match == nil {
+			return nil, fmt.Errorf("invalid input: %s is not a value of option %s", chosen, option.Name)
+		}
+
+		valueIDs = append(valueIDs, match.ID)
+		valueNames = append(valueNames, match.Value)
+		keyParts = append(keyParts, strconv.Itoa(match.ID))
+	}
+
+	optionKey := strings.Join(keyParts, "-")
+
+	var count int64
+	if err := s.DB.Model(&model.ProductVariant{}).Scopes(tools.IsDeletedAtNull).Where("product_id = ? AND option_key = ?", productID, optionKey).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("a variant with these options already exists")
+	}
+
+	sku := strings.TrimSpace(input.SKU)
+	if sku == "" {
+		var productSKU string
+		if product.SKU != nil {
+			productSKU = *product.SKU
+		}
+		sku = tools.GenerateVariantSKU(productSKU, valueNames)
+	}
+
+	if err := s.productCloseOwnStock(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	live := true
+	variant := model.ProductVariant{
+		ProductID: productID,
+		OptionKey: optionKey,
+		SKU:       sku,
+		Price:     input.Price,
+		Stock:     input.Stock,
+		Live:      &live,
+	}
+
+	if err := s.DB.Model(&variant).Create(&variant).Error; err != nil {
+		return nil, err
+	}
+
+	if variant.Stock > 0 {
+		if err := s.productRecordMovement(ctx, false, variant.Stock, model.StockChange{
+			ProductID: productID,
+			VariantID: variant.ID,
+			Reason:    model.MOVEMENT_REASON_RESTOCK,
+			Note:      "initial stock",
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, valueID := range valueIDs {
+		variantValue := model.ProductVariantValue{
+			VariantID:     variant.ID,
+			OptionValueID: valueID,
+		}
+
+		if err := s.DB.Model(&variantValue).Create(&variantValue).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.ProductSyncVariantStock(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	return s.ProductVariantGetByID(ctx, productID, variant.ID)
+}
+
+func (s *Service) ProductVariantUpdate(ctx context.Context, input model.UpdateProductVariant) (*model.ProductVariant, error) {
+	updates := map[string]interface{}{}
+
+	if input.Price != nil {
+		if *input.Price < 0 {
+			return nil, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+		}
+		updates["price"] = *input.Price
+	}
+	if input.Stock != nil && *input.Stock < 0 {
+		return nil, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	current, err := s.ProductVariantGetByID(ctx, input.ProductID, input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if err := s.DB.Model(&model.ProductVariant{}).Where("id = ?", input.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Price != nil && (current.Price == nil || *current.Price != *input.Price) {
+		if err := s.productRecordPriceChange(ctx, model.PriceHistory{
+			ProductID:     input.ProductID,
+			VariantID:     input.ID,
+			ListPrice:     *input.Price,
+			EffectiveFrom: time.Now(),
+			Reason:        string(model.PRICE_CHANGE_REASON_MANUAL),
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if input.Stock != nil {
+		if err := s.productSetStock(ctx, model.StockChange{
+			ProductID: input.ProductID,
+			VariantID: input.ID,
+			Reason:    model.MOVEMENT_REASON_MANUAL_ADJUSTMENT,
+		}, *input.Stock); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.ProductSyncVariantStock(ctx, input.ProductID); err != nil {
+		return nil, err
+	}
+
+	return s.ProductVariantGetByID(ctx, input.ProductID, input.ID)
+}
+
+func (s *Service) ProductVariantDelete(ctx context.Context, productID int, variantID int) (bool, error) {
+	if _, err := s.ProductVariantGetByID(ctx, productID, variantID); err != nil {
+		return false, err
+	}
+
+	// clearing live frees the variant's options and sku for a new variant
+	if err := s.DB.Model(&model.ProductVariant{}).Where("id = ?", variantID).Updates(map[string]interface{}{
+		"deleted_at": time.Now(),
+		"live":       nil,
+	}).Error; err != nil {
+		return false, err
+	}
+
+	if err := s.ProductSyncVariantStock(ctx, productID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *Service) ProductVariantGetByID(ctx context.Context, productID int, variantID int) (*model.ProductVariant, error) {
+	var variant *model.ProductVariant
+
+	if err := s.DB.Model(&variant).Scopes(tools.IsDeletedAtNull).Where("id = ? AND product_id = ?", variantID, productID).First(&variant).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("variant not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := s.productVariantLoadOptions([]*model.ProductVariant{variant}); err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (s *Service) ProductVariantGetByProductID(ctx context.Context, productID int) ([]*model.ProductVariant, error) {
+	var variants []*model.ProductVariant
+
+	if err := s.DB.Model(&variants).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Order("id ASC").Find(&variants).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.productVariantLoadOptions(variants); err != nil {
+		return nil, err
+	}
+
+	return variants, nil
+}
+
+func (s *Service) ProductHasVariants(ctx context.Context, productID int) (bool, error) {
+	var count int64
+
+	if err := s.DB.Model(&model.ProductVariant{}).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// productCloseOwnStock zeroes the stock a product holds itself before its
+// first variant is added, after which its stock is the sum of its variants.
+// The stock leaves through the ledger so the product's balance still adds up.
+func (s *Service) productCloseOwnStock(ctx context.Context, productID int) error {
+	hasVariants, err := s.ProductHasVariants(ctx, productID)
+	if err != nil || hasVariants {
+		return err
+	}
+
+	current, err := s.productCurrentStock(productID, 0)
+	if err != nil || current == 0 {
+		return err
+	}
+
+	tracked, err := s.productTracksLocations(productID, 0)
+	if err != nil {
+		return err
+	}
+	if tracked {
+		return fmt.Errorf("stock is tracked per warehouse; set it to zero at every warehouse before adding variants")
+	}
+
+	if err := s.DB.Model(&model.Product{}).Where("id = ?", productID).Update("stock", 0).Error; err != nil {
+		return err
+	}
+
+	return s.productRecordMovement(ctx, false, -current, model.StockChange{
+		ProductID: productID,
+		Reason:    model.MOVEMENT_REASON_MANUAL_ADJUSTMENT,
+		Note:      "stock moved to variants",
+	})
+}
+
+// ProductSyncVariantStock keeps product.stock equal to the total stock of its
+// variants so catalog listings can keep filtering on a single column.
+func (s *Service) ProductSyncVariantStock(ctx context.Context, productID int) error {
+	var total int64
+
+	hasVariants, err := s.ProductHasVariants(ctx, productID)
+	if err != nil || !hasVariants {
+		return err
+	}
+
+	if err := s.DB.Model(&model.ProductVariant{}).Scopes(tools.IsDeletedAtNull).Where("product_id = ?", productID).
+		Select("COALESCE(SUM(stock), 0)").Scan(&total).Error; err != nil {
+		return err
+	}
+
+	return s.DB.Model(&model.Product{}).Where("id = ?", productID).Update("stock", total).Error
+}
+
+func (s *Service) productVariantLoadOptions(variants []*model.ProductVariant) error {
+	var rows []struct {
+		VariantID int
+		Name      string
+		Value     string
+	}
+
+	if len(variants) == 0 {
+		return nil
+	}
+
+	variantIDs := make([]int, 0, len(variants))
+	variantByID := make(map[int]*model.ProductVariant, len(variants))
+	for _, variant := range variants {
+		variantIDs = append(variantIDs, variant.ID)
+		variantByID[variant.ID] = variant
+		variant.Options = map[string]string{}
+	}
+
+	if err := s.DB.Table("product_variant_value").
+		Select("product_variant_value.variant_id, product_option.name, product_option_value.value").
+		Joins("JOIN product_option_value ON product_option_value.id = product_variant_value.option_value_id").
+		Joins("JOIN product_option ON product_option.id = product_option_value.option_id").
+		Where("product_variant_value.variant_id IN (?)", variantIDs).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		variantByID[row.VariantID].Options[row.Name] = row.Value
+	}
+
+	return nil
+}
+
+func lookupOption(options map[string]string, name string) (string, bool) {
+	for key, value := range options {
+		if strings.EqualFold(strings.TrimSpace(key), name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
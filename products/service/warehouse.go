@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"products/model"
+	"products/tools"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) WarehouseCreate(ctx context.Context, sellerID int, input model.NewWarehouse) (*model.Warehouse, error) {
+	valid, err := s.WarehouseOnCreate(ctx, sellerID, &input)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("error creating warehouse")
+	}
+
+	warehouse := model.Warehouse{
+		SellerID: sellerID,
+		Name:     input.Name,
+		Code:     input.Code,
+		Address:  input.Address,
+		Priority: input.Priority,
+	}
+
+	if err := s.DB.Model(&warehouse).Create(&warehouse).Error; err != nil {
+		return nil, err
+	}
+
+	return &warehouse, nil
+}
+
+func (s *Service) WarehouseOnCreate(ctx context.Context, sellerID int, input *model.NewWarehouse) (bool, error) {
+	var count int64
+
+	input.Name = strings.TrimSpace(input.Name)
+	input.Code = strings.ToUpper(strings.TrimSpace(input.Code))
+	input.Address = strings.TrimSpace(input.Address)
+
+	if input.Name == "" || input.Code == "" {
+		return false, fmt.Errorf("invalid input: name and code are required")
+	}
+	if input.Priority < 0 {
+		return false, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	if err := s.DB.Model(&model.Warehouse{}).Scopes(tools.IsDeletedAtNull).Where("seller_id = ? AND code = ?", sellerID, input.Code).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, fmt.Errorf("warehouse code %s is already in use", input.Code)
+	}
+
+	return true, nil
+}
+
+func (s *Service) WarehouseUpdate(ctx context.Context, sellerID int, input model.UpdateWarehouse) (*model.Warehouse, error) {
+	if _, err := s.WarehouseGetByID(ctx, input.ID, sellerID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid input: name cannot be empty")
+		}
+		updates["name"] = name
+	}
+	if input.Address != nil {
+		updates["address"] = strings.TrimSpace(*input.Address)
+	}
+	if input.Priority != nil {
+		if *input.Priority < 0 {
+			return nil, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+		}
+		updates["priority"] = *input.Priority
+	}
+
+	if len(updates) > 0 {
+		if err := s.DB.Model(&model.Warehouse{}).Where("id = ?", input.ID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.WarehouseGetByID(ctx, input.ID, sellerID)
+}
+
+// WarehouseDelete removes a warehouse that no longer holds any stock. Stock
+// has to be moved or written off first so the product totals stay correct.
+func (s *Service) WarehouseDelete(ctx context.Context, id int, sellerID int) (string, error) {
+	var count int64
+
+	if _, err := s.WarehouseGetByID(ctx, id, sellerID); err != nil {
+		return "failed", err
+	}
+
+	if err := s.DB.Model(&model.LocationStock{}).Where("warehouse_id = ? AND stock > 0", id).Count(&count).Error; err != nil {
+		return "failed", err
+	}
+	if count > 0 {
+		return "failed", fmt.Errorf("failed to delete warehouse: warehouse still holds stock")
+	}
+
+	if err := s.DB.Model(&model.Warehouse{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error; err != nil {
+		return "failed", err
+	}
+
+	return "success", nil
+}
+
+func (s *Service) WarehouseGetBySeller(ctx context.Context, sellerID int) ([]*model.Warehouse, error) {
+	var warehouses []*model.Warehouse
+
+	if err := s.DB.Model(&warehouses).Scopes(tools.IsDeletedAtNull).Where("seller_id = ?", sellerID).Order("priority ASC").Order("id ASC").Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+
+	return warehouses, nil
+}
+
+func (s *Service) WarehouseGetByID(ctx context.Context, id int, sellerID int) (*model.Warehouse, error) {
+	var warehouse *model.Warehouse
+
+	if err := s.DB.Model(&warehouse).Scopes(tools.IsDeletedAtNull).Where("id = ? AND seller_id = ?", id, sellerID).First(&warehouse).Error; err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("warehouse not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return warehouse, nil
+}
+
+// LocationStockSet overwrites the stock a warehouse holds for a product, or
+// one of its variants.
+func (s *Service) LocationStockSet(ctx context.Context, productID int, warehouseID int, input model.SetLocationStock) (bool, error) {
+	if input.Stock < 0 {
+		return false, fmt.Errorf("invalid input: numerical inputs cannot be negative")
+	}
+
+	if _, err := s.productResolveVariant(ctx, productID, input.VariantID); err != nil {
+		return false, err
+	}
+
+	if err := s.productSetStock(ctx, model.StockChange{
+		ProductID:   productID,
+		VariantID:   input.VariantID,
+		Reason:      model.MOVEMENT_REASON_MANUAL_ADJUSTMENT,
+		Note:        input.Note,
+		WarehouseID: warehouseID,
+	}, input.Stock); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+)
+
+// Storage is implemented by every backend that can hold uploaded files.
+// Keys are slash separated paths relative to the root of the backend.
+type Storage interface {
+	Save(ctx context.Context, key string, r io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+const (
+	defaultStorageDir = "uploads"
+	defaultStorageURL = "/uploads"
+)
+
+var (
+	store Storage
+	once  sync.Once
+)
+
+func GetStorage() Storage {
+	once.Do(func() {
+		dir := os.Getenv("IMAGE_STORAGE_DIR")
+		if dir == "" {
+			dir = defaultStorageDir
+		}
+
+		baseURL := os.Getenv("IMAGE_BASE_URL")
+		if baseURL == "" {
+			baseURL = defaultStorageURL
+		}
+
+		store = NewLocalStorage(dir, baseURL)
+	})
+
+	return store
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"products/model"
+	"strings"
 )
 
 func GenerateSKU(product *model.Product) string {
@@ -20,3 +21,12 @@ func GenerateSKU(product *model.Product) string {
 
 	return fmt.Sprintf("SKU-%s", hashStr[:12])
 }
+
+func GenerateVariantSKU(productSKU string, optionValues []string) string {
+	sku := productSKU
+	for _, value := range optionValues {
+		sku += "-" + strings.ToUpper(Slugify(value))
+	}
+
+	return sku
+}
@@ -0,0 +1,13 @@
+package tools
+
+import (
+	"regexp"
+	"strings"
+)
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func Slugify(s string) string {
+	slug := nonSlugChars.ReplaceAllString(strings.ToLower(s), "-")
+	return strings.Trim(slug, "-")
+}
@@ -0,0 +1,36 @@
+// Command admin grants or revokes admin access for a user, since no admin
+// exists to do it through the API until the first one is made.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"users/config"
+	"users/service"
+)
+
+func main() {
+	email := flag.String("email", "", "email of the user")
+	revoke := flag.Bool("revoke", false, "revoke admin access instead of granting it")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+
+	config.ConnectDB()
+	config.SyncDB()
+
+	user, err := service.GetService().UserSetAdmin(context.Background(), *email, !*revoke)
+	if err != nil {
+		log.Fatalf("failed to update %s: %v", *email, err)
+	}
+
+	if *revoke {
+		fmt.Printf("revoked admin access for %s (user %d)\n", user.Email, user.ID)
+	} else {
+		fmt.Printf("granted admin access to %s (user %d)\n", user.Email, user.ID)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 	"users/model"
+	"utils/currency"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -59,4 +60,8 @@ func GetDB() *gorm.DB {
 
 func SyncDB() {
 	db.AutoMigrate(&model.User{})
+	db.AutoMigrate(&model.Seller{})
+
+	// sellers registered before currencies existed price in the default one
+	db.Model(&model.Seller{}).Where("currency = ''").Update("currency", currency.Default())
 }
@@ -110,7 +110,48 @@ func SellerProfile(c *gin.Context) {
 			BusinessName: seller.BusinessName,
 			Address:      seller.Address,
 			IsApproved:   seller.IsApproved,
+			Currency:     seller.Currency,
 			CreatedAt:    seller.CreatedAt,
 		},
 	})
 }
+
+func UpdateSellerCurrency(c *gin.Context) {
+	var input model.UpdateSellerCurrency
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	seller, err := s.SellerUpdateCurrency(c.Request.Context(), input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	s.Commit()
+
+	c.JSON(http.StatusOK, &model.GlobalResponse{
+		Success: true,
+		Message: "Seller currency updated to " + seller.Currency,
+	})
+}
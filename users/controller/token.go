@@ -80,10 +80,22 @@ func RefreshToken(c *gin.Context) {
 		fmt.Printf("Warning: failed to revoke old refresh token JTI: %v", err)
 	}
 
+	// the role is looked up again, so a granted or revoked admin or seller
+	// status shows on the next refresh
+	user, err := s.UserGetByID(c.Request.Context(), claims.ID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not found",
+		})
+		return
+	}
+	role, seller := s.UserRole(c.Request.Context(), user)
+
 	// new jti and refresh token
 	jti := uuid.New().String()
 
-	newRefreshToken, err := tools.CreateToken(claims.ID, claims.Email, claims.Role, 24*time.Hour, jti)
+	newRefreshToken, err := tools.CreateToken(claims.ID, claims.Email, role, seller, 24*time.Hour, jti)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
 			Success: false,
@@ -93,7 +105,7 @@ func RefreshToken(c *gin.Context) {
 	}
 
 	// store jti in db
-	user, err := s.UserUpdateRememberToken(c.Request.Context(), claims.ID, jti)
+	user, err = s.UserUpdateRememberToken(c.Request.Context(), claims.ID, jti)
 	if err != nil {
 		panic(fmt.Errorf("failed to store refresh token jti: %v", err))
 	}
@@ -102,7 +114,7 @@ func RefreshToken(c *gin.Context) {
 	c.SetCookie("refresh_token", newRefreshToken, 3600*24, "/", "", true, true)
 
 	// generate new access token
-	newAccessToken, err := tools.CreateToken(claims.ID, claims.Email, claims.Role, 30*time.Minute, jti)
+	newAccessToken, err := tools.CreateToken(claims.ID, claims.Email, role, seller, 30*time.Minute, jti)
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
 			Success: false,
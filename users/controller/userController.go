@@ -7,6 +7,8 @@ import (
 	grpcclient "users/grpc_client"
 	"users/model"
 	"users/service"
+	"users/tools"
+	"utils/middleware"
 	"utils/orders"
 
 	"github.com/gin-gonic/gin"
@@ -195,3 +197,56 @@ func GetProfile(c *gin.Context) {
 		},
 	})
 }
+
+func UpdateAddress(c *gin.Context) {
+	user := middleware.AuthContext(c.Request.Context())
+	if user == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, &model.GlobalResponse{
+			Success: false,
+			Message: "user not logged in",
+		})
+		return
+	}
+
+	var input model.UpdateAddress
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	s := service.GetTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			err := s.Rollback(r)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, &model.GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+	}()
+
+	updated, err := s.UserUpdateAddress(c.Request.Context(), user.ID, input)
+	if err != nil {
+		s.Rollback(err)
+		c.AbortWithStatusJSON(http.StatusBadRequest, &model.GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return
+	}
+	s.Commit()
+
+	data := tools.UserToUserData(updated)
+	data.Address = updated.Address
+
+	c.JSON(http.StatusOK, &model.UserResponse{
+		Success: true,
+		Message: "Address updated successfully",
+		Data:    *data,
+	})
+}
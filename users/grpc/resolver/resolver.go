@@ -35,6 +35,9 @@ func (s *Server) GetUserDetails(ctx context.Context, request *user.GetUserDetail
 		Phone:   details.Phone,
 		Address: *details.Address,
 	}
+	if details.Region != nil {
+		resp.Region = *details.Region
+	}
 
 	return resp, nil
 }
@@ -49,6 +52,7 @@ func (s *Server) GetSellerDetails(ctx context.Context, request *user.GetSellerDe
 
 	resp := &user.GetSellerDetailsResponse{
 		BusinessName: seller.BusinessName,
+		Currency:     seller.Currency,
 	}
 
 	return resp, nil
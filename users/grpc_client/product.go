@@ -0,0 +1,18 @@
+package grpcclient
+
+import (
+	"context"
+	"utils/product"
+)
+
+func GetCurrencies(ctx context.Context) (*product.GetCurrenciesResponse, error) {
+	productConn, conn := product.Connect(product.ConnectionOption{})
+	defer conn.Close()
+
+	currencies, err := productConn.GetCurrencies(ctx, &product.GetCurrenciesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return currencies, nil
+}
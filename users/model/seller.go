@@ -7,6 +7,7 @@ type Seller struct {
 	BusinessName string     `json:"business_name" gorm:"type:varchar(255);not null"`
 	Address      string     `json:"address" gorm:"type:varchar(255);not null"`
 	IsApproved   string     `json:"is_approved" gorm:"type:varchar(15);default:false"`
+	Currency     string     `json:"currency" gorm:"type:varchar(3);not null"`
 	CreatedAt    time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
 	UpdatedAt    *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
 	DeletedAt    *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
@@ -16,6 +17,13 @@ type Seller struct {
 type NewSeller struct {
 	BusinessName string `json:"business_name"`
 	Address      string `json:"address"`
+	Currency     string `json:"currency"`
+}
+
+// UpdateSellerCurrency changes the base currency of a seller. Products listed
+// before the change keep the currency they were priced in.
+type UpdateSellerCurrency struct {
+	Currency string `json:"currency"`
 }
 
 type SellerResponse struct {
@@ -29,5 +37,6 @@ type SellerData struct {
 	BusinessName string    `json:"business_name"`
 	Address      string    `json:"address"`
 	IsApproved   string    `json:"is_approved"`
+	Currency     string    `json:"currency"`
 	CreatedAt    time.Time `json:"created_at"`
 }
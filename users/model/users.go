@@ -9,7 +9,9 @@ type User struct {
 	Email         string     `json:"email" gorm:"type:varchar(100);unique;not null"`
 	Phone         string     `json:"phone" gorm:"type:varchar(20);not null"`
 	Address       *string    `json:"address" gorm:"type:varchar(255);null"`
+	Region        *string    `json:"region" gorm:"type:varchar(10);null"`
 	RememberToken *string    `json:"remember_token" gorm:"type:varchar(100);null"`
+	IsAdmin       bool       `json:"is_admin" gorm:"type:boolean;not null;default:false"`
 	CreatedAt     time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
 	UpdatedAt     *time.Time `json:"updated_at" gorm:"type:timestamp;null"`
 	DeletedAt     *time.Time `json:"deleted_at" gorm:"type:timestamp;null"`
@@ -21,6 +23,7 @@ type UserData struct {
 	Email     string    `json:"email"`
 	Phone     string    `json:"phone"`
 	Address   *string   `json:"address"`
+	Region    *string   `json:"region"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
@@ -30,9 +33,18 @@ type NewUser struct {
 	Email           string `json:"email"`
 	Phone           string `json:"phone"`
 	Address         string `json:"address"`
+	Region          string `json:"region"`
 	ConfirmPassword string `json:"confirm_password"`
 }
 
+// UpdateAddress sets where a user's orders ship to. Region is the country,
+// or country and subdivision, of the address, such as DE or US-CA, and
+// decides the tax charged at checkout.
+type UpdateAddress struct {
+	Address string `json:"address"`
+	Region  string `json:"region"`
+}
+
 type UserResponse struct {
 	Success bool     `json:"success"`
 	Message string   `json:"message"`
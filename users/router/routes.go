@@ -18,6 +18,7 @@ func ApiRouter(r *gin.Engine) {
 		auth.POST("/refresh-token", controller.RefreshToken)
 		auth.POST("/logout", controller.Logout)
 		auth.GET("/profile/:id", controller.GetProfile)
+		auth.PUT("/profile/address", controller.UpdateAddress)
 
 		auth.POST("/seller/register", controller.RegisterSeller)
 	}
@@ -26,6 +27,7 @@ func ApiRouter(r *gin.Engine) {
 	seller.Use(middleware.AuthMiddleware(), middleware.CORSMiddlewware(), middleware.IsLogin(), middleware.IsSeller())
 	{
 		seller.GET("/seller/profile/:id", controller.SellerProfile)
+		seller.PUT("/seller/currency", controller.UpdateSellerCurrency)
 	}
 
 }
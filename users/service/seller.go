@@ -3,8 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	grpcclient "users/grpc_client"
 	"users/model"
 	"users/tools"
+	"utils/currency"
 	"utils/middleware"
 
 	"gorm.io/gorm"
@@ -21,7 +25,7 @@ const (
 func (s *Service) SellerRegister(ctx context.Context, input model.NewSeller) (*model.Seller, error) {
 	var ctxData = middleware.AuthContext(ctx)
 
-	validInput, err := s.SellerOnCreate(ctx, input)
+	validInput, err := s.SellerOnCreate(ctx, &input)
 	if !validInput {
 		panic(err)
 	}
@@ -39,6 +43,7 @@ func (s *Service) SellerRegister(ctx context.Context, input model.NewSeller) (*m
 		BusinessName: input.BusinessName,
 		Address:      input.Address,
 		IsApproved:   string(APPROVAL_TYPE_PENDING),
+		Currency:     input.Currency,
 	}
 
 	if err := s.DB.Model(&seller).Create(&seller).Error; err != nil {
@@ -48,11 +53,20 @@ func (s *Service) SellerRegister(ctx context.Context, input model.NewSeller) (*m
 	return &seller, nil
 }
 
-func (s *Service) SellerOnCreate(ctx context.Context, input model.NewSeller) (bool, error) {
+func (s *Service) SellerOnCreate(ctx context.Context, input *model.NewSeller) (bool, error) {
 	if input.BusinessName == "" || input.Address == "" {
 		return false, fmt.Errorf("data cannot be empty")
 	}
 
+	if input.Currency == "" {
+		input.Currency = currency.Default()
+	}
+	code, err := s.sellerCheckCurrency(ctx, input.Currency)
+	if err != nil {
+		return false, err
+	}
+	input.Currency = code
+
 	exists, err := s.SellerCheckExist(ctx, 0)
 	if err != nil {
 		return false, err
@@ -115,6 +129,48 @@ func (s *Service) SellerUpdateApproval(ctx context.Context, id int, approval App
 	return nil
 }
 
+// SellerUpdateCurrency changes the currency new products of the seller are
+// priced in.
+func (s *Service) SellerUpdateCurrency(ctx context.Context, input model.UpdateSellerCurrency) (*model.Seller, error) {
+	var ctxData = middleware.AuthContext(ctx)
+
+	code, err := s.sellerCheckCurrency(ctx, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	seller, err := s.SellerGetByID(ctx, ctxData.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.DB.Model(seller).Update("currency", code).Error; err != nil {
+		return nil, err
+	}
+	seller.Currency = code
+
+	return seller, nil
+}
+
+// sellerCheckCurrency normalizes a currency code and checks that products can
+// be priced in it, which needs an exchange rate in the products service.
+func (s *Service) sellerCheckCurrency(ctx context.Context, code string) (string, error) {
+	code, err := currency.Normalize(code)
+	if err != nil {
+		return "", err
+	}
+
+	supported, err := grpcclient.GetCurrencies(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to check currency: %w", err)
+	}
+	if !slices.Contains(supported.Currencies, code) {
+		return "", fmt.Errorf("%s is not supported, choose one of %s", code, strings.Join(supported.Currencies, ", "))
+	}
+
+	return code, nil
+}
+
 func (s *Service) SellerGetByID(ctx context.Context, id int) (*model.Seller, error) {
 	var seller model.Seller
 
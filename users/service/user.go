@@ -8,6 +8,7 @@ import (
 	"users/model"
 	"users/tools"
 	"utils/middleware"
+	"utils/region"
 
 	"github.com/google/uuid"
 )
@@ -21,6 +22,17 @@ func (s *Service) UserRegister(ctx context.Context, input model.NewUser) (*model
 		return nil, err
 	}
 
+	// the region can be left out at registration, but has to be set before
+	// the user can check out
+	var userRegion *string
+	if strings.TrimSpace(input.Region) != "" {
+		code, err := region.Normalize(input.Region)
+		if err != nil {
+			return nil, err
+		}
+		userRegion = &code
+	}
+
 	hashedPw, err := tools.HashAndSalt(input.Password)
 	if err != nil {
 		panic(err)
@@ -32,6 +44,7 @@ func (s *Service) UserRegister(ctx context.Context, input model.NewUser) (*model
 		Password: hashedPw,
 		Phone:    input.Phone,
 		Address:  &input.Address,
+		Region:   userRegion,
 	}
 
 	if err := s.DB.Model(&user).Create(&user).Error; err != nil {
@@ -84,8 +97,6 @@ func (s *Service) UserOnCreate(ctx context.Context, input model.NewUser) (bool,
 }
 
 func (s *Service) UserLogin(ctx context.Context, input model.UserLogin) (*model.UserLoginResponse, error) {
-	var role string
-
 	if input.Email == "" || input.Password == "" {
 		panic(fmt.Errorf("email or password cannot be empty"))
 	}
@@ -102,18 +113,12 @@ func (s *Service) UserLogin(ctx context.Context, input model.UserLogin) (*model.
 		panic(fmt.Errorf("invalid password"))
 	}
 
-	// check if is seller
-	isSeller, _ := s.SellerCheckIsValid(ctx, user.ID)
-	if !isSeller {
-		role = "user"
-	} else {
-		role = "seller"
-	}
+	role, seller := s.UserRole(ctx, user)
 
 	jti := uuid.New().String()
 
 	//create refresh token
-	refreshToken, err := tools.CreateToken(user.ID, input.Email, role, 24*time.Hour, jti)
+	refreshToken, err := tools.CreateToken(user.ID, input.Email, role, seller, 24*time.Hour, jti)
 	if err != nil {
 		panic(err)
 	}
@@ -124,7 +129,7 @@ func (s *Service) UserLogin(ctx context.Context, input model.UserLogin) (*model.
 	}
 
 	// create access token
-	accessToken, err := tools.CreateToken(user.ID, input.Email, role, 30*time.Minute, "")
+	accessToken, err := tools.CreateToken(user.ID, input.Email, role, seller, 30*time.Minute, "")
 	if err != nil {
 		panic(err)
 	}
@@ -149,6 +154,58 @@ func (s *Service) UserLogin(ctx context.Context, input model.UserLogin) (*model.
 
 }
 
+// UserRole is the role put in the user's tokens: admin for users flagged as
+// admins, seller for approved sellers, and user otherwise. seller reports
+// whether the user is an approved seller, which an admin can be as well.
+func (s *Service) UserRole(ctx context.Context, user *model.User) (role string, seller bool) {
+	// check if is seller
+	seller, _ = s.SellerCheckIsValid(ctx, user.ID)
+
+	switch {
+	case user.IsAdmin:
+		return "admin", seller
+	case seller:
+		return "seller", seller
+	}
+
+	return "user", seller
+}
+
+// UserSetAdmin grants or revokes admin access. It takes effect at the user's
+// next login or token refresh.
+func (s *Service) UserSetAdmin(ctx context.Context, email string, admin bool) (*model.User, error) {
+	user, err := s.UserGetByEmail(ctx, strings.TrimSpace(strings.ToLower(email)))
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := s.DB.Model(user).Update("is_admin", admin).Error; err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// UserUpdateAddress sets where a user's orders ship to.
+func (s *Service) UserUpdateAddress(ctx context.Context, id int, input model.UpdateAddress) (*model.User, error) {
+	address := strings.TrimSpace(input.Address)
+	if address == "" || len(address) > 255 {
+		return nil, fmt.Errorf("invalid input: address is required and must be at most 255 characters")
+	}
+
+	code, err := region.Normalize(input.Region)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input: %w", err)
+	}
+
+	if err := s.DB.Model(&model.User{}).Scopes(tools.IsDeletedAtNull).Where("id = ?", id).
+		Updates(map[string]interface{}{"address": address, "region": code}).Error; err != nil {
+		return nil, err
+	}
+
+	return s.UserGetByID(ctx, id)
+}
+
 func (s *Service) UserGetByEmail(ctx context.Context, email string) (*model.User, error) {
 	var user model.User
 
@@ -15,6 +15,7 @@ func UserToUserData(user *model.User) *model.UserData {
 		Name:      user.Name,
 		Email:     user.Email,
 		Phone:     user.Phone,
+		Region:    user.Region,
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -13,17 +13,21 @@ type Claims struct {
 	ID    int    `json:"id"`
 	Email string `json:"email"`
 	Role  string `json:"role"`
+	// Seller is set for approved sellers whatever their role, so an admin who
+	// sells keeps their seller access
+	Seller bool `json:"seller,omitempty"`
 	jwt.StandardClaims
 }
 
-func CreateToken(id int, email string, role string, duration time.Duration, jti string) (string, error) {
+func CreateToken(id int, email string, role string, seller bool, duration time.Duration, jti string) (string, error) {
 	var jwtKey = []byte(os.Getenv("JWT_KEY"))
 
 	// Define claim
 	claims := Claims{
-		ID:    id,
-		Email: email,
-		Role:  role,
+		ID:     id,
+		Email:  email,
+		Role:   role,
+		Seller: seller,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Add(duration).Unix(),
 			IssuedAt:  time.Now().Unix(),
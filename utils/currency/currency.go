@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+// zeroDecimal lists the currencies that have no minor unit.
+var zeroDecimal = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "ISK": true,
+	"JPY": true, "KMF": true, "KRW": true, "PYG": true, "RWF": true,
+	"UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true,
+	"XPF": true,
+}
+
+// Default is the currency prices are kept in when nothing else is said, and the
+// reference currency exchange rates are quoted against. It is read from
+// DEFAULT_CURRENCY and falls back to USD.
+func Default() string {
+	if code, err := Normalize(os.Getenv("DEFAULT_CURRENCY")); err == nil {
+		return code
+	}
+
+	return "USD"
+}
+
+// Normalize upper-cases a currency code and checks that it looks like an ISO
+// 4217 code.
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", code)
+	}
+
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", code)
+		}
+	}
+
+	return code, nil
+}
+
+// Decimals returns the number of minor unit digits of a currency.
+func Decimals(code string) int {
+	if zeroDecimal[code] {
+		return 0
+	}
+
+	return 2
+}
+
+// Round rounds an amount to the minor unit of its currency.
+func Round(amount float64, code string) float64 {
+	factor := math.Pow(10, float64(Decimals(code)))
+	return math.Round(amount*factor) / factor
+}
@@ -20,6 +20,8 @@ type contextKey struct {
 type User struct {
 	ID   int    `json:"id"`
 	Role string `json:"role"`
+	// Seller is true for approved sellers, including admins who also sell
+	Seller bool `json:"seller"`
 }
 
 type GlobalResponse struct {
@@ -74,8 +76,9 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		ctx := context.WithValue(c.Request.Context(), CtxKey, &User{
-			ID:   claims.ID,
-			Role: claims.Role,
+			ID:     claims.ID,
+			Role:   claims.Role,
+			Seller: claims.Seller || claims.Role == "seller",
 		})
 
 		c.Request = c.Request.WithContext(ctx)
@@ -105,7 +108,7 @@ func IsLogin() gin.HandlerFunc {
 func IsSeller() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user := AuthContext(c.Request.Context())
-		if user == nil || user.Role != "seller" {
+		if user == nil || !user.Seller {
 			log.Println("No context found")
 			c.AbortWithStatusJSON(http.StatusUnauthorized, GlobalResponse{
 				Success: false,
@@ -116,3 +119,26 @@ func IsSeller() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// IsAdmin only lets admins through. A logged in user without the admin role
+// is forbidden rather than unauthorised.
+func IsAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := AuthContext(c.Request.Context())
+		if user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, GlobalResponse{
+				Success: false,
+				Message: "user not logged in",
+			})
+			return
+		}
+		if user.Role != "admin" {
+			c.AbortWithStatusJSON(http.StatusForbidden, GlobalResponse{
+				Success: false,
+				Message: "admin access required",
+			})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,261 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	defaultIdempotencyKeyTTL = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+
+	// idempotencyLockTimeout is how long a request holds its key. A key still
+	// processing after that is taken to belong to a request whose process
+	// died, and the next retry takes it over.
+	idempotencyLockTimeout = 5 * time.Minute
+)
+
+type IdempotencyStatus string
+
+const (
+	IDEMPOTENCY_STATUS_PROCESSING IdempotencyStatus = "processing"
+	IDEMPOTENCY_STATUS_COMPLETED  IdempotencyStatus = "completed"
+)
+
+// IdempotencyKey is a request made with an Idempotency-Key header, and the
+// response it got. Fingerprint is a hash of the method, path and body, so a
+// key cannot be reused for a different request.
+type IdempotencyKey struct {
+	ID             int        `json:"id" gorm:"type:int;primaryKey;autoIncrement"`
+	UserID         int        `json:"user_id" gorm:"type:int;not null;uniqueIndex:idx_idempotency_user_key"`
+	RequestKey     string     `json:"request_key" gorm:"type:varchar(255);not null;uniqueIndex:idx_idempotency_user_key"`
+	Fingerprint    string     `json:"fingerprint" gorm:"type:varchar(64);not null"`
+	Status         string     `json:"status" gorm:"type:varchar(20);not null"`
+	ResponseStatus int        `json:"response_status" gorm:"type:int;not null;default:0"`
+	ContentType    string     `json:"content_type" gorm:"type:varchar(100)"`
+	ResponseBody   []byte     `json:"-" gorm:"type:mediumblob"`
+	LockedUntil    *time.Time `json:"locked_until" gorm:"type:timestamp;null"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"type:timestamp;not null;index"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"type:timestamp;not null"`
+}
+
+// idempotencyWriter keeps a copy of the response so it can be replayed.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyWriter) WriteString(data string) (int, error) {
+	w.body.WriteString(data)
+	return w.ResponseWriter.WriteString(data)
+}
+
+// IdempotencyKeyTTL is how long a key is remembered, from the
+// IDEMPOTENCY_KEY_TTL env var such as 24h.
+func IdempotencyKeyTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultIdempotencyKeyTTL
+	}
+
+	return ttl
+}
+
+// Idempotency makes a route safe to retry. The first request with a given
+// Idempotency-Key runs as usual and its response is stored; a retry with the
+// same key and body gets the stored response back without running again.
+// A retry while the first request is still running gets a conflict, unless
+// the first request has held the key past idempotencyLockTimeout. Requests
+// without the header, or from a user that is not logged in, are passed
+// through. Keys are per user and are stored in db, which must have
+// IdempotencyKey migrated.
+func Idempotency(db *gorm.DB) gin.HandlerFunc {
+	ttl := IdempotencyKeyTTL()
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		user := AuthContext(c.Request.Context())
+		if key == "" || user == nil {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, GlobalResponse{
+				Success: false,
+				Message: "Idempotency-Key is too long",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, GlobalResponse{
+				Success: false,
+				Message: "failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+
+		lockedUntil := time.Now().Add(idempotencyLockTimeout)
+		record := IdempotencyKey{
+			UserID:      user.ID,
+			RequestKey:  key,
+			Fingerprint: hex.EncodeToString(hash.Sum(nil)),
+			Status:      string(IDEMPOTENCY_STATUS_PROCESSING),
+			LockedUntil: &lockedUntil,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		// an expired key is forgotten, so it can be used again
+		if err := db.Where("user_id = ? AND request_key = ? AND expires_at <= ?", user.ID, key, time.Now()).Delete(&IdempotencyKey{}).Error; err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, GlobalResponse{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		claim := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+		if claim.Error != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, GlobalResponse{
+				Success: false,
+				Message: claim.Error.Error(),
+			})
+			return
+		}
+
+		if claim.RowsAffected == 0 {
+			stored, takenOver := idempotencyReplay(c, db, record)
+			if !takenOver {
+				return
+			}
+			record = *stored
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		defer func() {
+			// a failed request did not happen as far as the key is
+			// concerned, so it can be retried with the same key
+			if r := recover(); r != nil {
+				db.Delete(&record)
+				panic(r)
+			}
+
+			if writer.Status() >= http.StatusInternalServerError {
+				db.Delete(&record)
+				return
+			}
+
+			if err := db.Model(&record).Updates(map[string]interface{}{
+				"status":          string(IDEMPOTENCY_STATUS_COMPLETED),
+				"locked_until":    nil,
+				"response_status": writer.Status(),
+				"content_type":    writer.Header().Get("Content-Type"),
+				"response_body":   writer.body.Bytes(),
+			}).Error; err != nil {
+				log.Printf("failed to store response for idempotency key %s: %v", key, err)
+			}
+		}()
+
+		c.Next()
+	}
+}
+
+// idempotencyReplay answers a request whose key has been seen before. It
+// reports whether the key was instead taken over from a request that stopped
+// without finishing, in which case the caller runs the request.
+func idempotencyReplay(c *gin.Context, db *gorm.DB, record IdempotencyKey) (*IdempotencyKey, bool) {
+	var stored IdempotencyKey
+
+	if err := db.Where("user_id = ? AND request_key = ?", record.UserID, record.RequestKey).First(&stored).Error; err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, GlobalResponse{
+			Success: false,
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+
+	if stored.Fingerprint != record.Fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, GlobalResponse{
+			Success: false,
+			Message: "Idempotency-Key has already been used for a different request",
+		})
+		return nil, false
+	}
+
+	if stored.Status != string(IDEMPOTENCY_STATUS_COMPLETED) {
+		if stored.LockedUntil == nil || stored.LockedUntil.Before(time.Now()) {
+			// only one retry can move the lock on from the value it read
+			takeover := db.Model(&IdempotencyKey{}).Where("id = ? AND status = ?", stored.ID, IDEMPOTENCY_STATUS_PROCESSING)
+			if stored.LockedUntil == nil {
+				takeover = takeover.Where("locked_until IS NULL")
+			} else {
+				takeover = takeover.Where("locked_until = ?", *stored.LockedUntil)
+			}
+
+			takeover = takeover.Update("locked_until", record.LockedUntil)
+			if takeover.Error != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, GlobalResponse{
+					Success: false,
+					Message: takeover.Error.Error(),
+				})
+				return nil, false
+			}
+			if takeover.RowsAffected == 1 {
+				stored.LockedUntil = record.LockedUntil
+				return &stored, true
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusConflict, GlobalResponse{
+			Success: false,
+			Message: "a request with this Idempotency-Key is still being processed",
+		})
+		return nil, false
+	}
+
+	c.Header(IdempotencyReplayedHeader, "true")
+	c.Data(stored.ResponseStatus, stored.ContentType, stored.ResponseBody)
+	c.Abort()
+
+	return nil, false
+}
+
+// PurgeIdempotencyKeys deletes keys that have expired. It blocks, so run it
+// in its own goroutine.
+func PurgeIdempotencyKeys(db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := db.Where("expires_at <= ?", time.Now()).Delete(&IdempotencyKey{}).Error; err != nil {
+			log.Println("failed to purge idempotency keys:", err)
+		}
+	}
+}
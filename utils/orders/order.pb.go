@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.6
+// 	protoc-gen-go v1.36.8
 // 	protoc        v6.32.0--rc2
 // source: orders/order.proto
 
@@ -191,7 +191,7 @@ type CartResponse struct {
 	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	CreatedAt     string                 `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     string                 `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	CartItems     []*CartItem            `protobuf:"bytes,5,rep,name=cartItems,proto3" json:"cartItems,omitempty"`
+	CartItems     []*CartItem            `protobuf:"bytes,5,rep,name=cart_items,json=cartItems,proto3" json:"cart_items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -305,6 +305,602 @@ func (x *CartRequest) GetUserId() int64 {
 	return 0
 }
 
+type VerifyPurchaseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId     int64                  `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPurchaseRequest) Reset() {
+	*x = VerifyPurchaseRequest{}
+	mi := &file_orders_order_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPurchaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPurchaseRequest) ProtoMessage() {}
+
+func (x *VerifyPurchaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPurchaseRequest.ProtoReflect.Descriptor instead.
+func (*VerifyPurchaseRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyPurchaseRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *VerifyPurchaseRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+type VerifyPurchaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Verified      bool                   `protobuf:"varint,1,opt,name=verified,proto3" json:"verified,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyPurchaseResponse) Reset() {
+	*x = VerifyPurchaseResponse{}
+	mi := &file_orders_order_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyPurchaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyPurchaseResponse) ProtoMessage() {}
+
+func (x *VerifyPurchaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyPurchaseResponse.ProtoReflect.Descriptor instead.
+func (*VerifyPurchaseResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *VerifyPurchaseResponse) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *VerifyPurchaseResponse) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type ProductSalesRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ProductIds []int64                `protobuf:"varint,1,rep,packed,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	// RFC3339; only orders placed at or after this time are counted
+	Since         string `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSalesRequest) Reset() {
+	*x = ProductSalesRequest{}
+	mi := &file_orders_order_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSalesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSalesRequest) ProtoMessage() {}
+
+func (x *ProductSalesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSalesRequest.ProtoReflect.Descriptor instead.
+func (*ProductSalesRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ProductSalesRequest) GetProductIds() []int64 {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *ProductSalesRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+type DailySales struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// YYYY-MM-DD
+	Date          string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	Quantity      int64  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailySales) Reset() {
+	*x = DailySales{}
+	mi := &file_orders_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailySales) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySales) ProtoMessage() {}
+
+func (x *DailySales) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySales.ProtoReflect.Descriptor instead.
+func (*DailySales) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DailySales) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *DailySales) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailySales) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ProductSalesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sales         []*DailySales          `protobuf:"bytes,1,rep,name=sales,proto3" json:"sales,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSalesResponse) Reset() {
+	*x = ProductSalesResponse{}
+	mi := &file_orders_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSalesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSalesResponse) ProtoMessage() {}
+
+func (x *ProductSalesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSalesResponse.ProtoReflect.Descriptor instead.
+func (*ProductSalesResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ProductSalesResponse) GetSales() []*DailySales {
+	if x != nil {
+		return x.Sales
+	}
+	return nil
+}
+
+type CoPurchaseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// RFC3339; only orders placed at or after this time are counted
+	Since         string `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoPurchaseRequest) Reset() {
+	*x = CoPurchaseRequest{}
+	mi := &file_orders_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoPurchaseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoPurchaseRequest) ProtoMessage() {}
+
+func (x *CoPurchaseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoPurchaseRequest.ProtoReflect.Descriptor instead.
+func (*CoPurchaseRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CoPurchaseRequest) GetSince() string {
+	if x != nil {
+		return x.Since
+	}
+	return ""
+}
+
+// CoPurchase is the number of completed orders that contain both products
+type CoPurchase struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ProductId        int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	RelatedProductId int64                  `protobuf:"varint,2,opt,name=related_product_id,json=relatedProductId,proto3" json:"related_product_id,omitempty"`
+	Orders           int64                  `protobuf:"varint,3,opt,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CoPurchase) Reset() {
+	*x = CoPurchase{}
+	mi := &file_orders_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoPurchase) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoPurchase) ProtoMessage() {}
+
+func (x *CoPurchase) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoPurchase.ProtoReflect.Descriptor instead.
+func (*CoPurchase) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CoPurchase) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *CoPurchase) GetRelatedProductId() int64 {
+	if x != nil {
+		return x.RelatedProductId
+	}
+	return 0
+}
+
+func (x *CoPurchase) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+// ProductOrders is the number of completed orders that contain a product
+type ProductOrders struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Orders        int64                  `protobuf:"varint,2,opt,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductOrders) Reset() {
+	*x = ProductOrders{}
+	mi := &file_orders_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductOrders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductOrders) ProtoMessage() {}
+
+func (x *ProductOrders) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductOrders.ProtoReflect.Descriptor instead.
+func (*ProductOrders) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ProductOrders) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ProductOrders) GetOrders() int64 {
+	if x != nil {
+		return x.Orders
+	}
+	return 0
+}
+
+type CoPurchaseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*CoPurchase          `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	Products      []*ProductOrders       `protobuf:"bytes,2,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoPurchaseResponse) Reset() {
+	*x = CoPurchaseResponse{}
+	mi := &file_orders_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoPurchaseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoPurchaseResponse) ProtoMessage() {}
+
+func (x *CoPurchaseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoPurchaseResponse.ProtoReflect.Descriptor instead.
+func (*CoPurchaseResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CoPurchaseResponse) GetPairs() []*CoPurchase {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *CoPurchaseResponse) GetProducts() []*ProductOrders {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// ConfirmPaymentRequest is sent by the payment provider integration once a
+// card payment for an order has been captured
+type ConfirmPaymentRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	OrderId  int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount   float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// the provider's id for the payment, kept in the order's tracking
+	Reference     string `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPaymentRequest) Reset() {
+	*x = ConfirmPaymentRequest{}
+	mi := &file_orders_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPaymentRequest) ProtoMessage() {}
+
+func (x *ConfirmPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPaymentRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConfirmPaymentRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *ConfirmPaymentRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ConfirmPaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ConfirmPaymentRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type ConfirmPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPaymentResponse) Reset() {
+	*x = ConfirmPaymentResponse{}
+	mi := &file_orders_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPaymentResponse) ProtoMessage() {}
+
+func (x *ConfirmPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPaymentResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConfirmPaymentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ConfirmPaymentResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
 var File_orders_order_proto protoreflect.FileDescriptor
 
 const file_orders_order_proto_rawDesc = "" +
@@ -320,20 +916,67 @@ const file_orders_order_proto_rawDesc = "" +
 	"\n" +
 	"product_id\x18\x03 \x01(\x03R\tproductId\x12\x1a\n" +
 	"\bquantity\x18\x04 \x01(\x03R\bquantity\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x02R\x05price\"\xa5\x01\n" +
+	"\x05price\x18\x05 \x01(\x02R\x05price\"\xa6\x01\n" +
 	"\fCartResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\x03 \x01(\tR\tcreatedAt\x12\x1d\n" +
 	"\n" +
-	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x12.\n" +
-	"\tcartItems\x18\x05 \x03(\v2\x10.orders.CartItemR\tcartItems\"&\n" +
+	"updated_at\x18\x04 \x01(\tR\tupdatedAt\x12/\n" +
+	"\n" +
+	"cart_items\x18\x05 \x03(\v2\x10.orders.CartItemR\tcartItems\"&\n" +
 	"\vCartRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\x03R\x06userId2L\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\"O\n" +
+	"\x15VerifyPurchaseRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\x03R\tproductId\"O\n" +
+	"\x16VerifyPurchaseResponse\x12\x1a\n" +
+	"\bverified\x18\x01 \x01(\bR\bverified\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\"L\n" +
+	"\x13ProductSalesRequest\x12\x1f\n" +
+	"\vproduct_ids\x18\x01 \x03(\x03R\n" +
+	"productIds\x12\x14\n" +
+	"\x05since\x18\x02 \x01(\tR\x05since\"[\n" +
+	"\n" +
+	"DailySales\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\"@\n" +
+	"\x14ProductSalesResponse\x12(\n" +
+	"\x05sales\x18\x01 \x03(\v2\x12.orders.DailySalesR\x05sales\")\n" +
+	"\x11CoPurchaseRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\tR\x05since\"q\n" +
+	"\n" +
+	"CoPurchase\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12,\n" +
+	"\x12related_product_id\x18\x02 \x01(\x03R\x10relatedProductId\x12\x16\n" +
+	"\x06orders\x18\x03 \x01(\x03R\x06orders\"F\n" +
+	"\rProductOrders\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x16\n" +
+	"\x06orders\x18\x02 \x01(\x03R\x06orders\"q\n" +
+	"\x12CoPurchaseResponse\x12(\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x12.orders.CoPurchaseR\x05pairs\x121\n" +
+	"\bproducts\x18\x02 \x03(\v2\x15.orders.ProductOrdersR\bproducts\"\x84\x01\n" +
+	"\x15ConfirmPaymentRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\"J\n" +
+	"\x16ConfirmPaymentResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status2\x85\x03\n" +
 	"\x05Order\x12C\n" +
 	"\n" +
-	"CreateCart\x12\x19.orders.CreateCartRequest\x1a\x1a.orders.CreateCartResponseB\x0fZ\r/utils/ordersb\x06proto3"
+	"CreateCart\x12\x19.orders.CreateCartRequest\x1a\x1a.orders.CreateCartResponse\x12O\n" +
+	"\x0eVerifyPurchase\x12\x1d.orders.VerifyPurchaseRequest\x1a\x1e.orders.VerifyPurchaseResponse\x12L\n" +
+	"\x0fGetProductSales\x12\x1b.orders.ProductSalesRequest\x1a\x1c.orders.ProductSalesResponse\x12G\n" +
+	"\x0eGetCoPurchases\x12\x19.orders.CoPurchaseRequest\x1a\x1a.orders.CoPurchaseResponse\x12O\n" +
+	"\x0eConfirmPayment\x12\x1d.orders.ConfirmPaymentRequest\x1a\x1e.orders.ConfirmPaymentResponseB\x0fZ\r/utils/ordersb\x06proto3"
 
 var (
 	file_orders_order_proto_rawDescOnce sync.Once
@@ -347,23 +990,45 @@ func file_orders_order_proto_rawDescGZIP() []byte {
 	return file_orders_order_proto_rawDescData
 }
 
-var file_orders_order_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_orders_order_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_orders_order_proto_goTypes = []any{
-	(*CreateCartRequest)(nil),  // 0: orders.CreateCartRequest
-	(*CreateCartResponse)(nil), // 1: orders.CreateCartResponse
-	(*CartItem)(nil),           // 2: orders.CartItem
-	(*CartResponse)(nil),       // 3: orders.CartResponse
-	(*CartRequest)(nil),        // 4: orders.CartRequest
+	(*CreateCartRequest)(nil),      // 0: orders.CreateCartRequest
+	(*CreateCartResponse)(nil),     // 1: orders.CreateCartResponse
+	(*CartItem)(nil),               // 2: orders.CartItem
+	(*CartResponse)(nil),           // 3: orders.CartResponse
+	(*CartRequest)(nil),            // 4: orders.CartRequest
+	(*VerifyPurchaseRequest)(nil),  // 5: orders.VerifyPurchaseRequest
+	(*VerifyPurchaseResponse)(nil), // 6: orders.VerifyPurchaseResponse
+	(*ProductSalesRequest)(nil),    // 7: orders.ProductSalesRequest
+	(*DailySales)(nil),             // 8: orders.DailySales
+	(*ProductSalesResponse)(nil),   // 9: orders.ProductSalesResponse
+	(*CoPurchaseRequest)(nil),      // 10: orders.CoPurchaseRequest
+	(*CoPurchase)(nil),             // 11: orders.CoPurchase
+	(*ProductOrders)(nil),          // 12: orders.ProductOrders
+	(*CoPurchaseResponse)(nil),     // 13: orders.CoPurchaseResponse
+	(*ConfirmPaymentRequest)(nil),  // 14: orders.ConfirmPaymentRequest
+	(*ConfirmPaymentResponse)(nil), // 15: orders.ConfirmPaymentResponse
 }
 var file_orders_order_proto_depIdxs = []int32{
-	2, // 0: orders.CartResponse.cartItems:type_name -> orders.CartItem
-	0, // 1: orders.Order.CreateCart:input_type -> orders.CreateCartRequest
-	1, // 2: orders.Order.CreateCart:output_type -> orders.CreateCartResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2,  // 0: orders.CartResponse.cart_items:type_name -> orders.CartItem
+	8,  // 1: orders.ProductSalesResponse.sales:type_name -> orders.DailySales
+	11, // 2: orders.CoPurchaseResponse.pairs:type_name -> orders.CoPurchase
+	12, // 3: orders.CoPurchaseResponse.products:type_name -> orders.ProductOrders
+	0,  // 4: orders.Order.CreateCart:input_type -> orders.CreateCartRequest
+	5,  // 5: orders.Order.VerifyPurchase:input_type -> orders.VerifyPurchaseRequest
+	7,  // 6: orders.Order.GetProductSales:input_type -> orders.ProductSalesRequest
+	10, // 7: orders.Order.GetCoPurchases:input_type -> orders.CoPurchaseRequest
+	14, // 8: orders.Order.ConfirmPayment:input_type -> orders.ConfirmPaymentRequest
+	1,  // 9: orders.Order.CreateCart:output_type -> orders.CreateCartResponse
+	6,  // 10: orders.Order.VerifyPurchase:output_type -> orders.VerifyPurchaseResponse
+	9,  // 11: orders.Order.GetProductSales:output_type -> orders.ProductSalesResponse
+	13, // 12: orders.Order.GetCoPurchases:output_type -> orders.CoPurchaseResponse
+	15, // 13: orders.Order.ConfirmPayment:output_type -> orders.ConfirmPaymentResponse
+	9,  // [9:14] is the sub-list for method output_type
+	4,  // [4:9] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_orders_order_proto_init() }
@@ -377,7 +1042,7 @@ func file_orders_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orders_order_proto_rawDesc), len(file_orders_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
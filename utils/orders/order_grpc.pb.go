@@ -19,7 +19,11 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Order_CreateCart_FullMethodName = "/orders.Order/CreateCart"
+	Order_CreateCart_FullMethodName      = "/orders.Order/CreateCart"
+	Order_VerifyPurchase_FullMethodName  = "/orders.Order/VerifyPurchase"
+	Order_GetProductSales_FullMethodName = "/orders.Order/GetProductSales"
+	Order_GetCoPurchases_FullMethodName  = "/orders.Order/GetCoPurchases"
+	Order_ConfirmPayment_FullMethodName  = "/orders.Order/ConfirmPayment"
 )
 
 // OrderClient is the client API for Order service.
@@ -27,6 +31,10 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderClient interface {
 	CreateCart(ctx context.Context, in *CreateCartRequest, opts ...grpc.CallOption) (*CreateCartResponse, error)
+	VerifyPurchase(ctx context.Context, in *VerifyPurchaseRequest, opts ...grpc.CallOption) (*VerifyPurchaseResponse, error)
+	GetProductSales(ctx context.Context, in *ProductSalesRequest, opts ...grpc.CallOption) (*ProductSalesResponse, error)
+	GetCoPurchases(ctx context.Context, in *CoPurchaseRequest, opts ...grpc.CallOption) (*CoPurchaseResponse, error)
+	ConfirmPayment(ctx context.Context, in *ConfirmPaymentRequest, opts ...grpc.CallOption) (*ConfirmPaymentResponse, error)
 }
 
 type orderClient struct {
@@ -47,11 +55,55 @@ func (c *orderClient) CreateCart(ctx context.Context, in *CreateCartRequest, opt
 	return out, nil
 }
 
+func (c *orderClient) VerifyPurchase(ctx context.Context, in *VerifyPurchaseRequest, opts ...grpc.CallOption) (*VerifyPurchaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyPurchaseResponse)
+	err := c.cc.Invoke(ctx, Order_VerifyPurchase_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderClient) GetProductSales(ctx context.Context, in *ProductSalesRequest, opts ...grpc.CallOption) (*ProductSalesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProductSalesResponse)
+	err := c.cc.Invoke(ctx, Order_GetProductSales_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderClient) GetCoPurchases(ctx context.Context, in *CoPurchaseRequest, opts ...grpc.CallOption) (*CoPurchaseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CoPurchaseResponse)
+	err := c.cc.Invoke(ctx, Order_GetCoPurchases_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderClient) ConfirmPayment(ctx context.Context, in *ConfirmPaymentRequest, opts ...grpc.CallOption) (*ConfirmPaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPaymentResponse)
+	err := c.cc.Invoke(ctx, Order_ConfirmPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServer is the server API for Order service.
 // All implementations must embed UnimplementedOrderServer
 // for forward compatibility.
 type OrderServer interface {
 	CreateCart(context.Context, *CreateCartRequest) (*CreateCartResponse, error)
+	VerifyPurchase(context.Context, *VerifyPurchaseRequest) (*VerifyPurchaseResponse, error)
+	GetProductSales(context.Context, *ProductSalesRequest) (*ProductSalesResponse, error)
+	GetCoPurchases(context.Context, *CoPurchaseRequest) (*CoPurchaseResponse, error)
+	ConfirmPayment(context.Context, *ConfirmPaymentRequest) (*ConfirmPaymentResponse, error)
 	mustEmbedUnimplementedOrderServer()
 }
 
@@ -65,6 +117,18 @@ type UnimplementedOrderServer struct{}
 func (UnimplementedOrderServer) CreateCart(context.Context, *CreateCartRequest) (*CreateCartResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateCart not implemented")
 }
+func (UnimplementedOrderServer) VerifyPurchase(context.Context, *VerifyPurchaseRequest) (*VerifyPurchaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyPurchase not implemented")
+}
+func (UnimplementedOrderServer) GetProductSales(context.Context, *ProductSalesRequest) (*ProductSalesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductSales not implemented")
+}
+func (UnimplementedOrderServer) GetCoPurchases(context.Context, *CoPurchaseRequest) (*CoPurchaseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCoPurchases not implemented")
+}
+func (UnimplementedOrderServer) ConfirmPayment(context.Context, *ConfirmPaymentRequest) (*ConfirmPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmPayment not implemented")
+}
 func (UnimplementedOrderServer) mustEmbedUnimplementedOrderServer() {}
 func (UnimplementedOrderServer) testEmbeddedByValue()               {}
 
@@ -104,6 +168,78 @@ func _Order_CreateCart_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Order_VerifyPurchase_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyPurchaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).VerifyPurchase(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_VerifyPurchase_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).VerifyPurchase(ctx, req.(*VerifyPurchaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Order_GetProductSales_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProductSalesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).GetProductSales(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_GetProductSales_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).GetProductSales(ctx, req.(*ProductSalesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Order_GetCoPurchases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CoPurchaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).GetCoPurchases(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_GetCoPurchases_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).GetCoPurchases(ctx, req.(*CoPurchaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Order_ConfirmPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServer).ConfirmPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Order_ConfirmPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServer).ConfirmPayment(ctx, req.(*ConfirmPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Order_ServiceDesc is the grpc.ServiceDesc for Order service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +251,22 @@ var Order_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateCart",
 			Handler:    _Order_CreateCart_Handler,
 		},
+		{
+			MethodName: "VerifyPurchase",
+			Handler:    _Order_VerifyPurchase_Handler,
+		},
+		{
+			MethodName: "GetProductSales",
+			Handler:    _Order_GetProductSales_Handler,
+		},
+		{
+			MethodName: "GetCoPurchases",
+			Handler:    _Order_GetCoPurchases_Handler,
+		},
+		{
+			MethodName: "ConfirmPayment",
+			Handler:    _Order_ConfirmPayment_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orders/order.proto",
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.6
+// 	protoc-gen-go v1.36.8
 // 	protoc        v6.32.0--rc2
 // source: utils/product/product.proto
 
@@ -22,15 +22,47 @@ const (
 )
 
 type GetProductDetailsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	SellerId      int64                  `protobuf:"varint,2,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
-	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int64                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
-	Sku           string                 `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"`
-	ShopName      string                 `protobuf:"bytes,8,opt,name=shop_name,json=shopName,proto3" json:"shop_name,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId     int64                  `protobuf:"varint,2,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Name         string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description  string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Price        float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Stock        int64                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	Sku          string                 `protobuf:"bytes,7,opt,name=sku,proto3" json:"sku,omitempty"`
+	ShopName     string                 `protobuf:"bytes,8,opt,name=shop_name,json=shopName,proto3" json:"shop_name,omitempty"`
+	CategoryPath string                 `protobuf:"bytes,9,opt,name=category_path,json=categoryPath,proto3" json:"category_path,omitempty"`
+	ImageUrls    []string               `protobuf:"bytes,10,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
+	PrimaryImage string                 `protobuf:"bytes,11,opt,name=primary_image,json=primaryImage,proto3" json:"primary_image,omitempty"`
+	HasVariants  bool                   `protobuf:"varint,12,opt,name=has_variants,json=hasVariants,proto3" json:"has_variants,omitempty"`
+	// set when the request names a variant; price, stock and sku above then
+	// describe that variant instead of the base product
+	Variant *ProductVariant `protobuf:"bytes,13,opt,name=variant,proto3" json:"variant,omitempty"`
+	// price above is the effective price; list_price is what it is marked
+	// down from while a sale is running
+	ListPrice      float64 `protobuf:"fixed64,14,opt,name=list_price,json=listPrice,proto3" json:"list_price,omitempty"`
+	EffectivePrice float64 `protobuf:"fixed64,15,opt,name=effective_price,json=effectivePrice,proto3" json:"effective_price,omitempty"`
+	OnSale         bool    `protobuf:"varint,16,opt,name=on_sale,json=onSale,proto3" json:"on_sale,omitempty"`
+	// stock held at each warehouse; empty when stock is not tracked per location
+	Locations []*LocationStock `protobuf:"bytes,17,rep,name=locations,proto3" json:"locations,omitempty"`
+	Deleted   bool             `protobuf:"varint,18,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	// lifecycle status; only active products can be bought
+	Status string `protobuf:"bytes,19,opt,name=status,proto3" json:"status,omitempty"`
+	// typed specs filled in by the seller, in display order
+	Attributes []*ProductAttribute `protobuf:"bytes,20,rep,name=attributes,proto3" json:"attributes,omitempty"`
+	// simple or bundle
+	Type string `protobuf:"bytes,21,opt,name=type,proto3" json:"type,omitempty"`
+	// what one unit of a bundle is made of; empty for simple products
+	Components []*BundleComponent `protobuf:"bytes,22,rep,name=components,proto3" json:"components,omitempty"`
+	// currency every price above is in: the requested one, or base_currency
+	// when none was asked for
+	Currency string `protobuf:"bytes,23,opt,name=currency,proto3" json:"currency,omitempty"`
+	// currency the seller listed the product in
+	BaseCurrency string `protobuf:"bytes,24,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	// units of currency per unit of base_currency; 1 when not converted
+	ExchangeRate float64 `protobuf:"fixed64,25,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`
+	// matched against the tax rules of the destination region
+	TaxCategory   string `protobuf:"bytes,26,opt,name=tax_category,json=taxCategory,proto3" json:"tax_category,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -121,16 +153,223 @@ func (x *GetProductDetailsResponse) GetShopName() string {
 	return ""
 }
 
-type GetProductDetailsRequest struct {
+func (x *GetProductDetailsResponse) GetCategoryPath() string {
+	if x != nil {
+		return x.CategoryPath
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetImageUrls() []string {
+	if x != nil {
+		return x.ImageUrls
+	}
+	return nil
+}
+
+func (x *GetProductDetailsResponse) GetPrimaryImage() string {
+	if x != nil {
+		return x.PrimaryImage
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetHasVariants() bool {
+	if x != nil {
+		return x.HasVariants
+	}
+	return false
+}
+
+func (x *GetProductDetailsResponse) GetVariant() *ProductVariant {
+	if x != nil {
+		return x.Variant
+	}
+	return nil
+}
+
+func (x *GetProductDetailsResponse) GetListPrice() float64 {
+	if x != nil {
+		return x.ListPrice
+	}
+	return 0
+}
+
+func (x *GetProductDetailsResponse) GetEffectivePrice() float64 {
+	if x != nil {
+		return x.EffectivePrice
+	}
+	return 0
+}
+
+func (x *GetProductDetailsResponse) GetOnSale() bool {
+	if x != nil {
+		return x.OnSale
+	}
+	return false
+}
+
+func (x *GetProductDetailsResponse) GetLocations() []*LocationStock {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+func (x *GetProductDetailsResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *GetProductDetailsResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetAttributes() []*ProductAttribute {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *GetProductDetailsResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetComponents() []*BundleComponent {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+func (x *GetProductDetailsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *GetProductDetailsResponse) GetExchangeRate() float64 {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return 0
+}
+
+func (x *GetProductDetailsResponse) GetTaxCategory() string {
+	if x != nil {
+		return x.TaxCategory
+	}
+	return ""
+}
+
+type ProductVariant struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int64                  `protobuf:"varint,4,opt,name=stock,proto3" json:"stock,omitempty"`
+	Options       map[string]string      `protobuf:"bytes,5,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductVariant) Reset() {
+	*x = ProductVariant{}
+	mi := &file_utils_product_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariant) ProtoMessage() {}
+
+func (x *ProductVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariant.ProtoReflect.Descriptor instead.
+func (*ProductVariant) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductVariant) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GetProductDetailsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	VariantId int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	// deleted products are reported as not found unless this is set
+	IncludeDeleted bool `protobuf:"varint,3,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	// converts prices to this currency; empty keeps the listing currency
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetProductDetailsRequest) Reset() {
 	*x = GetProductDetailsRequest{}
-	mi := &file_utils_product_product_proto_msgTypes[1]
+	mi := &file_utils_product_product_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -142,7 +381,7 @@ func (x *GetProductDetailsRequest) String() string {
 func (*GetProductDetailsRequest) ProtoMessage() {}
 
 func (x *GetProductDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_utils_product_product_proto_msgTypes[1]
+	mi := &file_utils_product_product_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -155,7 +394,7 @@ func (x *GetProductDetailsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductDetailsRequest.ProtoReflect.Descriptor instead.
 func (*GetProductDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_utils_product_product_proto_rawDescGZIP(), []int{1}
+	return file_utils_product_product_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetProductDetailsRequest) GetId() int64 {
@@ -165,17 +404,40 @@ func (x *GetProductDetailsRequest) GetId() int64 {
 	return 0
 }
 
+func (x *GetProductDetailsRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *GetProductDetailsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *GetProductDetailsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type UpdateStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	QtyBought     int64                  `protobuf:"varint,2,opt,name=qty_bought,json=qtyBought,proto3" json:"qty_bought,omitempty"`
+	VariantId     int64                  `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateStockRequest) Reset() {
 	*x = UpdateStockRequest{}
-	mi := &file_utils_product_product_proto_msgTypes[2]
+	mi := &file_utils_product_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -187,7 +449,7 @@ func (x *UpdateStockRequest) String() string {
 func (*UpdateStockRequest) ProtoMessage() {}
 
 func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_utils_product_product_proto_msgTypes[2]
+	mi := &file_utils_product_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -200,7 +462,7 @@ func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStockRequest.ProtoReflect.Descriptor instead.
 func (*UpdateStockRequest) Descriptor() ([]byte, []int) {
-	return file_utils_product_product_proto_rawDescGZIP(), []int{2}
+	return file_utils_product_product_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *UpdateStockRequest) GetId() int64 {
@@ -217,16 +479,31 @@ func (x *UpdateStockRequest) GetQtyBought() int64 {
 	return 0
 }
 
+func (x *UpdateStockRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *UpdateStockRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
 type UpdateStockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Allocations   []*StockAllocation     `protobuf:"bytes,2,rep,name=allocations,proto3" json:"allocations,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateStockResponse) Reset() {
 	*x = UpdateStockResponse{}
-	mi := &file_utils_product_product_proto_msgTypes[3]
+	mi := &file_utils_product_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -238,7 +515,7 @@ func (x *UpdateStockResponse) String() string {
 func (*UpdateStockResponse) ProtoMessage() {}
 
 func (x *UpdateStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_utils_product_product_proto_msgTypes[3]
+	mi := &file_utils_product_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -251,7 +528,7 @@ func (x *UpdateStockResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateStockResponse.ProtoReflect.Descriptor instead.
 func (*UpdateStockResponse) Descriptor() ([]byte, []int) {
-	return file_utils_product_product_proto_rawDescGZIP(), []int{3}
+	return file_utils_product_product_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UpdateStockResponse) GetSuccess() bool {
@@ -261,31 +538,1905 @@ func (x *UpdateStockResponse) GetSuccess() bool {
 	return false
 }
 
-var File_utils_product_product_proto protoreflect.FileDescriptor
+func (x *UpdateStockResponse) GetAllocations() []*StockAllocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
 
-const file_utils_product_product_proto_rawDesc = "" +
-	"\n" +
-	"\x1butils/product/product.proto\x12\aproduct\"\xd9\x01\n" +
-	"\x19GetProductDetailsResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
-	"\tseller_id\x18\x02 \x01(\x03R\bsellerId\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
-	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x14\n" +
-	"\x05stock\x18\x06 \x01(\x03R\x05stock\x12\x10\n" +
-	"\x03sku\x18\a \x01(\tR\x03sku\x12\x1b\n" +
-	"\tshop_name\x18\b \x01(\tR\bshopName\"*\n" +
-	"\x18GetProductDetailsRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"C\n" +
-	"\x12UpdateStockRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
-	"\n" +
-	"qty_bought\x18\x02 \x01(\x03R\tqtyBought\"/\n" +
-	"\x13UpdateStockResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xaf\x01\n" +
+type SearchProductsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Query        string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	MinPrice     *float64               `protobuf:"fixed64,2,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice     *float64               `protobuf:"fixed64,3,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	SellerId     *int64                 `protobuf:"varint,4,opt,name=seller_id,json=sellerId,proto3,oneof" json:"seller_id,omitempty"`
+	InStockOnly  bool                   `protobuf:"varint,5,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	CreatedAfter string                 `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	Sort         string                 `protobuf:"bytes,7,opt,name=sort,proto3" json:"sort,omitempty"`
+	Cursor       string                 `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit        int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	CategoryId   *int64                 `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	// currency min_price and max_price are given in; defaults to the
+	// reference currency
+	Currency      string `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetSellerId() int64 {
+	if x != nil && x.SellerId != nil {
+		return *x.SellerId
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetInStockOnly() bool {
+	if x != nil {
+		return x.InStockOnly
+	}
+	return false
+}
+
+func (x *SearchProductsRequest) GetCreatedAfter() string {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetCategoryId() int64 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ProductItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId      int64                  `protobuf:"varint,2,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Price         float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	Stock         int64                  `protobuf:"varint,6,opt,name=stock,proto3" json:"stock,omitempty"`
+	ShopName      string                 `protobuf:"bytes,7,opt,name=shop_name,json=shopName,proto3" json:"shop_name,omitempty"`
+	Sku           string                 `protobuf:"bytes,8,opt,name=sku,proto3" json:"sku,omitempty"`
+	SoldCount     int64                  `protobuf:"varint,9,opt,name=sold_count,json=soldCount,proto3" json:"sold_count,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	RatingAverage float64                `protobuf:"fixed64,12,opt,name=rating_average,json=ratingAverage,proto3" json:"rating_average,omitempty"`
+	RatingCount   int64                  `protobuf:"varint,13,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	Currency      string                 `protobuf:"bytes,14,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductItem) Reset() {
+	*x = ProductItem{}
+	mi := &file_utils_product_product_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductItem) ProtoMessage() {}
+
+func (x *ProductItem) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductItem.ProtoReflect.Descriptor instead.
+func (*ProductItem) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProductItem) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductItem) GetSellerId() int64 {
+	if x != nil {
+		return x.SellerId
+	}
+	return 0
+}
+
+func (x *ProductItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ProductItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *ProductItem) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductItem) GetShopName() string {
+	if x != nil {
+		return x.ShopName
+	}
+	return ""
+}
+
+func (x *ProductItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductItem) GetSoldCount() int64 {
+	if x != nil {
+		return x.SoldCount
+	}
+	return 0
+}
+
+func (x *ProductItem) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *ProductItem) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *ProductItem) GetRatingAverage() float64 {
+	if x != nil {
+		return x.RatingAverage
+	}
+	return 0
+}
+
+func (x *ProductItem) GetRatingCount() int64 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *ProductItem) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type SearchProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductItem         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SearchProductsResponse) GetProducts() []*ProductItem {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type StockItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_utils_product_product_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockItem) ProtoMessage() {}
+
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StockItem) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockItem) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int64                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	ExpiresAt     string                 `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Allocations   []*StockAllocation     `protobuf:"bytes,3,rep,name=allocations,proto3" json:"allocations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReserveStockResponse) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+func (x *ReserveStockResponse) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *ReserveStockResponse) GetAllocations() []*StockAllocation {
+	if x != nil {
+		return x.Allocations
+	}
+	return nil
+}
+
+type ReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int64                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	OrderId       int64                  `protobuf:"varint,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationRequest) Reset() {
+	*x = ReservationRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationRequest) ProtoMessage() {}
+
+func (x *ReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReservationRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReservationRequest) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+func (x *ReservationRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type ReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationResponse) Reset() {
+	*x = ReservationResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationResponse) ProtoMessage() {}
+
+func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReservationResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReservationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// reason is order_cancel or return. reference names the restock, so a retried
+// call is only applied once; applied is false for such a retry.
+type IncrementStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Reference     string                 `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+	Items         []*StockItem           `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementStockRequest) Reset() {
+	*x = IncrementStockRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementStockRequest) ProtoMessage() {}
+
+func (x *IncrementStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementStockRequest.ProtoReflect.Descriptor instead.
+func (*IncrementStockRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IncrementStockRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *IncrementStockRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *IncrementStockRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *IncrementStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type IncrementStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Applied       bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrementStockResponse) Reset() {
+	*x = IncrementStockResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrementStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrementStockResponse) ProtoMessage() {}
+
+func (x *IncrementStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrementStockResponse.ProtoReflect.Descriptor instead.
+func (*IncrementStockResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *IncrementStockResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *IncrementStockResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+type BundleComponent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Sku           string                 `protobuf:"bytes,5,opt,name=sku,proto3" json:"sku,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BundleComponent) Reset() {
+	*x = BundleComponent{}
+	mi := &file_utils_product_product_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BundleComponent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BundleComponent) ProtoMessage() {}
+
+func (x *BundleComponent) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BundleComponent.ProtoReflect.Descriptor instead.
+func (*BundleComponent) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BundleComponent) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *BundleComponent) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *BundleComponent) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *BundleComponent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BundleComponent) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+type ProductAttribute struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Unit          string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	Value         string                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductAttribute) Reset() {
+	*x = ProductAttribute{}
+	mi := &file_utils_product_product_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductAttribute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductAttribute) ProtoMessage() {}
+
+func (x *ProductAttribute) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductAttribute.ProtoReflect.Descriptor instead.
+func (*ProductAttribute) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ProductAttribute) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ProductAttribute) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductAttribute) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProductAttribute) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *ProductAttribute) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type LocationStock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WarehouseId   int64                  `protobuf:"varint,1,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	WarehouseName string                 `protobuf:"bytes,2,opt,name=warehouse_name,json=warehouseName,proto3" json:"warehouse_name,omitempty"`
+	Priority      int64                  `protobuf:"varint,3,opt,name=priority,proto3" json:"priority,omitempty"`
+	VariantId     int64                  `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Stock         int64                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocationStock) Reset() {
+	*x = LocationStock{}
+	mi := &file_utils_product_product_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocationStock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocationStock) ProtoMessage() {}
+
+func (x *LocationStock) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocationStock.ProtoReflect.Descriptor instead.
+func (*LocationStock) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *LocationStock) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *LocationStock) GetWarehouseName() string {
+	if x != nil {
+		return x.WarehouseName
+	}
+	return ""
+}
+
+func (x *LocationStock) GetPriority() int64 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *LocationStock) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *LocationStock) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+type StockAllocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	WarehouseId   int64                  `protobuf:"varint,3,opt,name=warehouse_id,json=warehouseId,proto3" json:"warehouse_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockAllocation) Reset() {
+	*x = StockAllocation{}
+	mi := &file_utils_product_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockAllocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockAllocation) ProtoMessage() {}
+
+func (x *StockAllocation) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockAllocation.ProtoReflect.Descriptor instead.
+func (*StockAllocation) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *StockAllocation) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockAllocation) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockAllocation) GetWarehouseId() int64 {
+	if x != nil {
+		return x.WarehouseId
+	}
+	return 0
+}
+
+func (x *StockAllocation) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type StockAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockAvailabilityRequest) Reset() {
+	*x = StockAvailabilityRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockAvailabilityRequest) ProtoMessage() {}
+
+func (x *StockAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*StockAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *StockAvailabilityRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type StockAvailability struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Stock         int64                  `protobuf:"varint,3,opt,name=stock,proto3" json:"stock,omitempty"`
+	Locations     []*LocationStock       `protobuf:"bytes,4,rep,name=locations,proto3" json:"locations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockAvailability) Reset() {
+	*x = StockAvailability{}
+	mi := &file_utils_product_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockAvailability) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockAvailability) ProtoMessage() {}
+
+func (x *StockAvailability) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockAvailability.ProtoReflect.Descriptor instead.
+func (*StockAvailability) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *StockAvailability) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockAvailability) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *StockAvailability) GetStock() int64 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *StockAvailability) GetLocations() []*LocationStock {
+	if x != nil {
+		return x.Locations
+	}
+	return nil
+}
+
+type StockAvailabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockAvailability   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockAvailabilityResponse) Reset() {
+	*x = StockAvailabilityResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockAvailabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockAvailabilityResponse) ProtoMessage() {}
+
+func (x *StockAvailabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockAvailabilityResponse.ProtoReflect.Descriptor instead.
+func (*StockAvailabilityResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *StockAvailabilityResponse) GetItems() []*StockAvailability {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ProductLookup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	VariantId     int64                  `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductLookup) Reset() {
+	*x = ProductLookup{}
+	mi := &file_utils_product_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductLookup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductLookup) ProtoMessage() {}
+
+func (x *ProductLookup) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductLookup.ProtoReflect.Descriptor instead.
+func (*ProductLookup) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ProductLookup) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductLookup) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+type GetProductsDetailsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Items          []*ProductLookup       `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	IncludeDeleted bool                   `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	// converts prices to this currency; empty keeps the listing currency
+	Currency      string `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsDetailsRequest) Reset() {
+	*x = GetProductsDetailsRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsDetailsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsDetailsRequest) ProtoMessage() {}
+
+func (x *GetProductsDetailsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsDetailsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsDetailsRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetProductsDetailsRequest) GetItems() []*ProductLookup {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetProductsDetailsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *GetProductsDetailsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ProductDetailsResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Found bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	// why the product could not be returned when found is false
+	Error         string                     `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Product       *GetProductDetailsResponse `protobuf:"bytes,3,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductDetailsResult) Reset() {
+	*x = ProductDetailsResult{}
+	mi := &file_utils_product_product_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductDetailsResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductDetailsResult) ProtoMessage() {}
+
+func (x *ProductDetailsResult) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductDetailsResult.ProtoReflect.Descriptor instead.
+func (*ProductDetailsResult) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ProductDetailsResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *ProductDetailsResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ProductDetailsResult) GetProduct() *GetProductDetailsResponse {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type GetProductsDetailsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// keyed by LookupKey(id, variant_id)
+	Products      map[string]*ProductDetailsResult `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsDetailsResponse) Reset() {
+	*x = GetProductsDetailsResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsDetailsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsDetailsResponse) ProtoMessage() {}
+
+func (x *GetProductsDetailsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsDetailsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsDetailsResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetProductsDetailsResponse) GetProducts() map[string]*ProductDetailsResult {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+type RecommendationRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// defaults to 8 when not set
+	Limit         int64 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecommendationRequest) Reset() {
+	*x = RecommendationRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendationRequest) ProtoMessage() {}
+
+func (x *RecommendationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendationRequest.ProtoReflect.Descriptor instead.
+func (*RecommendationRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RecommendationRequest) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *RecommendationRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Recommendation struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId int64                  `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Price     float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	ShopName  string                 `protobuf:"bytes,4,opt,name=shop_name,json=shopName,proto3" json:"shop_name,omitempty"`
+	// co_purchase, category or seller
+	Source        string  `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Score         float64 `protobuf:"fixed64,6,opt,name=score,proto3" json:"score,omitempty"`
+	Currency      string  `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Recommendation) Reset() {
+	*x = Recommendation{}
+	mi := &file_utils_product_product_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Recommendation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Recommendation) ProtoMessage() {}
+
+func (x *Recommendation) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Recommendation.ProtoReflect.Descriptor instead.
+func (*Recommendation) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Recommendation) GetProductId() int64 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *Recommendation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Recommendation) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Recommendation) GetShopName() string {
+	if x != nil {
+		return x.ShopName
+	}
+	return ""
+}
+
+func (x *Recommendation) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Recommendation) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *Recommendation) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type RecommendationResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Recommendations []*Recommendation      `protobuf:"bytes,1,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RecommendationResponse) Reset() {
+	*x = RecommendationResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendationResponse) ProtoMessage() {}
+
+func (x *RecommendationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendationResponse.ProtoReflect.Descriptor instead.
+func (*RecommendationResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RecommendationResponse) GetRecommendations() []*Recommendation {
+	if x != nil {
+		return x.Recommendations
+	}
+	return nil
+}
+
+type GetCurrenciesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrenciesRequest) Reset() {
+	*x = GetCurrenciesRequest{}
+	mi := &file_utils_product_product_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrenciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrenciesRequest) ProtoMessage() {}
+
+func (x *GetCurrenciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrenciesRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrenciesRequest) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{29}
+}
+
+// GetCurrenciesResponse lists the currencies products can be priced in: the
+// reference currency and every currency with an exchange rate
+type GetCurrenciesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reference     string                 `protobuf:"bytes,1,opt,name=reference,proto3" json:"reference,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrenciesResponse) Reset() {
+	*x = GetCurrenciesResponse{}
+	mi := &file_utils_product_product_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrenciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrenciesResponse) ProtoMessage() {}
+
+func (x *GetCurrenciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_utils_product_product_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrenciesResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrenciesResponse) Descriptor() ([]byte, []int) {
+	return file_utils_product_product_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetCurrenciesResponse) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *GetCurrenciesResponse) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+var File_utils_product_product_proto protoreflect.FileDescriptor
+
+const file_utils_product_product_proto_rawDesc = "" +
+	"\n" +
+	"\x1butils/product/product.proto\x12\aproduct\"\xf3\x06\n" +
+	"\x19GetProductDetailsResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tseller_id\x18\x02 \x01(\x03R\bsellerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x03R\x05stock\x12\x10\n" +
+	"\x03sku\x18\a \x01(\tR\x03sku\x12\x1b\n" +
+	"\tshop_name\x18\b \x01(\tR\bshopName\x12#\n" +
+	"\rcategory_path\x18\t \x01(\tR\fcategoryPath\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\n" +
+	" \x03(\tR\timageUrls\x12#\n" +
+	"\rprimary_image\x18\v \x01(\tR\fprimaryImage\x12!\n" +
+	"\fhas_variants\x18\f \x01(\bR\vhasVariants\x121\n" +
+	"\avariant\x18\r \x01(\v2\x17.product.ProductVariantR\avariant\x12\x1d\n" +
+	"\n" +
+	"list_price\x18\x0e \x01(\x01R\tlistPrice\x12'\n" +
+	"\x0feffective_price\x18\x0f \x01(\x01R\x0eeffectivePrice\x12\x17\n" +
+	"\aon_sale\x18\x10 \x01(\bR\x06onSale\x124\n" +
+	"\tlocations\x18\x11 \x03(\v2\x16.product.LocationStockR\tlocations\x12\x18\n" +
+	"\adeleted\x18\x12 \x01(\bR\adeleted\x12\x16\n" +
+	"\x06status\x18\x13 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"attributes\x18\x14 \x03(\v2\x19.product.ProductAttributeR\n" +
+	"attributes\x12\x12\n" +
+	"\x04type\x18\x15 \x01(\tR\x04type\x128\n" +
+	"\n" +
+	"components\x18\x16 \x03(\v2\x18.product.BundleComponentR\n" +
+	"components\x12\x1a\n" +
+	"\bcurrency\x18\x17 \x01(\tR\bcurrency\x12#\n" +
+	"\rbase_currency\x18\x18 \x01(\tR\fbaseCurrency\x12#\n" +
+	"\rexchange_rate\x18\x19 \x01(\x01R\fexchangeRate\x12!\n" +
+	"\ftax_category\x18\x1a \x01(\tR\vtaxCategory\"\xda\x01\n" +
+	"\x0eProductVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x04 \x01(\x03R\x05stock\x12>\n" +
+	"\aoptions\x18\x05 \x03(\v2$.product.ProductVariant.OptionsEntryR\aoptions\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x8e\x01\n" +
+	"\x18GetProductDetailsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12'\n" +
+	"\x0finclude_deleted\x18\x03 \x01(\bR\x0eincludeDeleted\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"}\n" +
+	"\x12UpdateStockRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"qty_bought\x18\x02 \x01(\x03R\tqtyBought\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\x03R\tvariantId\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\x03R\aorderId\"k\n" +
+	"\x13UpdateStockResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12:\n" +
+	"\vallocations\x18\x02 \x03(\v2\x18.product.StockAllocationR\vallocations\"\x9a\x03\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12 \n" +
+	"\tmin_price\x18\x02 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x03 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01\x12 \n" +
+	"\tseller_id\x18\x04 \x01(\x03H\x02R\bsellerId\x88\x01\x01\x12\"\n" +
+	"\rin_stock_only\x18\x05 \x01(\bR\vinStockOnly\x12#\n" +
+	"\rcreated_after\x18\x06 \x01(\tR\fcreatedAfter\x12\x12\n" +
+	"\x04sort\x18\a \x01(\tR\x04sort\x12\x16\n" +
+	"\x06cursor\x18\b \x01(\tR\x06cursor\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\x12$\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\x03H\x03R\n" +
+	"categoryId\x88\x01\x01\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrencyB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_priceB\f\n" +
+	"\n" +
+	"_seller_idB\x0e\n" +
+	"\f_category_id\"\x8e\x03\n" +
+	"\vProductItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tseller_id\x18\x02 \x01(\x03R\bsellerId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x14\n" +
+	"\x05stock\x18\x06 \x01(\x03R\x05stock\x12\x1b\n" +
+	"\tshop_name\x18\a \x01(\tR\bshopName\x12\x10\n" +
+	"\x03sku\x18\b \x01(\tR\x03sku\x12\x1d\n" +
+	"\n" +
+	"sold_count\x18\t \x01(\x03R\tsoldCount\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12%\n" +
+	"\x0erating_average\x18\f \x01(\x01R\rratingAverage\x12!\n" +
+	"\frating_count\x18\r \x01(\x03R\vratingCount\x12\x1a\n" +
+	"\bcurrency\x18\x0e \x01(\tR\bcurrency\"k\n" +
+	"\x16SearchProductsResponse\x120\n" +
+	"\bproducts\x18\x01 \x03(\v2\x14.product.ProductItemR\bproducts\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"e\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\"`\n" +
+	"\x13ReserveStockRequest\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.product.StockItemR\x05items\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"\x98\x01\n" +
+	"\x14ReserveStockResponse\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x03R\rreservationId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\tR\texpiresAt\x12:\n" +
+	"\vallocations\x18\x03 \x03(\v2\x18.product.StockAllocationR\vallocations\"V\n" +
+	"\x12ReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x03R\rreservationId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\x03R\aorderId\"/\n" +
+	"\x13ReservationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x92\x01\n" +
+	"\x15IncrementStockRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1c\n" +
+	"\treference\x18\x03 \x01(\tR\treference\x12(\n" +
+	"\x05items\x18\x04 \x03(\v2\x12.product.StockItemR\x05items\"L\n" +
+	"\x16IncrementStockResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\"\x91\x01\n" +
+	"\x0fBundleComponent\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x10\n" +
+	"\x03sku\x18\x05 \x01(\tR\x03sku\"x\n" +
+	"\x10ProductAttribute\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\tR\x05value\"\xaa\x01\n" +
+	"\rLocationStock\x12!\n" +
+	"\fwarehouse_id\x18\x01 \x01(\x03R\vwarehouseId\x12%\n" +
+	"\x0ewarehouse_name\x18\x02 \x01(\tR\rwarehouseName\x12\x1a\n" +
+	"\bpriority\x18\x03 \x01(\x03R\bpriority\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\x03R\tvariantId\x12\x14\n" +
+	"\x05stock\x18\x05 \x01(\x03R\x05stock\"\x8e\x01\n" +
+	"\x0fStockAllocation\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12!\n" +
+	"\fwarehouse_id\x18\x03 \x01(\x03R\vwarehouseId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x03R\bquantity\"D\n" +
+	"\x18StockAvailabilityRequest\x12(\n" +
+	"\x05items\x18\x01 \x03(\v2\x12.product.StockItemR\x05items\"\x9d\x01\n" +
+	"\x11StockAvailability\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x14\n" +
+	"\x05stock\x18\x03 \x01(\x03R\x05stock\x124\n" +
+	"\tlocations\x18\x04 \x03(\v2\x16.product.LocationStockR\tlocations\"M\n" +
+	"\x19StockAvailabilityResponse\x120\n" +
+	"\x05items\x18\x01 \x03(\v2\x1a.product.StockAvailabilityR\x05items\">\n" +
+	"\rProductLookup\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\"\x8e\x01\n" +
+	"\x19GetProductsDetailsRequest\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.product.ProductLookupR\x05items\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\x80\x01\n" +
+	"\x14ProductDetailsResult\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12<\n" +
+	"\aproduct\x18\x03 \x01(\v2\".product.GetProductDetailsResponseR\aproduct\"\xc7\x01\n" +
+	"\x1aGetProductsDetailsResponse\x12M\n" +
+	"\bproducts\x18\x01 \x03(\v21.product.GetProductsDetailsResponse.ProductsEntryR\bproducts\x1aZ\n" +
+	"\rProductsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x123\n" +
+	"\x05value\x18\x02 \x01(\v2\x1d.product.ProductDetailsResultR\x05value:\x028\x01\"L\n" +
+	"\x15RecommendationRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\"\xc0\x01\n" +
+	"\x0eRecommendation\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\x03R\tproductId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1b\n" +
+	"\tshop_name\x18\x04 \x01(\tR\bshopName\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12\x14\n" +
+	"\x05score\x18\x06 \x01(\x01R\x05score\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"[\n" +
+	"\x16RecommendationResponse\x12A\n" +
+	"\x0frecommendations\x18\x01 \x03(\v2\x17.product.RecommendationR\x0frecommendations\"\x16\n" +
+	"\x14GetCurrenciesRequest\"U\n" +
+	"\x15GetCurrenciesResponse\x12\x1c\n" +
+	"\treference\x18\x01 \x01(\tR\treference\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies2\xa8\a\n" +
 	"\aProduct\x12Z\n" +
 	"\x11GetProductDetails\x12!.product.GetProductDetailsRequest\x1a\".product.GetProductDetailsResponse\x12H\n" +
-	"\vUpdateStock\x12\x1b.product.UpdateStockRequest\x1a\x1c.product.UpdateStockResponseB\x10Z\x0e/utils/productb\x06proto3"
+	"\vUpdateStock\x12\x1b.product.UpdateStockRequest\x1a\x1c.product.UpdateStockResponse\x12Q\n" +
+	"\x0eSearchProducts\x12\x1e.product.SearchProductsRequest\x1a\x1f.product.SearchProductsResponse\x12K\n" +
+	"\fReserveStock\x12\x1c.product.ReserveStockRequest\x1a\x1d.product.ReserveStockResponse\x12N\n" +
+	"\x11CommitReservation\x12\x1b.product.ReservationRequest\x1a\x1c.product.ReservationResponse\x12O\n" +
+	"\x12ReleaseReservation\x12\x1b.product.ReservationRequest\x1a\x1c.product.ReservationResponse\x12Q\n" +
+	"\x0eIncrementStock\x12\x1e.product.IncrementStockRequest\x1a\x1f.product.IncrementStockResponse\x12]\n" +
+	"\x14GetStockAvailability\x12!.product.StockAvailabilityRequest\x1a\".product.StockAvailabilityResponse\x12]\n" +
+	"\x12GetProductsDetails\x12\".product.GetProductsDetailsRequest\x1a#.product.GetProductsDetailsResponse\x12U\n" +
+	"\x12GetRecommendations\x12\x1e.product.RecommendationRequest\x1a\x1f.product.RecommendationResponse\x12N\n" +
+	"\rGetCurrencies\x12\x1d.product.GetCurrenciesRequest\x1a\x1e.product.GetCurrenciesResponseB\x10Z\x0e/utils/productb\x06proto3"
 
 var (
 	file_utils_product_product_proto_rawDescOnce sync.Once
@@ -299,23 +2450,88 @@ func file_utils_product_product_proto_rawDescGZIP() []byte {
 	return file_utils_product_product_proto_rawDescData
 }
 
-var file_utils_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_utils_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_utils_product_product_proto_goTypes = []any{
-	(*GetProductDetailsResponse)(nil), // 0: product.GetProductDetailsResponse
-	(*GetProductDetailsRequest)(nil),  // 1: product.GetProductDetailsRequest
-	(*UpdateStockRequest)(nil),        // 2: product.UpdateStockRequest
-	(*UpdateStockResponse)(nil),       // 3: product.UpdateStockResponse
+	(*GetProductDetailsResponse)(nil),  // 0: product.GetProductDetailsResponse
+	(*ProductVariant)(nil),             // 1: product.ProductVariant
+	(*GetProductDetailsRequest)(nil),   // 2: product.GetProductDetailsRequest
+	(*UpdateStockRequest)(nil),         // 3: product.UpdateStockRequest
+	(*UpdateStockResponse)(nil),        // 4: product.UpdateStockResponse
+	(*SearchProductsRequest)(nil),      // 5: product.SearchProductsRequest
+	(*ProductItem)(nil),                // 6: product.ProductItem
+	(*SearchProductsResponse)(nil),     // 7: product.SearchProductsResponse
+	(*StockItem)(nil),                  // 8: product.StockItem
+	(*ReserveStockRequest)(nil),        // 9: product.ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 10: product.ReserveStockResponse
+	(*ReservationRequest)(nil),         // 11: product.ReservationRequest
+	(*ReservationResponse)(nil),        // 12: product.ReservationResponse
+	(*IncrementStockRequest)(nil),      // 13: product.IncrementStockRequest
+	(*IncrementStockResponse)(nil),     // 14: product.IncrementStockResponse
+	(*BundleComponent)(nil),            // 15: product.BundleComponent
+	(*ProductAttribute)(nil),           // 16: product.ProductAttribute
+	(*LocationStock)(nil),              // 17: product.LocationStock
+	(*StockAllocation)(nil),            // 18: product.StockAllocation
+	(*StockAvailabilityRequest)(nil),   // 19: product.StockAvailabilityRequest
+	(*StockAvailability)(nil),          // 20: product.StockAvailability
+	(*StockAvailabilityResponse)(nil),  // 21: product.StockAvailabilityResponse
+	(*ProductLookup)(nil),              // 22: product.ProductLookup
+	(*GetProductsDetailsRequest)(nil),  // 23: product.GetProductsDetailsRequest
+	(*ProductDetailsResult)(nil),       // 24: product.ProductDetailsResult
+	(*GetProductsDetailsResponse)(nil), // 25: product.GetProductsDetailsResponse
+	(*RecommendationRequest)(nil),      // 26: product.RecommendationRequest
+	(*Recommendation)(nil),             // 27: product.Recommendation
+	(*RecommendationResponse)(nil),     // 28: product.RecommendationResponse
+	(*GetCurrenciesRequest)(nil),       // 29: product.GetCurrenciesRequest
+	(*GetCurrenciesResponse)(nil),      // 30: product.GetCurrenciesResponse
+	nil,                                // 31: product.ProductVariant.OptionsEntry
+	nil,                                // 32: product.GetProductsDetailsResponse.ProductsEntry
 }
 var file_utils_product_product_proto_depIdxs = []int32{
-	1, // 0: product.Product.GetProductDetails:input_type -> product.GetProductDetailsRequest
-	2, // 1: product.Product.UpdateStock:input_type -> product.UpdateStockRequest
-	0, // 2: product.Product.GetProductDetails:output_type -> product.GetProductDetailsResponse
-	3, // 3: product.Product.UpdateStock:output_type -> product.UpdateStockResponse
-	2, // [2:4] is the sub-list for method output_type
-	0, // [0:2] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	1,  // 0: product.GetProductDetailsResponse.variant:type_name -> product.ProductVariant
+	17, // 1: product.GetProductDetailsResponse.locations:type_name -> product.LocationStock
+	16, // 2: product.GetProductDetailsResponse.attributes:type_name -> product.ProductAttribute
+	15, // 3: product.GetProductDetailsResponse.components:type_name -> product.BundleComponent
+	31, // 4: product.ProductVariant.options:type_name -> product.ProductVariant.OptionsEntry
+	18, // 5: product.UpdateStockResponse.allocations:type_name -> product.StockAllocation
+	6,  // 6: product.SearchProductsResponse.products:type_name -> product.ProductItem
+	8,  // 7: product.ReserveStockRequest.items:type_name -> product.StockItem
+	18, // 8: product.ReserveStockResponse.allocations:type_name -> product.StockAllocation
+	8,  // 9: product.IncrementStockRequest.items:type_name -> product.StockItem
+	8,  // 10: product.StockAvailabilityRequest.items:type_name -> product.StockItem
+	17, // 11: product.StockAvailability.locations:type_name -> product.LocationStock
+	20, // 12: product.StockAvailabilityResponse.items:type_name -> product.StockAvailability
+	22, // 13: product.GetProductsDetailsRequest.items:type_name -> product.ProductLookup
+	0,  // 14: product.ProductDetailsResult.product:type_name -> product.GetProductDetailsResponse
+	32, // 15: product.GetProductsDetailsResponse.products:type_name -> product.GetProductsDetailsResponse.ProductsEntry
+	27, // 16: product.RecommendationResponse.recommendations:type_name -> product.Recommendation
+	24, // 17: product.GetProductsDetailsResponse.ProductsEntry.value:type_name -> product.ProductDetailsResult
+	2,  // 18: product.Product.GetProductDetails:input_type -> product.GetProductDetailsRequest
+	3,  // 19: product.Product.UpdateStock:input_type -> product.UpdateStockRequest
+	5,  // 20: product.Product.SearchProducts:input_type -> product.SearchProductsRequest
+	9,  // 21: product.Product.ReserveStock:input_type -> product.ReserveStockRequest
+	11, // 22: product.Product.CommitReservation:input_type -> product.ReservationRequest
+	11, // 23: product.Product.ReleaseReservation:input_type -> product.ReservationRequest
+	13, // 24: product.Product.IncrementStock:input_type -> product.IncrementStockRequest
+	19, // 25: product.Product.GetStockAvailability:input_type -> product.StockAvailabilityRequest
+	23, // 26: product.Product.GetProductsDetails:input_type -> product.GetProductsDetailsRequest
+	26, // 27: product.Product.GetRecommendations:input_type -> product.RecommendationRequest
+	29, // 28: product.Product.GetCurrencies:input_type -> product.GetCurrenciesRequest
+	0,  // 29: product.Product.GetProductDetails:output_type -> product.GetProductDetailsResponse
+	4,  // 30: product.Product.UpdateStock:output_type -> product.UpdateStockResponse
+	7,  // 31: product.Product.SearchProducts:output_type -> product.SearchProductsResponse
+	10, // 32: product.Product.ReserveStock:output_type -> product.ReserveStockResponse
+	12, // 33: product.Product.CommitReservation:output_type -> product.ReservationResponse
+	12, // 34: product.Product.ReleaseReservation:output_type -> product.ReservationResponse
+	14, // 35: product.Product.IncrementStock:output_type -> product.IncrementStockResponse
+	21, // 36: product.Product.GetStockAvailability:output_type -> product.StockAvailabilityResponse
+	25, // 37: product.Product.GetProductsDetails:output_type -> product.GetProductsDetailsResponse
+	28, // 38: product.Product.GetRecommendations:output_type -> product.RecommendationResponse
+	30, // 39: product.Product.GetCurrencies:output_type -> product.GetCurrenciesResponse
+	29, // [29:40] is the sub-list for method output_type
+	18, // [18:29] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_utils_product_product_proto_init() }
@@ -323,13 +2539,14 @@ func file_utils_product_product_proto_init() {
 	if File_utils_product_product_proto != nil {
 		return
 	}
+	file_utils_product_product_proto_msgTypes[5].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_utils_product_product_proto_rawDesc), len(file_utils_product_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   33,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,8 +19,17 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Product_GetProductDetails_FullMethodName = "/product.Product/GetProductDetails"
-	Product_UpdateStock_FullMethodName       = "/product.Product/UpdateStock"
+	Product_GetProductDetails_FullMethodName    = "/product.Product/GetProductDetails"
+	Product_UpdateStock_FullMethodName          = "/product.Product/UpdateStock"
+	Product_SearchProducts_FullMethodName       = "/product.Product/SearchProducts"
+	Product_ReserveStock_FullMethodName         = "/product.Product/ReserveStock"
+	Product_CommitReservation_FullMethodName    = "/product.Product/CommitReservation"
+	Product_ReleaseReservation_FullMethodName   = "/product.Product/ReleaseReservation"
+	Product_IncrementStock_FullMethodName       = "/product.Product/IncrementStock"
+	Product_GetStockAvailability_FullMethodName = "/product.Product/GetStockAvailability"
+	Product_GetProductsDetails_FullMethodName   = "/product.Product/GetProductsDetails"
+	Product_GetRecommendations_FullMethodName   = "/product.Product/GetRecommendations"
+	Product_GetCurrencies_FullMethodName        = "/product.Product/GetCurrencies"
 )
 
 // ProductClient is the client API for Product service.
@@ -29,6 +38,15 @@ const (
 type ProductClient interface {
 	GetProductDetails(ctx context.Context, in *GetProductDetailsRequest, opts ...grpc.CallOption) (*GetProductDetailsResponse, error)
 	UpdateStock(ctx context.Context, in *UpdateStockRequest, opts ...grpc.CallOption) (*UpdateStockResponse, error)
+	SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error)
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	IncrementStock(ctx context.Context, in *IncrementStockRequest, opts ...grpc.CallOption) (*IncrementStockResponse, error)
+	GetStockAvailability(ctx context.Context, in *StockAvailabilityRequest, opts ...grpc.CallOption) (*StockAvailabilityResponse, error)
+	GetProductsDetails(ctx context.Context, in *GetProductsDetailsRequest, opts ...grpc.CallOption) (*GetProductsDetailsResponse, error)
+	GetRecommendations(ctx context.Context, in *RecommendationRequest, opts ...grpc.CallOption) (*RecommendationResponse, error)
+	GetCurrencies(ctx context.Context, in *GetCurrenciesRequest, opts ...grpc.CallOption) (*GetCurrenciesResponse, error)
 }
 
 type productClient struct {
@@ -59,12 +77,111 @@ func (c *productClient) UpdateStock(ctx context.Context, in *UpdateStockRequest,
 	return out, nil
 }
 
+func (c *productClient) SearchProducts(ctx context.Context, in *SearchProductsRequest, opts ...grpc.CallOption) (*SearchProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchProductsResponse)
+	err := c.cc.Invoke(ctx, Product_SearchProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, Product_ReserveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) CommitReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, Product_CommitReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) ReleaseReservation(ctx context.Context, in *ReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, Product_ReleaseReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) IncrementStock(ctx context.Context, in *IncrementStockRequest, opts ...grpc.CallOption) (*IncrementStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncrementStockResponse)
+	err := c.cc.Invoke(ctx, Product_IncrementStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) GetStockAvailability(ctx context.Context, in *StockAvailabilityRequest, opts ...grpc.CallOption) (*StockAvailabilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockAvailabilityResponse)
+	err := c.cc.Invoke(ctx, Product_GetStockAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) GetProductsDetails(ctx context.Context, in *GetProductsDetailsRequest, opts ...grpc.CallOption) (*GetProductsDetailsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProductsDetailsResponse)
+	err := c.cc.Invoke(ctx, Product_GetProductsDetails_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) GetRecommendations(ctx context.Context, in *RecommendationRequest, opts ...grpc.CallOption) (*RecommendationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendationResponse)
+	err := c.cc.Invoke(ctx, Product_GetRecommendations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *productClient) GetCurrencies(ctx context.Context, in *GetCurrenciesRequest, opts ...grpc.CallOption) (*GetCurrenciesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCurrenciesResponse)
+	err := c.cc.Invoke(ctx, Product_GetCurrencies_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ProductServer is the server API for Product service.
 // All implementations must embed UnimplementedProductServer
 // for forward compatibility.
 type ProductServer interface {
 	GetProductDetails(context.Context, *GetProductDetailsRequest) (*GetProductDetailsResponse, error)
 	UpdateStock(context.Context, *UpdateStockRequest) (*UpdateStockResponse, error)
+	SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error)
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	CommitReservation(context.Context, *ReservationRequest) (*ReservationResponse, error)
+	ReleaseReservation(context.Context, *ReservationRequest) (*ReservationResponse, error)
+	IncrementStock(context.Context, *IncrementStockRequest) (*IncrementStockResponse, error)
+	GetStockAvailability(context.Context, *StockAvailabilityRequest) (*StockAvailabilityResponse, error)
+	GetProductsDetails(context.Context, *GetProductsDetailsRequest) (*GetProductsDetailsResponse, error)
+	GetRecommendations(context.Context, *RecommendationRequest) (*RecommendationResponse, error)
+	GetCurrencies(context.Context, *GetCurrenciesRequest) (*GetCurrenciesResponse, error)
 	mustEmbedUnimplementedProductServer()
 }
 
@@ -81,6 +198,33 @@ func (UnimplementedProductServer) GetProductDetails(context.Context, *GetProduct
 func (UnimplementedProductServer) UpdateStock(context.Context, *UpdateStockRequest) (*UpdateStockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateStock not implemented")
 }
+func (UnimplementedProductServer) SearchProducts(context.Context, *SearchProductsRequest) (*SearchProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchProducts not implemented")
+}
+func (UnimplementedProductServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedProductServer) CommitReservation(context.Context, *ReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedProductServer) ReleaseReservation(context.Context, *ReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
+func (UnimplementedProductServer) IncrementStock(context.Context, *IncrementStockRequest) (*IncrementStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IncrementStock not implemented")
+}
+func (UnimplementedProductServer) GetStockAvailability(context.Context, *StockAvailabilityRequest) (*StockAvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockAvailability not implemented")
+}
+func (UnimplementedProductServer) GetProductsDetails(context.Context, *GetProductsDetailsRequest) (*GetProductsDetailsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProductsDetails not implemented")
+}
+func (UnimplementedProductServer) GetRecommendations(context.Context, *RecommendationRequest) (*RecommendationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecommendations not implemented")
+}
+func (UnimplementedProductServer) GetCurrencies(context.Context, *GetCurrenciesRequest) (*GetCurrenciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCurrencies not implemented")
+}
 func (UnimplementedProductServer) mustEmbedUnimplementedProductServer() {}
 func (UnimplementedProductServer) testEmbeddedByValue()                 {}
 
@@ -138,6 +282,168 @@ func _Product_UpdateStock_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Product_SearchProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).SearchProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_SearchProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).SearchProducts(ctx, req.(*SearchProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).CommitReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_ReleaseReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).ReleaseReservation(ctx, req.(*ReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_IncrementStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrementStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).IncrementStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_IncrementStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).IncrementStock(ctx, req.(*IncrementStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_GetStockAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).GetStockAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_GetStockAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).GetStockAvailability(ctx, req.(*StockAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_GetProductsDetails_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProductsDetailsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).GetProductsDetails(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_GetProductsDetails_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).GetProductsDetails(ctx, req.(*GetProductsDetailsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_GetRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecommendationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).GetRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_GetRecommendations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).GetRecommendations(ctx, req.(*RecommendationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Product_GetCurrencies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCurrenciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProductServer).GetCurrencies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Product_GetCurrencies_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProductServer).GetCurrencies(ctx, req.(*GetCurrenciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Product_ServiceDesc is the grpc.ServiceDesc for Product service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,6 +459,42 @@ var Product_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateStock",
 			Handler:    _Product_UpdateStock_Handler,
 		},
+		{
+			MethodName: "SearchProducts",
+			Handler:    _Product_SearchProducts_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _Product_ReserveStock_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _Product_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _Product_ReleaseReservation_Handler,
+		},
+		{
+			MethodName: "IncrementStock",
+			Handler:    _Product_IncrementStock_Handler,
+		},
+		{
+			MethodName: "GetStockAvailability",
+			Handler:    _Product_GetStockAvailability_Handler,
+		},
+		{
+			MethodName: "GetProductsDetails",
+			Handler:    _Product_GetProductsDetails_Handler,
+		},
+		{
+			MethodName: "GetRecommendations",
+			Handler:    _Product_GetRecommendations_Handler,
+		},
+		{
+			MethodName: "GetCurrencies",
+			Handler:    _Product_GetCurrencies_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "utils/product/product.proto",
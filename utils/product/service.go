@@ -1,6 +1,7 @@
 package product
 
 import (
+	"fmt"
 	"os"
 
 	"google.golang.org/grpc"
@@ -26,3 +27,9 @@ func Connect(connectionOption ConnectionOption) (ProductClient, *grpc.ClientConn
 
 	return NewProductClient(conn), conn
 }
+
+// LookupKey is the key GetProductsDetails uses for a product, or one of its
+// variants, in its results.
+func LookupKey(productID int64, variantID int64) string {
+	return fmt.Sprintf("%d:%d", productID, variantID)
+}
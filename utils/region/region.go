@@ -0,0 +1,32 @@
+package region
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalize upper-cases a region code and checks that it is a two letter
+// country code, optionally followed by a dash and a subdivision of up to three
+// letters or digits, as in DE or US-CA.
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	country, subdivision, hasSubdivision := strings.Cut(code, "-")
+	if len(country) != 2 || (hasSubdivision && (len(subdivision) == 0 || len(subdivision) > 3)) {
+		return "", fmt.Errorf("invalid region %q", code)
+	}
+
+	for _, r := range country + subdivision {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return "", fmt.Errorf("invalid region %q", code)
+		}
+	}
+
+	return code, nil
+}
+
+// Country is the country code of a region.
+func Country(code string) string {
+	country, _, _ := strings.Cut(code, "-")
+	return country
+}
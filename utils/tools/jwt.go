@@ -12,6 +12,9 @@ type Claims struct {
 	ID    int    `json:"id"`
 	Email string `json:"email"`
 	Role  string `json:"role"`
+	// Seller is set for approved sellers whatever their role, so an admin who
+	// sells keeps their seller access
+	Seller bool `json:"seller,omitempty"`
 	jwt.StandardClaims
 }
 
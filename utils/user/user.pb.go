@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.6
+// 	protoc-gen-go v1.36.8
 // 	protoc        v6.32.0--rc2
 // source: utils/user/user.proto
 
@@ -222,11 +222,13 @@ func (x *GetUserDetailsRequest) GetId() int64 {
 }
 
 type GetUserDetailsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
-	Phone         string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
-	Address       string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Name    string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Email   string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Phone   string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address string                 `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	// where the address is, such as DE or US-CA; empty when not given
+	Region        string `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -289,6 +291,13 @@ func (x *GetUserDetailsResponse) GetAddress() string {
 	return ""
 }
 
+func (x *GetUserDetailsResponse) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
 type GetSellerDetailsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -336,6 +345,7 @@ func (x *GetSellerDetailsRequest) GetId() int64 {
 type GetSellerDetailsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	BusinessName  string                 `protobuf:"bytes,1,opt,name=business_name,json=businessName,proto3" json:"business_name,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -377,6 +387,13 @@ func (x *GetSellerDetailsResponse) GetBusinessName() string {
 	return ""
 }
 
+func (x *GetSellerDetailsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 var File_utils_user_user_proto protoreflect.FileDescriptor
 
 const file_utils_user_user_proto_rawDesc = "" +
@@ -393,16 +410,18 @@ const file_utils_user_user_proto_rawDesc = "" +
 	"\x19CheckSellerExistsResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\"'\n" +
 	"\x15GetUserDetailsRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"r\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x8a\x01\n" +
 	"\x16GetUserDetailsResponse\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
 	"\x05phone\x18\x03 \x01(\tR\x05phone\x12\x18\n" +
-	"\aaddress\x18\x04 \x01(\tR\aaddress\")\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\")\n" +
 	"\x17GetSellerDetailsRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"?\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"[\n" +
 	"\x18GetSellerDetailsResponse\x12#\n" +
-	"\rbusiness_name\x18\x01 \x01(\tR\fbusinessName2\x9a\x02\n" +
+	"\rbusiness_name\x18\x01 \x01(\tR\fbusinessName\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency2\x9a\x02\n" +
 	"\x04User\x12^\n" +
 	"\x11CheckSellerExists\x12#.ecommerce.CheckSellerExistsRequest\x1a$.ecommerce.CheckSellerExistsResponse\x12U\n" +
 	"\x0eGetUserDetails\x12 .ecommerce.GetUserDetailsRequest\x1a!.ecommerce.GetUserDetailsResponse\x12[\n" +